@@ -649,9 +649,11 @@ func NewManager(
 	))
 
 	var cid int
+	var gtx transaction.GenesisTransaction
 	if gBlock, err := m.getBlockByHeight(0); err == nil {
 		if tx, err := gBlock.NormalTransactions().Get(0); err == nil {
-			if gtx, ok := tx.(transaction.GenesisTransaction); ok {
+			var ok bool
+			if gtx, ok = tx.(transaction.GenesisTransaction); ok {
 				cid = gtx.CID()
 			} else {
 				return nil, errors.InvalidStateError.New("InvalidGenesisTransaction")
@@ -671,6 +673,11 @@ func NewManager(
 			"InvalidChainID Database.CID=%#x Chain.CID=%#x",
 			cid, m.chain.CID())
 	}
+	if gtx != nil {
+		if err := m.checkGenesisIdentity(gtx); err != nil {
+			return nil, err
+		}
+	}
 
 	mtr, _ := m.sm.CreateInitialTransition(lastFinalized.Result(), lastFinalized.NextValidators())
 	if mtr == nil {
@@ -853,6 +860,31 @@ func (cb *channelingCB) onExecute(err error) {
 	cb.ch <- err
 }
 
+// checkGenesisIdentity checks whether the genesis transaction in the database
+// is semantically equal to the genesis of the chain.
+func (m *manager) checkGenesisIdentity(gtx transaction.GenesisTransaction) error {
+	gns := m.chain.GenesisStorage()
+	if gns == nil {
+		return nil
+	}
+	if gt, err := gns.Type(); err != nil || gt != module.GenesisNormal {
+		return nil
+	}
+	dbID, err := gtx.IdentityHash()
+	if err != nil {
+		return err
+	}
+	chainID, err := transaction.CanonicalGenesisHash(m.chain.Genesis())
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(dbID, chainID) {
+		return errors.InvalidNetworkError.Errorf(
+			"GenesisMismatch Database.ID=%#x Chain.ID=%#x", dbID, chainID)
+	}
+	return nil
+}
+
 func (m *manager) finalizeGenesis() error {
 	gns := m.chain.GenesisStorage()
 	gt, err := gns.Type()
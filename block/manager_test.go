@@ -18,6 +18,7 @@ package block_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"testing"
@@ -28,6 +29,7 @@ import (
 	"github.com/icon-project/goloop/btp/ntm"
 	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/platform/basic"
@@ -81,6 +83,38 @@ func TestBlockManager_NewManager(t *testing.T) {
 	assert.EqualValues(blk.ID(), blk2.ID())
 }
 
+type genesisChain struct {
+	*test.Chain
+	genesis []byte
+}
+
+func (c *genesisChain) Genesis() []byte {
+	return c.genesis
+}
+
+func TestBlockManager_NewManagerChecksGenesisIdentity(t *testing.T) {
+	nd := test.NewNode(t)
+	defer nd.Close()
+	assert := assert.New(t)
+
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+
+	var jso map[string]interface{}
+	assert.NoError(json.Unmarshal(nd.Chain.Genesis(), &jso))
+
+	// equivalent genesis in another format
+	reformatted, err := json.Marshal(jso)
+	assert.NoError(err)
+	_, err = block.NewManager(&genesisChain{nd.Chain, reformatted}, nil, nil)
+	assert.NoError(err)
+
+	jso["message"] = "another genesis"
+	another, err := json.Marshal(jso)
+	assert.NoError(err)
+	_, err = block.NewManager(&genesisChain{nd.Chain, another}, nil, nil)
+	assert.True(errors.InvalidNetworkError.Equals(err))
+}
+
 func TestBlockManager_ImportBlock_OK(t *testing.T) {
 	nd := test.NewNode(t)
 	defer nd.Close()
@@ -562,3 +596,101 @@ func TestManager_GetTransactionInfo(t *testing.T) {
 	assert.NoError(err)
 	assert.EqualValues(module.StatusSuccess, rct.Status())
 }
+
+func TestManager_CumulativeStepUsed(t *testing.T) {
+	assert := assert.New(t)
+	nd := test.NewNode(t)
+	defer nd.Close()
+
+	txs := []*test.Transaction{
+		nd.NewTx().SetStepUsed(100),
+		nd.NewTx().SetStepUsed(200),
+		nd.NewTx().SetStepUsed(300),
+	}
+	for _, tx := range txs {
+		_, err := nd.SM.SendTransaction(nil, 0, tx.String())
+		assert.NoError(err)
+	}
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+
+	// cumulative step used shall be reset for the next transition
+	tx4 := nd.NewTx().SetStepUsed(400)
+	nd.ProposeFinalizeBlockWithTX(consensus.NewEmptyCommitVoteList(), tx4.String())
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+
+	expected := []struct {
+		tx         *test.Transaction
+		index      int
+		used       int64
+		cumulative int64
+	}{
+		{txs[0], 0, 100, 100},
+		{txs[1], 1, 200, 300},
+		{txs[2], 2, 300, 600},
+		{tx4, 0, 400, 400},
+	}
+	for _, e := range expected {
+		ti, err := nd.BM.GetTransactionInfo(e.tx.ID())
+		assert.NoError(err)
+		assert.EqualValues(e.index, ti.Index())
+		rct, err := ti.GetReceipt()
+		assert.NoError(err)
+		assert.EqualValues(e.used, rct.StepUsed().Int64())
+		assert.EqualValues(e.cumulative, rct.CumulativeStepUsed().Int64())
+	}
+}
+
+func TestManager_PatchTransaction(t *testing.T) {
+	assert := assert.New(t)
+	nd := test.NewNode(t)
+	defer nd.Close()
+
+	patchVar, normalVar := "patch", "normal"
+	ntx := nd.NewTx().SetVarTest(&normalVar)
+	ptx := nd.NewTx().SetVarTest(&patchVar).SetPatch(true)
+	_, err := nd.SM.SendTransaction(nil, 0, ntx.String())
+	assert.NoError(err)
+	_, err = nd.SM.SendTransaction(nil, 0, ptx.String())
+	assert.NoError(err)
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+
+	blk, err := nd.BM.GetBlockByHeight(1)
+	assert.NoError(err)
+	ptx2, err := blk.PatchTransactions().Get(0)
+	assert.NoError(err)
+	assert.EqualValues(ptx.ID(), ptx2.ID())
+	ntx2, err := blk.NormalTransactions().Get(0)
+	assert.NoError(err)
+	assert.EqualValues(ntx.ID(), ntx2.ID())
+
+	ti, err := nd.BM.GetTransactionInfo(ptx.ID())
+	assert.NoError(err)
+	assert.EqualValues(module.TransactionGroupPatch, ti.Group())
+	assert.EqualValues(0, ti.Index())
+	rct, err := ti.GetReceipt()
+	assert.NoError(err)
+	assert.EqualValues(module.StatusSuccess, rct.Status())
+
+	ti, err = nd.BM.GetTransactionInfo(ntx.ID())
+	assert.NoError(err)
+	assert.EqualValues(module.TransactionGroupNormal, ti.Group())
+	rct, err = ti.GetReceipt()
+	assert.NoError(err)
+	assert.EqualValues(module.StatusSuccess, rct.Status())
+
+	// separate receipt lists for patch and normal transactions
+	last, err := nd.BM.GetLastBlock()
+	assert.NoError(err)
+	sm := nd.SM.(*test.ServiceManager)
+	prl, err := sm.ReceiptListFromResult(last.Result(), module.TransactionGroupPatch)
+	assert.NoError(err)
+	nrl, err := sm.ReceiptListFromResult(last.Result(), module.TransactionGroupNormal)
+	assert.NoError(err)
+	assert.NotEqual(prl.Hash(), nrl.Hash())
+
+	// patch transaction is executed before normal transaction
+	v, err := sm.GetVarTest(last.Result())
+	assert.NoError(err)
+	assert.Equal(normalVar, v)
+}
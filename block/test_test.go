@@ -385,6 +385,10 @@ func (tr *testTransition) NormalReceipts() module.ReceiptList {
 	return nil
 }
 
+func (tr *testTransition) Receipts() module.ReceiptList {
+	return nil
+}
+
 func (tr *testTransition) EffectiveTransactions() *testTransactionList {
 	if tr.patchTransactions.Len() > 0 {
 		return tr.patchTransactions
@@ -19,6 +19,7 @@ package base
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/log"
@@ -77,4 +78,5 @@ type Chain interface {
 	Regulator() module.Regulator
 	Wallet() module.Wallet
 	WalletFor(dsa string) module.BaseWallet
+	VoteTimestampSkew() time.Duration
 }
@@ -270,6 +270,13 @@ func (c *singleChain) ValidateTxOnSend() bool {
 	return c.cfg.ValidateTxOnSend
 }
 
+func (c *singleChain) VoteTimestampSkew() time.Duration {
+	if c.cfg.VoteTSSkew > 0 {
+		return time.Duration(c.cfg.VoteTSSkew) * time.Millisecond
+	}
+	return 0
+}
+
 func (c *singleChain) State() (string, int64, error) {
 	c.mtx.RLock()
 	defer c.mtx.RUnlock()
@@ -484,6 +491,17 @@ func (c *singleChain) _init() error {
 	return nil
 }
 
+// serviceManagerOptions returns options of the service manager from the
+// chain configuration.
+func (c *singleChain) serviceManagerOptions() []service.ManagerOption {
+	return []service.ManagerOption{
+		service.WithMaxTxPerBlock(c.cfg.MaxTxPerBlock),
+		service.WithMaxBlockSteps(c.cfg.MaxBlockSteps),
+		service.WithMaxBlockTxBytes(c.cfg.MaxBlockTxBytes),
+		service.WithTransactionTimeout(c.TransactionTimeout()),
+	}
+}
+
 func (c *singleChain) prepareManagers() error {
 	pr := network.PeerRoleFlag(c.cfg.Role)
 	c.nm = network.NewManager(c, c.nt, c.cfg.SeedAddr, pr.ToRoles()...)
@@ -491,7 +509,8 @@ func (c *singleChain) prepareManagers() error {
 	chainDir := c.cfg.AbsBaseDir()
 	ContractDir := path.Join(chainDir, DefaultContractDir)
 	var err error
-	c.sm, err = service.NewManager(c, c.nm, c.pm, c.plt, ContractDir)
+	c.sm, err = service.NewManager(c, c.nm, c.pm, c.plt, ContractDir,
+		c.serviceManagerOptions()...)
 	if err != nil {
 		return err
 	}
@@ -44,11 +44,14 @@ type Config struct {
 	NormalTxPoolSize int    `json:"normal_tx_pool,omitempty"`
 	PatchTxPoolSize  int    `json:"patch_tx_pool,omitempty"`
 	MaxBlockTxBytes  int    `json:"max_block_tx_bytes,omitempty"`
+	MaxTxPerBlock    int    `json:"max_tx_per_block,omitempty"`
+	MaxBlockSteps    int64  `json:"max_block_steps,omitempty"`
 	NodeCache        string `json:"node_cache,omitempty"`
 	AutoStart        bool   `json:"auto_start,omitempty"`
 	ChildrenLimit    *int   `json:"children_limit,omitempty"`
 	NephewsLimit     *int   `json:"nephews_limit,omitempty"`
 	ValidateTxOnSend bool   `json:"validate_tx_on_send,omitempty"`
+	VoteTSSkew       int64  `json:"vote_ts_skew,omitempty"`
 
 	// runtime
 	Channel        string `json:"channel"`
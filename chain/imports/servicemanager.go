@@ -29,9 +29,9 @@ type managerForImport struct {
 
 func NewServiceManagerForImport(chain module.Chain, nm module.NetworkManager,
 	eem eeproxy.Manager, plt base.Platform, contractDir string, lcDBDir string,
-	height int64, cb ImportCallback,
+	height int64, cb ImportCallback, opts ...service.ManagerOption,
 ) (module.ServiceManager, module.Timestamper, error) {
-	manager, err := service.NewManager(chain, nm, eem, plt, contractDir)
+	manager, err := service.NewManager(chain, nm, eem, plt, contractDir, opts...)
 	if err != nil {
 		return nil, nil, err
 	}
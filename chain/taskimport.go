@@ -105,7 +105,7 @@ func (t *taskImport) _import() error {
 	var err error
 	var ts module.Timestamper
 	c.sm, ts, err = imports.NewServiceManagerForImport(c, c.nm, c.pm, c.plt,
-		ContractDir, t.src, t.height, t)
+		ContractDir, t.src, t.height, t, c.serviceManagerOptions()...)
 	if err != nil {
 		return err
 	}
@@ -192,7 +192,8 @@ func (t *taskReset) _prepareBlocks(height int64, blockHash []byte) (module.Block
 
 	ContractDir := path.Join(chainDir, DefaultContractDir)
 	var err error
-	c.sm, err = service.NewManager(c, c.nm, c.pm, c.plt, ContractDir)
+	c.sm, err = service.NewManager(c, c.nm, c.pm, c.plt, ContractDir,
+		c.serviceManagerOptions()...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -127,6 +127,10 @@ func (c *JsonRpcClient) DoURL(url string, method string, reqPtr, respPtr interfa
 				err = NewHttpError(resp)
 				return
 			}
+			if jrResp == nil || jrResp.Error == nil {
+				err = fmt.Errorf("http-status(%s) without error object", resp.Status)
+				return
+			}
 			err = jrResp.Error
 			return
 		}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+func newTestServer(status int, contentType, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerContentType, contentType)
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+}
+
+func TestJsonRpcClient_Do(t *testing.T) {
+	large := strings.Repeat("a", 3*1024*1024)
+	s := newTestServer(http.StatusOK, typeApplicationJSON,
+		`{"jsonrpc":"2.0","id":1,"result":"`+large+`"}`)
+	defer s.Close()
+
+	var result string
+	c := NewJsonRpcClient(http.DefaultClient, s.URL)
+	_, err := c.Do("test", nil, &result)
+	assert.NoError(t, err)
+	assert.Equal(t, large, result)
+}
+
+func TestJsonRpcClient_DoWithError(t *testing.T) {
+	errBody := `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"MethodNotFound"}}`
+	for _, status := range []int{http.StatusOK, http.StatusBadRequest} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			s := newTestServer(status, typeApplicationJSON, errBody)
+			defer s.Close()
+
+			_, err := NewJsonRpcClient(http.DefaultClient, s.URL).Do("test", nil, nil)
+			jerr, ok := err.(*jsonrpc.Error)
+			if assert.True(t, ok) {
+				assert.Equal(t, jsonrpc.ErrorCodeMethodNotFound, jerr.Code)
+			}
+		})
+	}
+
+	// error status without error object
+	s := newTestServer(http.StatusInternalServerError, typeApplicationJSON,
+		`{"jsonrpc":"2.0","id":1}`)
+	defer s.Close()
+	_, err := NewJsonRpcClient(http.DefaultClient, s.URL).Do("test", nil, nil)
+	assert.Error(t, err)
+	_, ok := err.(*jsonrpc.Error)
+	assert.False(t, ok)
+
+	// non-JSON body is kept in the error
+	s2 := newTestServer(http.StatusBadGateway, "text/plain", "upstream failure")
+	defer s2.Close()
+	_, err = NewJsonRpcClient(http.DefaultClient, s2.URL).Do("test", nil, nil)
+	herr, ok := err.(*HttpError)
+	if assert.True(t, ok) {
+		assert.Equal(t, "upstream failure", herr.Response())
+	}
+}
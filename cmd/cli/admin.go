@@ -128,6 +128,8 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 			param.NormalTxPoolSize, _ = fs.GetInt("normal_tx_pool")
 			param.PatchTxPoolSize, _ = fs.GetInt("patch_tx_pool")
 			param.MaxBlockTxBytes, _ = fs.GetInt("max_block_tx_bytes")
+			param.MaxTxPerBlock, _ = fs.GetInt("max_tx_per_block")
+			param.MaxBlockSteps, _ = fs.GetInt64("max_block_steps")
 			param.NodeCache, _ = fs.GetString("node_cache")
 			param.Channel, _ = fs.GetString("channel")
 			param.SecureSuites, _ = fs.GetString("secure_suites")
@@ -145,6 +147,7 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 				param.NephewsLimit = &nephewsLimit
 			}
 			param.ValidateTxOnSend, _ = fs.GetBool("validate_tx_on_send")
+			param.VoteTSSkew, _ = fs.GetInt64("vote_ts_skew")
 
 			var buf *bytes.Buffer
 			if len(genesisZip) > 0 {
@@ -189,6 +192,8 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags.Int("normal_tx_pool", 0, "Size of normal transaction pool")
 	joinFlags.Int("patch_tx_pool", 0, "Size of patch transaction pool")
 	joinFlags.Int("max_block_tx_bytes", 0, "Max size of transactions in a block")
+	joinFlags.Int("max_tx_per_block", 0, "Max number of transactions in a block (0: no additional limit)")
+	joinFlags.Int64("max_block_steps", 0, "Max sum of step limits of transactions in a block (0: no limit)")
 	joinFlags.String("node_cache", chain.NodeCacheDefault, "Node cache (none,small,large)")
 	joinFlags.String("channel", "", "Channel")
 	joinFlags.String("secure_suites", "none,tls,ecdhe",
@@ -202,6 +207,7 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags.Int("children_limit", -1, "Maximum number of child connections (-1: uses system default value)")
 	joinFlags.Int("nephews_limit", -1, "Maximum number of nephew connections (-1: uses system default value)")
 	joinFlags.Bool("validate_tx_on_send", false, "Validate transaction on send")
+	joinFlags.Int64("vote_ts_skew", 0, "Maximum skew of vote timestamps in milli-second (0: disable)")
 
 	leaveCmd := &cobra.Command{
 		Use:   "leave CID",
@@ -138,8 +138,11 @@ func main() {
 	flag.IntVar(&cfg.NormalTxPoolSize, "normal_tx_pool", 0, "Normal transaction pool size")
 	flag.IntVar(&cfg.PatchTxPoolSize, "patch_tx_pool", 0, "Patch transaction pool size")
 	flag.IntVar(&cfg.MaxBlockTxBytes, "max_block_tx_bytes", 0, "Maximum size of transactions in a block")
+	flag.IntVar(&cfg.MaxTxPerBlock, "max_tx_per_block", 0, "Maximum number of transactions in a block (0: no additional limit)")
+	flag.Int64Var(&cfg.MaxBlockSteps, "max_block_steps", 0, "Maximum sum of step limits of transactions in a block (0: no limit)")
 	flag.StringVar(&cfg.NodeCache, "node_cache", chain.NodeCacheDefault, "Node cache (none,small,large)")
 	flag.BoolVar(&cfg.ValidateTxOnSend, "validate_tx_on_send", false, "Validate transaction on send")
+	flag.Int64Var(&cfg.VoteTSSkew, "vote_ts_skew", 0, "Maximum skew of vote timestamps in milli-second (0: disable)")
 	cfg.ChildrenLimit = flag.Int("children_limit", -1, "Maximum number of child connections (-1: uses system default value)")
 	cfg.NephewsLimit = flag.Int("nephews_limit", -1, "Maximum number of nephew connections (-1: uses system default value)")
 	flag.StringVar(&cfg.LogLevel, "log_level", "debug", "Main log level")
@@ -140,6 +140,21 @@ func (sig *Signature) SerializeRSV() ([]byte, error) {
 	return s, nil
 }
 
+// IsLowS returns whether S value of the signature is in the lower half of
+// the curve order. A signature with higher S value is a malleated form of
+// the other one, and it also recovers the same public key.
+func (sig *Signature) IsLowS() bool {
+	rs, err := sig.SerializeRS()
+	if err != nil {
+		return false
+	}
+	var s secp256k1.ModNScalar
+	if overflow := s.SetByteSlice(rs[32:]); overflow {
+		return false
+	}
+	return !s.IsOverHalfOrder()
+}
+
 // RecoverPublicKey recovers a public key from the hash of message and its signature.
 func (sig *Signature) RecoverPublicKey(hash []byte) (*PublicKey, error) {
 	if !sig.HasV() {
@@ -0,0 +1,65 @@
+package consensus
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+// VerifyCommits verifies commit votes of blocks in parallel with up to
+// workers goroutines, or as many as CPUs if workers is not positive.
+// votes[i] is the commit votes for blocks[i] which should be voted by
+// validators[i]. It returns an error for each block at the same index as the
+// block, which is nil if the block is verified.
+func VerifyCommits(
+	blocks []module.Block, votes []module.CommitVoteSet, validators []module.ValidatorList, workers int,
+) []error {
+	errs := make([]error, len(blocks))
+	if len(votes) != len(blocks) || len(validators) != len(blocks) {
+		err := errors.IllegalArgumentError.Errorf(
+			"LengthMismatch(blocks=%d,votes=%d,validators=%d)",
+			len(blocks), len(votes), len(validators))
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(blocks) {
+		workers = len(blocks)
+	}
+
+	// each worker takes the next block, and stores the result at its index
+	next := int64(-1)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(blocks) {
+					return
+				}
+				errs[i] = verifyCommit(blocks[i], votes[i], validators[i])
+			}
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+func verifyCommit(blk module.Block, votes module.CommitVoteSet, validators module.ValidatorList) error {
+	if blk == nil || votes == nil {
+		return errors.IllegalArgumentError.New("NoBlockOrVotes")
+	}
+	if _, err := votes.VerifyBlock(blk, validators); err != nil {
+		return errors.Wrapf(err, "InvalidCommit(height=%d,id=%x)", blk.Height(), blk.ID())
+	}
+	return nil
+}
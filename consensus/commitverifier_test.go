@@ -0,0 +1,136 @@
+package consensus
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+type testCommitBlock struct {
+	module.Block
+	height int64
+	id     []byte
+}
+
+func (b *testCommitBlock) Height() int64 {
+	return b.height
+}
+
+func (b *testCommitBlock) ID() []byte {
+	return b.id
+}
+
+type testCommits struct {
+	blocks     []module.Block
+	votes      []module.CommitVoteSet
+	validators []module.ValidatorList
+}
+
+func newTestCommits(t testing.TB, n int) *testCommits {
+	var wallets []module.Wallet
+	var vals []module.Validator
+	for i := 0; i < 4; i++ {
+		w := wallet.New()
+		v, err := state.ValidatorFromAddress(w.Address())
+		assert.NoError(t, err)
+		wallets = append(wallets, w)
+		vals = append(vals, v)
+	}
+	validators, err := state.ValidatorSnapshotFromSlice(db.NewMapDB(), vals)
+	assert.NoError(t, err)
+
+	tc := new(testCommits)
+	for i := 0; i < n; i++ {
+		height := int64(i + 1)
+		blk := &testCommitBlock{
+			height: height,
+			id:     crypto.SHA3Sum256([]byte(fmt.Sprintf("block%d", height))),
+		}
+		psid := &PartSetID{Count: 1, Hash: crypto.SHA3Sum256(blk.id)}
+		var msgs []*VoteMessage
+		for j, w := range wallets[:3] {
+			msgs = append(msgs, NewVoteMessage(w, VoteTypePrecommit, height, 0,
+				blk.id, psid, int64(j), nil, nil, 0))
+		}
+		vl, err := newCommitVoteList(nil, msgs)
+		assert.NoError(t, err)
+		tc.blocks = append(tc.blocks, blk)
+		tc.votes = append(tc.votes, vl)
+		tc.validators = append(tc.validators, validators)
+	}
+	return tc
+}
+
+func TestVerifyCommits(t *testing.T) {
+	tc := newTestCommits(t, 50)
+
+	// votes for other blocks
+	bad := map[int]bool{3: true, 17: true, 42: true}
+	for i := range bad {
+		tc.votes[i] = tc.votes[i+1]
+	}
+
+	for _, workers := range []int{0, 1, 4, 100} {
+		t.Run(fmt.Sprintf("Workers%d", workers), func(t *testing.T) {
+			errs := VerifyCommits(tc.blocks, tc.votes, tc.validators, workers)
+			assert.Len(t, errs, len(tc.blocks))
+			for i, err := range errs {
+				if bad[i] {
+					assert.Error(t, err, "index=%d", i)
+				} else {
+					assert.NoError(t, err, "index=%d", i)
+				}
+			}
+		})
+	}
+
+	// no votes
+	votes := append([]module.CommitVoteSet(nil), tc.votes...)
+	votes[0] = nil
+	errs := VerifyCommits(tc.blocks, votes, tc.validators, 4)
+	assert.Error(t, errs[0])
+	assert.NoError(t, errs[1])
+
+	// length mismatch
+	errs = VerifyCommits(tc.blocks, tc.votes[1:], tc.validators, 4)
+	assert.Len(t, errs, len(tc.blocks))
+	for _, err := range errs {
+		assert.Error(t, err)
+	}
+
+	assert.Empty(t, VerifyCommits(nil, nil, nil, 4))
+}
+
+func BenchmarkVerifyCommits(b *testing.B) {
+	tc := newTestCommits(b, 1000)
+	for _, workers := range []int{1, 0} {
+		name := "Sequential"
+		if workers != 1 {
+			name = "Parallel"
+		}
+		b.Run(name, func(b *testing.B) {
+			// recover public keys for each run
+			size := PublicKeyCacheSize
+			PublicKeyCacheSize = 0
+			defer func() {
+				PublicKeyCacheSize = size
+			}()
+			recoveredPublicKeys = newPublicKeyCache()
+
+			for i := 0; i < b.N; i++ {
+				for _, err := range VerifyCommits(tc.blocks, tc.votes, tc.validators, workers) {
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math/big"
 	"sort"
 
 	"github.com/icon-project/goloop/btp/ntm"
@@ -39,19 +40,88 @@ func (bvl *blockCommitVoteList) BlockVoteSetBytes() []byte {
 }
 
 func (bvl *blockCommitVoteList) VerifyBlock(block module.BlockData, validators module.ValidatorList) ([]bool, error) {
-	if block.Height() == 0 || validators == nil {
+	return bvl.VerifyWithValidators(block.Height(), block.ID(), nil, validators)
+}
+
+// VerifyWithValidators verifies the votes for the block with given height and
+// ID against validators without the block itself. It's useful to verify
+// historical commits with a known validator set. If psid is not nil, it
+// should match the part set ID of the votes.
+func (bvl *blockCommitVoteList) VerifyWithValidators(
+	height int64, blockID []byte, psid *PartSetID, validators module.ValidatorList,
+) ([]bool, error) {
+	vset, err := bvl.voters(height, blockID, psid, validators)
+	if err != nil || vset == nil {
+		return nil, err
+	}
+	if enoughVote(len(bvl.Items), validators.Len()) {
+		return vset, nil
+	}
+	return nil, errors.Errorf("votes(%d) <= 2/3 of validators(%d)", len(bvl.Items), validators.Len())
+}
+
+// VerifyByPower verifies the votes for the block like VerifyBlock, but it
+// requires more than 2/3 of total voting power instead of more than 2/3 of
+// validators. powers maps an index of validators to its voting power, and
+// validators not in powers have no voting power.
+func (bvl *blockCommitVoteList) VerifyByPower(
+	block module.BlockData, validators module.ValidatorList, powers map[int]*big.Int,
+) ([]bool, error) {
+	vset, err := bvl.voters(block.Height(), block.ID(), nil, validators)
+	if err != nil || vset == nil {
+		return nil, err
+	}
+	total := new(big.Int)
+	voted := new(big.Int)
+	for idx, power := range powers {
+		if idx < 0 || idx >= len(vset) {
+			return nil, errors.Errorf("bad validator index %d for power", idx)
+		}
+		if power == nil || power.Sign() < 0 {
+			return nil, errors.Errorf("bad power %v for validator index %d", power, idx)
+		}
+		total.Add(total, power)
+		if vset[idx] {
+			voted.Add(voted, power)
+		}
+	}
+	if total.Sign() == 0 {
+		return nil, errors.Errorf("no voting power for validators")
+	}
+	if enoughPower(voted, total) {
+		return vset, nil
+	}
+	return nil, errors.Errorf("voted power(%s) <= 2/3 of total power(%s)", voted, total)
+}
+
+// voters returns which validators signed the votes.
+// It returns nil without error if no votes are required. Voters of vote lists
+// verified before are returned from verifiedVoteLists without recovering
+// signatures.
+func (bvl *blockCommitVoteList) voters(
+	height int64, blockID []byte, psid *PartSetID, validators module.ValidatorList,
+) ([]bool, error) {
+	if height == 0 || validators == nil {
 		if len(bvl.Items) == 0 {
 			return nil, nil
 		} else {
 			return nil, errors.Errorf("voters for height 0 or nil validator list\n")
 		}
 	}
+	if psid != nil && !psid.Equal(bvl.BlockPartSetIDAndAppData.ID()) {
+		return nil, errors.Errorf("bad part set ID %v in vote list, expected %v",
+			bvl.BlockPartSetIDAndAppData.ID(), psid)
+	}
+	key := verifiedCacheKey(bvl, height, blockID, validators)
+	if vset := verifiedVoteLists.Get(key); vset != nil {
+		return vset, nil
+	}
 	vset := make([]bool, validators.Len())
 	msg := newVoteMessage()
-	msg.Height = block.Height()
+	msg.Height = height
 	msg.Round = bvl.Round
 	msg.Type = VoteTypePrecommit
-	msg.SetRoundDecision(block.ID(), bvl.BlockPartSetIDAndAppData, nil)
+	msg.SetRoundDecision(blockID, bvl.BlockPartSetIDAndAppData, nil)
 	for i, item := range bvl.Items {
 		msg.Timestamp = item.Timestamp
 		msg.setSignature(item.Signature)
@@ -64,10 +134,8 @@ func (bvl *blockCommitVoteList) VerifyBlock(block module.BlockData, validators m
 		}
 		vset[index] = true
 	}
-	if enoughVote(len(bvl.Items), validators.Len()) {
-		return vset, nil
-	}
-	return nil, errors.Errorf("votes(%d) <= 2/3 of validators(%d)", len(bvl.Items), validators.Len())
+	verifiedVoteLists.Put(key, vset)
+	return vset, nil
 }
 
 func enoughVote(voted int, voters int) bool {
@@ -78,6 +146,17 @@ func enoughVote(voted int, voters int) bool {
 	return voted > twoThirds
 }
 
+// enoughPower returns whether voted is more than 2/3 of total. Nothing is
+// enough for validators without voting power.
+func enoughPower(voted, total *big.Int) bool {
+	if total.Sign() <= 0 {
+		return false
+	}
+	v3 := new(big.Int).Mul(voted, big.NewInt(3))
+	t2 := new(big.Int).Mul(total, big.NewInt(2))
+	return v3.Cmp(t2) > 0
+}
+
 func (bvl *blockCommitVoteList) String() string {
 	return fmt.Sprintf("VoteList(R=%d,ID=%v,len(Signs)=%d)",
 		bvl.Round, bvl.BlockPartSetIDAndAppData, len(bvl.Items))
@@ -274,10 +353,21 @@ func newCommitVoteList(
 	vl.Items = make([]blockCommitVoteItem, l)
 	rdd := msgs[0].RoundDecisionDigest()
 	for i := 0; i < l; i++ {
+		if err := msgs[i].verifyCanonical(); err != nil {
+			return nil, errors.Wrapf(err, "NewVoteList: bad signature i=%d", i)
+		}
 		vl.Items[i] = blockCommitVoteItem{
 			msgs[i].Timestamp,
 			msgs[i].Signature,
 		}
+		if !msgs[0].BlockPartSetIDAndNTSVoteCount.Equal(msgs[i].BlockPartSetIDAndNTSVoteCount) {
+			return nil, errors.Errorf(
+				"NewVoteList: bad BlockPartSetID in messages msgs[0].BPSID=%v msgs[i].BPSID=%v i=%d",
+				msgs[0].BlockPartSetIDAndNTSVoteCount.ID(),
+				msgs[i].BlockPartSetIDAndNTSVoteCount.ID(),
+				i,
+			)
+		}
 		if !bytes.Equal(rdd, msgs[i].RoundDecisionDigest()) {
 			return nil, errors.Errorf(
 				"NewVoteList: bad RDD in messages msgs[0].BlockID=%s msgs[i].BlockID=%s i=%d",
@@ -313,8 +403,20 @@ func NewCommitVoteList(pcm module.BTPProofContextMap, msgs ...*VoteMessage) modu
 	return cvl
 }
 
+// emptyCommitVoteListBytes returns the canonical encoding of the empty commit
+// vote list used for the block at height 1. Round is zero, and both
+// BlockPartSetIDAndAppData and Items are encoded as RLP null, so its hash is
+// always a84151ceae94b2def049782c6c026794345e340bbf190b64a47e69ee097a1006.
+// It returns a new slice for each call, so callers may modify it.
+func emptyCommitVoteListBytes() []byte {
+	return []byte{0xc5, 0x00, 0xf8, 0x00, 0xf8, 0x00}
+}
+
+// NewEmptyCommitVoteList returns the empty commit vote list. Its Bytes()
+// returns emptyCommitVoteListBytes regardless of the codec in use.
 func NewEmptyCommitVoteList() module.CommitVoteSet {
 	cvl, _ := newCommitVoteList(nil, nil)
+	cvl.bytes = emptyCommitVoteListBytes()
 	return cvl
 }
 
@@ -1,9 +1,18 @@
 package consensus
 
 import (
+	"encoding/hex"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
 )
 
 func TestCommitVoteList_Timestamp(t *testing.T) {
@@ -47,3 +56,316 @@ func TestCommitVoteList_enoughVote(t *testing.T) {
 	assert.False(t, enoughVote(4, 7))
 	assert.True(t, enoughVote(5, 7))
 }
+
+func TestCommitVoteList_Empty(t *testing.T) {
+	const emptyHash = "a84151ceae94b2def049782c6c026794345e340bbf190b64a47e69ee097a1006"
+
+	vl := NewEmptyCommitVoteList()
+	assert.Equal(t, emptyCommitVoteListBytes(), vl.Bytes())
+	assert.Equal(t, emptyHash, hex.EncodeToString(vl.Hash()))
+
+	// encoding by codec matches the canonical bytes
+	cvl, err := newCommitVoteList(nil, []*VoteMessage{})
+	assert.NoError(t, err)
+	assert.Equal(t, emptyCommitVoteListBytes(), cvl.Bytes())
+	assert.Equal(t, emptyCommitVoteListBytes(), NewCommitVoteSetFromBytes(nil).Bytes())
+
+	// round trip keeps the canonical bytes
+	vl2 := NewCommitVoteSetFromBytes(vl.Bytes())
+	assert.NotNil(t, vl2)
+	assert.Equal(t, emptyCommitVoteListBytes(), vl2.Bytes())
+	assert.Equal(t, emptyHash, hex.EncodeToString(vl2.Hash()))
+
+	// bytes aren't shared between lists
+	vl.Bytes()[0] = 0
+	assert.Equal(t, emptyHash, hex.EncodeToString(NewEmptyCommitVoteList().Hash()))
+}
+
+func TestCommitVoteList_PartSetIDMismatch(t *testing.T) {
+	blockID := crypto.SHA3Sum256([]byte("block"))
+	psid := &PartSetID{Count: 1, Hash: crypto.SHA3Sum256([]byte("parts"))}
+	psid2 := &PartSetID{Count: 2, Hash: psid.Hash}
+
+	w1, w2 := wallet.New(), wallet.New()
+	m1 := NewVoteMessage(w1, VoteTypePrecommit, 10, 0, blockID, psid, 1, nil, nil, 0)
+	m2 := NewVoteMessage(w2, VoteTypePrecommit, 10, 0, blockID, psid, 2, nil, nil, 0)
+	m3 := NewVoteMessage(w2, VoteTypePrecommit, 10, 0, blockID, psid2, 2, nil, nil, 0)
+
+	vl, err := newCommitVoteList(nil, []*VoteMessage{m1, m2})
+	assert.NoError(t, err)
+	assert.Len(t, vl.Items, 2)
+
+	assert.NotPanics(t, func() {
+		vl, err = newCommitVoteList(nil, []*VoteMessage{m1, m3})
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "BlockPartSetID")
+	assert.Nil(t, vl)
+	assert.Nil(t, NewCommitVoteList(nil, m1, m3).(*CommitVoteList))
+
+	assert.True(t, psid.WithAppData(0).Equal(psid.WithAppData(0)))
+	assert.False(t, psid.WithAppData(0).Equal(psid.WithAppData(1)))
+	assert.False(t, psid.WithAppData(0).Equal(psid2.WithAppData(0)))
+	assert.False(t, psid.WithAppData(0).Equal(nil))
+	assert.True(t, (*PartSetIDAndAppData)(nil).Equal(nil))
+}
+
+func TestNewVoteListForTest(t *testing.T) {
+	blockID := crypto.SHA3Sum256([]byte("block"))
+	psid := &PartSetID{Count: 1, Hash: crypto.SHA3Sum256([]byte("parts"))}
+	psid2 := &PartSetID{Count: 2, Hash: psid.Hash}
+
+	w1, w2 := wallet.New(), wallet.New()
+	m1 := NewVoteMessage(w1, VoteTypePrecommit, 10, 0, blockID, psid, 1, nil, nil, 0)
+	m2 := NewVoteMessage(w2, VoteTypePrecommit, 10, 0, blockID, psid2, 2, nil, nil, 0)
+
+	// inconsistent votes can't make a vote list with NewCommitVoteList
+	assert.Nil(t, NewCommitVoteList(nil, m1, m2).(*CommitVoteList))
+
+	cvs := NewVoteListForTest(1, psid, []common.Signature{m1.Signature, m2.Signature})
+	vl, ok := cvs.(*CommitVoteList)
+	assert.True(t, ok)
+	assert.EqualValues(t, 1, vl.Round)
+	assert.True(t, psid.WithAppData(0).Equal(vl.BlockPartSetIDAndAppData))
+	assert.Len(t, vl.Items, 2)
+	assert.Equal(t, m2.Signature, vl.Items[1].Signature)
+
+	cvs2 := NewCommitVoteSetFromBytes(cvs.Bytes())
+	assert.Equal(t, cvs.Bytes(), cvs2.Bytes())
+	assert.Equal(t, cvs.Hash(), cvs2.Hash())
+}
+
+func TestCommitVoteList_VerifyWithValidators(t *testing.T) {
+	const height = 10
+	blockID := crypto.SHA3Sum256([]byte("block"))
+	psid := &PartSetID{Count: 1, Hash: crypto.SHA3Sum256([]byte("parts"))}
+
+	var wallets []module.Wallet
+	var vals []module.Validator
+	for i := 0; i < 4; i++ {
+		w := wallet.New()
+		v, err := state.ValidatorFromAddress(w.Address())
+		assert.NoError(t, err)
+		wallets = append(wallets, w)
+		vals = append(vals, v)
+	}
+	validators, err := state.ValidatorSnapshotFromSlice(db.NewMapDB(), vals)
+	assert.NoError(t, err)
+
+	newVoteList := func(ws []module.Wallet) *CommitVoteList {
+		var msgs []*VoteMessage
+		for i, w := range ws {
+			msgs = append(msgs, NewVoteMessage(w, VoteTypePrecommit, height, 0,
+				blockID, psid, int64(i), nil, nil, 0))
+		}
+		vl, err := newCommitVoteList(nil, msgs)
+		assert.NoError(t, err)
+		return vl
+	}
+
+	vl := newVoteList(wallets[:3])
+	voted, err := vl.VerifyWithValidators(height, blockID, psid, validators)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, true, true, false}, voted)
+
+	voted, err = vl.VerifyWithValidators(height, blockID, nil, validators)
+	assert.NoError(t, err)
+	assert.Len(t, voted, 4)
+
+	// wrong part set ID
+	_, err = vl.VerifyWithValidators(height, blockID,
+		&PartSetID{Count: 2, Hash: psid.Hash}, validators)
+	assert.Error(t, err)
+
+	// votes for another block or height
+	_, err = vl.VerifyWithValidators(height, crypto.SHA3Sum256([]byte("other")), psid, validators)
+	assert.Error(t, err)
+	_, err = vl.VerifyWithValidators(height+1, blockID, psid, validators)
+	assert.Error(t, err)
+
+	// not enough votes
+	_, err = newVoteList(wallets[:2]).VerifyWithValidators(height, blockID, psid, validators)
+	assert.Error(t, err)
+
+	// empty vote list with no validators
+	voted, err = NewEmptyCommitVoteList().(*CommitVoteList).VerifyWithValidators(0, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, voted)
+}
+
+func TestCommitVoteList_HighS(t *testing.T) {
+	const height = 10
+	blockID := crypto.SHA3Sum256([]byte("block"))
+	psid := &PartSetID{Count: 1, Hash: crypto.SHA3Sum256([]byte("parts"))}
+
+	var msgs []*VoteMessage
+	var vals []module.Validator
+	for i := 0; i < 4; i++ {
+		w := wallet.New()
+		v, err := state.ValidatorFromAddress(w.Address())
+		assert.NoError(t, err)
+		vals = append(vals, v)
+		msgs = append(msgs, NewVoteMessage(w, VoteTypePrecommit, height, 0,
+			blockID, psid, int64(i), nil, nil, 0))
+	}
+	validators, err := state.ValidatorSnapshotFromSlice(db.NewMapDB(), vals)
+	assert.NoError(t, err)
+
+	// commit stored with a high S signature is verified as it was
+	vl, err := newCommitVoteList(nil, msgs)
+	assert.NoError(t, err)
+	vl.Items[1].Signature = malleateSignature(t, vl.Items[1].Signature)
+	stored := NewCommitVoteSetFromBytes(vl.Bytes())
+	voted, err := stored.VerifyBlock(&testBlockData{height: height, id: blockID}, validators)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, true, true, true}, voted)
+	voted, err = stored.(*CommitVoteList).VerifyWithValidators(height, blockID, psid, validators)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, true, true, true}, voted)
+
+	// new commit can't be made with a high S signature
+	msgs[1].setSignature(malleateSignature(t, msgs[1].Signature))
+	_, err = newCommitVoteList(nil, msgs)
+	assert.ErrorIs(t, err, errNonCanonicalSignature)
+}
+
+type testBlockData struct {
+	module.BlockData
+	height int64
+	id     []byte
+}
+
+func (b *testBlockData) Height() int64 {
+	return b.height
+}
+
+func (b *testBlockData) ID() []byte {
+	return b.id
+}
+
+func TestCommitVoteList_VerifyByPower(t *testing.T) {
+	const height = 10
+	blk := &testBlockData{height: height, id: crypto.SHA3Sum256([]byte("block"))}
+	psid := &PartSetID{Count: 1, Hash: crypto.SHA3Sum256([]byte("parts"))}
+
+	var wallets []module.Wallet
+	var vals []module.Validator
+	for i := 0; i < 4; i++ {
+		w := wallet.New()
+		v, err := state.ValidatorFromAddress(w.Address())
+		assert.NoError(t, err)
+		wallets = append(wallets, w)
+		vals = append(vals, v)
+	}
+	validators, err := state.ValidatorSnapshotFromSlice(db.NewMapDB(), vals)
+	assert.NoError(t, err)
+
+	newVoteList := func(ws []module.Wallet) *CommitVoteList {
+		var msgs []*VoteMessage
+		for i, w := range ws {
+			msgs = append(msgs, NewVoteMessage(w, VoteTypePrecommit, height, 0,
+				blk.id, psid, int64(i), nil, nil, 0))
+		}
+		vl, err := newCommitVoteList(nil, msgs)
+		assert.NoError(t, err)
+		return vl
+	}
+	powers := map[int]*big.Int{
+		0: big.NewInt(70),
+		1: big.NewInt(10),
+		2: big.NewInt(10),
+		3: big.NewInt(10),
+	}
+
+	// a minority of validators holding a supermajority of power
+	vl := newVoteList(wallets[:1])
+	voted, err := vl.VerifyByPower(blk, validators, powers)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false, false, false}, voted)
+	_, err = vl.VerifyBlock(blk, validators)
+	assert.Error(t, err)
+
+	// a majority of validators without a supermajority of power
+	_, err = newVoteList(wallets[1:]).VerifyByPower(blk, validators, powers)
+	assert.Error(t, err)
+
+	// exactly 2/3 of power is not enough
+	_, err = newVoteList(wallets[1:3]).VerifyByPower(blk, validators, map[int]*big.Int{
+		1: big.NewInt(10),
+		2: big.NewInt(10),
+		3: big.NewInt(10),
+	})
+	assert.Error(t, err)
+
+	// bad powers
+	_, err = vl.VerifyByPower(blk, validators, map[int]*big.Int{4: big.NewInt(1)})
+	assert.Error(t, err)
+	_, err = vl.VerifyByPower(blk, validators, map[int]*big.Int{0: big.NewInt(-1)})
+	assert.Error(t, err)
+
+	// validators without voting power accept no votes
+	for _, zero := range []map[int]*big.Int{
+		nil,
+		{0: big.NewInt(0), 1: big.NewInt(0), 2: big.NewInt(0), 3: big.NewInt(0)},
+	} {
+		_, err = vl.VerifyByPower(blk, validators, zero)
+		assert.Error(t, err)
+		_, err = newVoteList(wallets).VerifyByPower(blk, validators, zero)
+		assert.Error(t, err)
+	}
+}
+
+func TestCommitVoteList_VerifiedCache(t *testing.T) {
+	const height = 10
+	blockID := crypto.SHA3Sum256([]byte("block"))
+	psid := &PartSetID{Count: 1, Hash: crypto.SHA3Sum256([]byte("parts"))}
+
+	var wallets []module.Wallet
+	var vals []module.Validator
+	for i := 0; i < 4; i++ {
+		w := wallet.New()
+		v, err := state.ValidatorFromAddress(w.Address())
+		assert.NoError(t, err)
+		wallets = append(wallets, w)
+		vals = append(vals, v)
+	}
+	validators, err := state.ValidatorSnapshotFromSlice(db.NewMapDB(), vals)
+	assert.NoError(t, err)
+
+	var msgs []*VoteMessage
+	for i, w := range wallets[:3] {
+		msgs = append(msgs, NewVoteMessage(w, VoteTypePrecommit, height, 0,
+			blockID, psid, int64(i), nil, nil, 0))
+	}
+	vl, err := newCommitVoteList(nil, msgs)
+	assert.NoError(t, err)
+
+	key := verifiedCacheKey(&vl.blockCommitVoteList, height, blockID, validators)
+	assert.Nil(t, verifiedVoteLists.Get(key))
+	voted, err := vl.VerifyWithValidators(height, blockID, psid, validators)
+	assert.NoError(t, err)
+	assert.Equal(t, voted, verifiedVoteLists.Get(key))
+
+	// cached voters can't be modified by callers
+	voted[3] = true
+	voted, err = vl.VerifyWithValidators(height, blockID, psid, validators)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, true, true, false}, voted)
+
+	// tampered vote list is still rejected even if encoded bytes are cached
+	_ = vl.Bytes()
+	other := NewVoteMessage(wallet.New(), VoteTypePrecommit, height, 0,
+		blockID, psid, 0, nil, nil, 0)
+	vl.Items[0].Signature = other.Signature
+	_, err = vl.VerifyWithValidators(height, blockID, psid, validators)
+	assert.Error(t, err)
+
+	// cache is bounded
+	c := newVerifiedCache(2)
+	c.Put("a", []bool{true})
+	c.Put("b", []bool{true})
+	c.Put("c", []bool{true})
+	assert.Equal(t, 2, c.Len())
+	assert.Nil(t, c.Get("a"))
+	assert.NotNil(t, c.Get("c"))
+}
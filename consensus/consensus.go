@@ -96,6 +96,7 @@ type consensus struct {
 	bpmCache       bpmCache
 	timeoutPropose time.Duration
 	dsmLog         dsmLog
+	voteTSSkew     time.Duration
 
 	lastBlock          module.Block
 	validators         module.ValidatorList
@@ -119,7 +120,8 @@ type consensus struct {
 	pcmForLastBlock    module.BTPProofContextMap
 	nextPCM            module.BTPProofContextMap
 
-	timer *time.Timer
+	clock common.Clock
+	timer *common.Timer
 
 	// commit cache
 	commitCache *commitCache
@@ -131,6 +133,9 @@ type consensus struct {
 	metric *metric.ConsensusMetric
 
 	lastVoteData *LastVoteData
+
+	// commit votes for the last finalized block
+	lastCommitVotes module.CommitVoteSet
 }
 
 func NewConsensus(
@@ -173,6 +178,8 @@ func New(
 		dsmLog:       makeDSMLog(configDSMLogSize),
 		lastVoteData:   lastVoteData,
 		timeoutPropose: tmoPropose,
+		voteTSSkew:     c.VoteTimestampSkew(),
+		clock:          &common.GoTimeClock{},
 	}
 	cs.log = c.Logger().WithFields(log.Fields{
 		log.FieldKeyModule: "CS",
@@ -181,6 +188,12 @@ func New(
 	return cs
 }
 
+// SetClock sets the clock used for timeouts. It's for tests, and should be
+// called before Start.
+func (cs *consensus) SetClock(clock common.Clock) {
+	cs.clock = clock
+}
+
 func (cs *consensus) _resetForNewHeight(prevBlock module.Block, votes *voteSet) {
 	cs.height = prevBlock.Height() + 1
 	cs.lastBlock = prevBlock
@@ -256,13 +269,18 @@ func (cs *consensus) resetForNewRound(round int32) {
 func (cs *consensus) resetForNewStep(step step) {
 	cs.endStep()
 	if cs.step < stepPropose && step > stepPropose {
-		now := time.Now()
+		now := cs.clock.Now()
 		cs.nextProposeTime = now
 		cs.c.Regulator().OnPropose(now)
 	}
 	cs.beginStep(step)
 }
 
+func (cs *consensus) afterFunc(d time.Duration, f func()) *common.Timer {
+	timer := cs.clock.AfterFunc(d, f)
+	return &timer
+}
+
 func (cs *consensus) endStep() {
 	if (cs.step == stepPropose || cs.step == stepCommit) && cs.cancelBlockRequest != nil {
 		cs.cancelBlockRequest.Cancel()
@@ -378,6 +396,9 @@ func (cs *consensus) logAndCheckProposalMessage(msg *ProposalMessage) error {
 }
 
 func (cs *consensus) ReceiveProposalMessage(msg *ProposalMessage, unicast bool) error {
+	if err := msg.verifyCanonical(); err != nil {
+		return err
+	}
 	err := cs.logAndCheckProposalMessage(msg)
 	if err != nil {
 		cs.log.Debugf("log or report failed: %+v", err)
@@ -476,6 +497,9 @@ func (cs *consensus) ReceiveBlockPartMessage(msg *BlockPartMessage, unicast bool
 }
 
 func (cs *consensus) ReceiveVoteMessage(msg *VoteMessage, unicast bool) (int, error) {
+	if err := msg.verifyCanonical(); err != nil {
+		return -1, err
+	}
 	lastPC :=
 		msg.Height == cs.height-1 &&
 			cs.step <= stepTransactionWait &&
@@ -507,6 +531,9 @@ func (cs *consensus) ReceiveVoteMessage(msg *VoteMessage, unicast bool) (int, er
 	if index < 0 {
 		return -1, errors.Errorf("bad voter %v", msg.address())
 	}
+	if err = msg.VerifyTimestamp(cs.lastBlock.Timestamp(), cs.voteTSSkew); err != nil {
+		return -1, err
+	}
 	err = msg.VerifyNTSDProofParts(cs.nextPCM, cs.srcUID, index)
 	if err != nil {
 		return -1, err
@@ -532,8 +559,37 @@ func (cs *consensus) ReceiveVoteMessage(msg *VoteMessage, unicast bool) (int, er
 
 func (cs *consensus) ReceiveVoteListMessage(msg *VoteListMessage, unicast bool) error {
 	var err error
+	votes := newVoteDedupSet(cs.height, cs.validators)
 	for i := 0; i < msg.VoteList.Len(); i++ {
 		vmsg := msg.VoteList.Get(i)
+		if vmsg.Height != cs.height {
+			if _, e := cs.ReceiveVoteMessage(vmsg, unicast); e != nil {
+				cs.log.Warnf("bad vote in vote list. VoteMessage:%v Error:%+v\n", vmsg, e)
+				err = errors.Errorf("bad vote in VoteList. LastError: %+v", e)
+			}
+			continue
+		}
+		if e := vmsg.verifyCanonical(); e != nil {
+			cs.log.Warnf("bad vote in vote list. VoteMessage:%v Error:%+v\n", vmsg, e)
+			err = errors.Errorf("bad vote in VoteList. LastError: %+v", e)
+			continue
+		}
+		_, conflict, e := votes.add(vmsg)
+		if e != nil {
+			cs.log.Warnf("bad vote in vote list. VoteMessage:%v Error:%+v\n", vmsg, e)
+			err = errors.Errorf("bad vote in VoteList. LastError: %+v", e)
+			continue
+		}
+		if conflict != nil {
+			// conflicting votes shall be checked for double signing
+			if e := cs.logAndCheckVoteMessage(conflict); e != nil {
+				cs.log.Debugf("log or report failed: %+v", e)
+			}
+		}
+	}
+	vl := votes.voteList()
+	for i := 0; i < vl.Len(); i++ {
+		vmsg := vl.Get(i)
 		if _, e := cs.ReceiveVoteMessage(vmsg, unicast); e != nil {
 			cs.log.Warnf("bad vote in vote list. VoteMessage:%v Error:%+v\n", vmsg, e)
 			err = errors.Errorf("bad vote in VoteList. LastError: %+v", e)
@@ -627,7 +683,7 @@ func (cs *consensus) processPrefetchItems() {
 func (cs *consensus) enterPropose() {
 	cs.resetForNewStep(stepPropose)
 
-	now := time.Now()
+	now := cs.clock.Now()
 	if int(cs.round) > cs.validators.Len()*configRoundTimeoutThresholdFactor {
 		cs.nextProposeTime = now.Add(timeoutNewRound)
 	} else {
@@ -636,7 +692,7 @@ func (cs *consensus) enterPropose() {
 	cs.c.Regulator().OnPropose(now)
 
 	hrs := cs.hrs
-	cs.timer = time.AfterFunc(cs.timeoutPropose, func() {
+	cs.timer = cs.afterFunc(cs.timeoutPropose, func() {
 		cs.mutex.Lock()
 		defer cs.mutex.Unlock()
 
@@ -822,13 +878,14 @@ func (cs *consensus) enterPrevoteWait() {
 		cs.enterPrecommit()
 	} else {
 		hrs := cs.hrs
-		cs.timer = time.AfterFunc(timeoutPrevote, func() {
+		cs.timer = cs.afterFunc(timeoutPrevote, func() {
 			cs.mutex.Lock()
 			defer cs.mutex.Unlock()
 
 			if cs.hrs != hrs || !cs.started {
 				return
 			}
+			cs.logMissingVoters(VoteTypePrevote, prevotes)
 			cs.enterPrecommit()
 		})
 	}
@@ -922,18 +979,27 @@ func (cs *consensus) enterPrecommitWait() {
 	} else {
 		cs.log.Traceln("enterPrecommitWait: start timer")
 		hrs := cs.hrs
-		cs.timer = time.AfterFunc(timeoutPrecommit, func() {
+		cs.timer = cs.afterFunc(timeoutPrecommit, func() {
 			cs.mutex.Lock()
 			defer cs.mutex.Unlock()
 
 			if cs.hrs != hrs || !cs.started {
 				return
 			}
+			cs.logMissingVoters(VoteTypePrecommit, precommits)
 			cs.enterNewRound()
 		})
 	}
 }
 
+// logMissingVoters logs validators whose votes are not received before
+// the timeout, and the number of votes required more for +2/3.
+func (cs *consensus) logMissingVoters(vt VoteType, votes *voteSet) {
+	missing := votes.MissingVoters(cs.validators)
+	cs.log.Infof("%v timeout Height:%d Round:%d missing:%d quorumGap:%d voters:%v\n",
+		vt, cs.height, cs.round, len(missing), votes.quorumGap(), missing)
+}
+
 func (cs *consensus) commitAndEnterNewHeight() {
 	if !cs.currentBlockParts.HasValidatedBlock() {
 		hrs := cs.hrs
@@ -991,7 +1057,7 @@ func (cs *consensus) enterCommit(precommits *voteSet, partSetID *PartSetID, roun
 		cs.log.Errorf("fail to sync WAL: cs.enterCommit: %+v\n", err)
 	}
 
-	cs.nextProposeTime = time.Now()
+	cs.nextProposeTime = cs.clock.Now()
 	if cs.consumedNonunicast || cs.validators.Len() == 1 {
 		if cs.timestamper == nil {
 			cs.nextProposeTime = cs.nextProposeTime.Add(cs.c.Regulator().CommitTimeout())
@@ -1020,10 +1086,10 @@ func (cs *consensus) enterNewRound() {
 	cs.resetForNewRound(cs.round + 1)
 	cs.notifySyncer()
 
-	now := time.Now()
+	now := cs.clock.Now()
 	if cs.nextProposeTime.After(now) {
 		hrs := cs.hrs
-		cs.timer = time.AfterFunc(cs.nextProposeTime.Sub(now), func() {
+		cs.timer = cs.afterFunc(cs.nextProposeTime.Sub(now), func() {
 			cs.mutex.Lock()
 			defer cs.mutex.Unlock()
 
@@ -1069,13 +1135,18 @@ func (cs *consensus) enterTransactionWait() {
 
 func (cs *consensus) enterNewHeight() {
 	votes := cs.hvs.votesFor(cs.commitRound, VoteTypePrecommit)
+	if cvl, err := votes.commitVoteListForOverTwoThirds(cs.nextPCM); err != nil {
+		cs.log.Warnf("fail to make commit vote list: %+v\n", err)
+	} else if cvl != nil {
+		cs.lastCommitVotes = cvl
+	}
 	cs.resetForNewHeight(cs.currentBlockParts.validatedBlock, votes)
 	cs.notifySyncer()
 
-	now := time.Now()
+	now := cs.clock.Now()
 	if cs.nextProposeTime.After(now) {
 		hrs := cs.hrs
-		cs.timer = time.AfterFunc(cs.nextProposeTime.Sub(now), func() {
+		cs.timer = cs.afterFunc(cs.nextProposeTime.Sub(now), func() {
 			cs.mutex.Lock()
 			defer cs.mutex.Unlock()
 
@@ -1178,6 +1249,16 @@ func (cs *consensus) doSendProposal(blockParts PartSet, polRound int32) error {
 	return nil
 }
 
+// SetVoteTimestampSkew sets how much earlier than the last block votes for
+// the current height may be. Older votes are rejected. Zero disables the
+// check.
+func (cs *consensus) SetVoteTimestampSkew(skew time.Duration) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	cs.voteTSSkew = skew
+}
+
 func (cs *consensus) voteTimestamp() int64 {
 	var timestamp int64
 	blockIota := int64(cs.c.Regulator().MinCommitTimeout() / time.Microsecond)
@@ -1945,6 +2026,14 @@ func (cs *consensus) GetVotesByHeight(height int64) (module.CommitVoteSet, error
 	return cs.getVotesByHeight(height)
 }
 
+// LastCommitVotes returns the commit votes for the block finalized last by
+// the consensus. It returns nil before the first commit.
+func (cs *consensus) LastCommitVotes() module.CommitVoteSet {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	return cs.lastCommitVotes
+}
+
 func (cs *consensus) getCommit(h int64) (*commit, error) {
 	if h > cs.height || (h == cs.height && cs.step < stepCommit) {
 		return nil, errors.NotFoundError.Errorf("not found commit height=%d", h)
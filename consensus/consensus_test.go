@@ -33,6 +33,7 @@ import (
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/platform/basic"
 	"github.com/icon-project/goloop/test"
+	"github.com/icon-project/goloop/test/clock"
 )
 
 func TestConsensus_FastSyncServer(t *testing.T) {
@@ -220,6 +221,147 @@ func TestConsensus_BasicConsensus(t *testing.T) {
 	}
 }
 
+func TestConsensus_LastCommitVotes(t *testing.T) {
+	f := test.NewNode(t)
+	defer f.Close()
+
+	h := make([]*test.SimplePeerHandler, 3)
+	for i := 0; i < len(h); i++ {
+		_, h[i] = f.NM.NewPeerFor(module.ProtoConsensus)
+	}
+
+	f.ProposeImportFinalizeBlockWithTX(
+		consensus.NewEmptyCommitVoteList(),
+		test.NewTx().SetValidatorsAddresser(
+			h[0], h[1], h[2], f.Chain.Wallet(),
+		).String(),
+	)
+	f.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+
+	cs := f.CS.(interface {
+		LastCommitVotes() module.CommitVoteSet
+	})
+	assert.Nil(t, cs.LastCommitVotes())
+
+	err := f.CS.Start()
+	assert.NoError(t, err)
+	assert.Nil(t, cs.LastCommitVotes())
+
+	var pm consensus.ProposalMessage
+	h[0].Receive(consensus.ProtoProposal, nil, &pm)
+	assert.EqualValues(t, 3, pm.Height)
+
+	ps := consensus.NewPartSetFromID(pm.BlockPartSetID)
+	for !ps.IsComplete() {
+		var bpm consensus.BlockPartMessage
+		h[0].Receive(consensus.ProtoBlockPart, nil, &bpm)
+		pt, err := consensus.NewPart(bpm.BlockPart)
+		assert.NoError(t, err)
+		err = ps.AddPart(pt)
+		assert.NoError(t, err)
+	}
+	blk, err := f.BM.NewBlockDataFromReader(ps.NewReader())
+	assert.NoError(t, err)
+
+	for _, vt := range []consensus.VoteType{
+		consensus.VoteTypePrevote, consensus.VoteTypePrecommit,
+	} {
+		for i := 0; i < len(h); i++ {
+			h[i].Unicast(
+				consensus.ProtoVote,
+				consensus.NewVoteMessage(
+					h[i].Wallet(), vt, 3, 0, blk.ID(),
+					ps.ID(), blk.Timestamp()+1, nil, nil, 0,
+				),
+				func(rb bool, e error) {
+					assert.True(t, rb)
+					assert.NoError(t, e)
+				},
+			)
+		}
+	}
+
+	hcs0 := h[0].Peer().RegisterProto(module.ProtoConsensusSync)
+	for {
+		var rs consensus.RoundStateMessage
+		hcs0.Receive(consensus.ProtoRoundState, nil, &rs)
+		if rs.Height == 4 {
+			break
+		}
+	}
+
+	cvs := cs.LastCommitVotes()
+	assert.NotNil(t, cvs)
+
+	fblk, err := f.BM.GetBlockByHeight(3)
+	assert.NoError(t, err)
+	assert.Equal(t, blk.ID(), fblk.ID())
+	voted, err := cvs.VerifyBlock(fblk, fblk.NextValidators())
+	assert.NoError(t, err)
+	nVoted := 0
+	for _, v := range voted {
+		if v {
+			nVoted++
+		}
+	}
+	assert.Less(t, len(voted)*2/3, nVoted)
+}
+
+func TestConsensus_SilentProposer(t *testing.T) {
+	// silent proposer -> timeout -> nil prevote -> nil prevotes
+	// -> nil precommit -> nil precommits -> proposal in the next round
+	const timeout = 200 * time.Millisecond
+	cl := &clock.Clock{}
+	f := test.NewNode(t, test.SetTimeoutPropose(timeout), test.UseClock(cl))
+	defer f.Close()
+
+	h := make([]*test.SimplePeerHandler, 3)
+	for i := 0; i < len(h); i++ {
+		_, h[i] = f.NM.NewPeerFor(module.ProtoConsensus)
+	}
+
+	// h[2] is the proposer of round 0, and the node is the proposer of
+	// round 1 for height 3
+	f.ProposeImportFinalizeBlockWithTX(
+		consensus.NewEmptyCommitVoteList(),
+		test.NewTx().SetValidatorsAddresser(
+			f.Chain.Wallet(), h[0], h[1], h[2],
+		).String(),
+	)
+	f.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+
+	assert.NoError(t, f.CS.Start())
+	cl.PassTime(timeout)
+
+	for _, vt := range []consensus.VoteType{
+		consensus.VoteTypePrevote, consensus.VoteTypePrecommit,
+	} {
+		var vm consensus.VoteMessage
+		h[0].Receive(consensus.ProtoVote, nil, &vm)
+		assert.Equal(t, vt, vm.Type)
+		assert.EqualValues(t, 3, vm.Height)
+		assert.EqualValues(t, 0, vm.Round)
+		// nil vote
+		assert.Equal(t, codec.MustMarshalToBytes(f.Chain.NID()), vm.BlockID)
+
+		for i := 0; i < 2; i++ {
+			h[i].Unicast(
+				consensus.ProtoVote,
+				consensus.NewVoteMessage(
+					h[i].Wallet(), vt, 3, 0, vm.BlockID, nil, vm.Timestamp, nil, nil, 0,
+				),
+				nil,
+			)
+		}
+	}
+
+	var pm consensus.ProposalMessage
+	h[0].Receive(consensus.ProtoProposal, nil, &pm)
+	assert.EqualValues(t, 3, pm.Height)
+	assert.EqualValues(t, 1, pm.Round)
+	assert.EqualValues(t, 1, f.CS.GetStatus().Round)
+}
+
 func TestConsensus_BasicConsensus2(t *testing.T) {
 	f := test.NewFixture(t,
 		test.AddDefaultNode(false),
@@ -860,6 +1002,7 @@ type ConsensusInternal interface {
 	module.Consensus
 	OnReceive(sp module.ProtocolInfo, bs []byte, id module.PeerID) (bool, error)
 	ReceiveBlockResult(br fastsync.BlockResult)
+	SetVoteTimestampSkew(skew time.Duration)
 }
 
 type peerID []byte
@@ -1406,3 +1549,47 @@ func TestConsensus_DSR(t *testing.T) {
 	_, _ = cs.OnReceive(consensus.ProtoVote, codec.MustMarshalToBytes(pc1), peer)
 	assert.True(reported)
 }
+
+func TestConsensus_StaleVoteTimestamp(t *testing.T) {
+	assert := assert.New(t)
+	f := test.NewFixture(t, test.AddDefaultNode(false), test.AddValidatorNodes(4))
+	defer f.Close()
+
+	blk := f.Nodes[1].ProposeBlock(consensus.NewEmptyCommitVoteList())
+	pmBytes, bpmBytes, bps := f.Nodes[1].ProposalBytesFor(blk, 0)
+	last, err := f.BM.GetBlockByHeight(blk.Height() - 1)
+	assert.NoError(err)
+
+	peer := peerID(make([]byte, 4))
+	cs, ok := f.CS.(ConsensusInternal)
+	assert.True(ok)
+	skew := time.Second
+	cs.SetVoteTimestampSkew(skew)
+	assert.NoError(cs.Start())
+
+	_, _ = cs.OnReceive(consensus.ProtoProposal, pmBytes, peer)
+	_, _ = cs.OnReceive(consensus.ProtoBlockPart, bpmBytes, peer)
+
+	voteAt := func(n *test.Node, ts int64) []byte {
+		return codec.MustMarshalToBytes(consensus.NewVoteMessage(
+			n.Chain.Wallet(), consensus.VoteTypePrevote, blk.Height(), 0,
+			blk.ID(), bps.ID(), ts, nil, nil, 0,
+		))
+	}
+	skewUS := int64(skew / time.Microsecond)
+
+	// older than the last block by more than skew
+	ok, err = cs.OnReceive(consensus.ProtoVote, voteAt(f.Nodes[1], last.Timestamp()-skewUS-1), peer)
+	assert.False(ok)
+	assert.Error(err)
+
+	// older than the last block within skew
+	ok, err = cs.OnReceive(consensus.ProtoVote, voteAt(f.Nodes[2], last.Timestamp()-skewUS), peer)
+	assert.True(ok)
+	assert.NoError(err)
+
+	pv := f.Nodes[3].VoteFor(consensus.VoteTypePrevote, blk, bps.ID(), 0)
+	ok, err = cs.OnReceive(consensus.ProtoVote, codec.MustMarshalToBytes(pv), peer)
+	assert.True(ok)
+	assert.NoError(err)
+}
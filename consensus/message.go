@@ -380,6 +380,23 @@ func (msg *VoteMessage) EqualExceptSigs(msg2 *VoteMessage) bool {
 	return msg.voteBase.Equal(&msg2.voteBase) && msg.Timestamp == msg2.Timestamp
 }
 
+// VerifyTimestamp checks that the vote isn't older than base, the timestamp
+// of the last block, by more than skew. Votes for the next height are made
+// after the last block, so older ones are stale. Votes in the future aren't
+// checked, because there's no agreed time for them, and the median of vote
+// timestamps keeps few validators from moving the block timestamp.
+// Zero or negative skew disables the check.
+func (msg *VoteMessage) VerifyTimestamp(base int64, skew time.Duration) error {
+	if skew <= 0 {
+		return nil
+	}
+	if diff := base - msg.Timestamp; diff > int64(skew/time.Microsecond) {
+		return errors.Errorf("stale vote timestamp ts=%d base=%d skew=%v",
+			msg.Timestamp, base, skew)
+	}
+	return nil
+}
+
 func (msg *VoteMessage) Verify(ctx verifyContext) error {
 	if err := msg._HR.verify(); err != nil {
 		return err
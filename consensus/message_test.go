@@ -18,10 +18,12 @@ package consensus
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/icon-project/goloop/btp/ntm"
+	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/codec"
 	"github.com/icon-project/goloop/common/wallet"
 	"github.com/icon-project/goloop/module"
@@ -141,6 +143,43 @@ func TestVoteMessage_VerifyOK(t *testing.T) {
 	assert.NoError(msg.Verify(theNilVerifyCtx))
 }
 
+func TestVoteMessage_VerifyTimestamp(t *testing.T) {
+	assert := assert.New(t)
+	base := common.UnixMicroFromTime(time.Now())
+	psb := NewPartSetBuffer(10)
+	_, _ = psb.Write(make([]byte, 10))
+	msg := newVoteMessage()
+	msg.Height = 1
+	msg.Type = VoteTypePrecommit
+	msg.BlockID = []byte("abc")
+	msg.BlockPartSetIDAndNTSVoteCount = psb.PartSet().ID().WithAppData(0)
+
+	skew := 5 * time.Second
+	skewUS := int64(skew / time.Microsecond)
+	for _, ts := range []int64{base, base - skewUS, base + time.Hour.Microseconds()} {
+		msg.Timestamp = ts
+		assert.NoError(msg.VerifyTimestamp(base, skew))
+	}
+
+	// older than the last block by more than skew
+	msg.Timestamp = base - skewUS - 1
+	assert.Error(msg.VerifyTimestamp(base, skew))
+
+	// disabled
+	assert.NoError(msg.VerifyTimestamp(base, 0))
+
+	// timestamp is signed
+	msg.Timestamp = base
+	w := wallet.New()
+	_ = msg.Sign(w)
+	assert.NoError(msg.Verify(theNilVerifyCtx))
+	assert.True(w.Address().Equal(msg.address()))
+	msg.Timestamp += 1
+	msg.signedBase._hash = nil
+	msg.signedBase._publicKey = nil
+	assert.False(w.Address().Equal(msg.address()))
+}
+
 func TestVoteMessage_VerifyMismatchBetweenAppDataAndNTSDProofPartsLen(t *testing.T) {
 	assert := assert.New(t)
 	wp := &walletProvider{wallet.New()}
@@ -64,6 +64,16 @@ func (ida *PartSetIDAndAppData) AppData() uint64 {
 	return uint64(ida.CountWord >> countWidth)
 }
 
+func (ida *PartSetIDAndAppData) Equal(ida2 *PartSetIDAndAppData) bool {
+	if ida == ida2 {
+		return true
+	}
+	if ida == nil || ida2 == nil {
+		return false
+	}
+	return ida.CountWord == ida2.CountWord && bytes.Equal(ida.Hash, ida2.Hash)
+}
+
 type PartSetID struct {
 	Count uint16
 	Hash  []byte
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"math/big"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+// ProposerPriorityState selects proposers in proportion to voting power.
+// On every round, each validator who voted accumulates priority by its
+// voting power, and the validator with the highest priority is selected.
+// Then the priority of the selected one is reduced by the total power
+// accumulated in the round. Validators who didn't vote don't accumulate
+// priority, so they are selected less.
+type ProposerPriorityState struct {
+	validators module.ValidatorList
+	powers     []*big.Int
+	priorities []*big.Int
+	proposer   int
+}
+
+// NewProposerPriorityState returns a new ProposerPriorityState. powers maps
+// an index of validators to its voting power, and validators not in powers
+// have no voting power.
+func NewProposerPriorityState(
+	validators module.ValidatorList, powers map[int]*big.Int,
+) (*ProposerPriorityState, error) {
+	if validators == nil || validators.Len() == 0 {
+		return nil, errors.IllegalArgumentError.New("EmptyValidators")
+	}
+	n := validators.Len()
+	s := &ProposerPriorityState{
+		validators: validators,
+		powers:     make([]*big.Int, n),
+		priorities: make([]*big.Int, n),
+	}
+	total := new(big.Int)
+	for i := 0; i < n; i++ {
+		s.powers[i] = new(big.Int)
+		s.priorities[i] = new(big.Int)
+	}
+	for idx, power := range powers {
+		if idx < 0 || idx >= n {
+			return nil, errors.IllegalArgumentError.Errorf("InvalidIndex(idx=%d)", idx)
+		}
+		if power == nil || power.Sign() < 0 {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"InvalidPower(idx=%d,power=%v)", idx, power)
+		}
+		s.powers[idx].Set(power)
+		total.Add(total, power)
+	}
+	if total.Sign() == 0 {
+		return nil, errors.IllegalArgumentError.New("NoVotingPower")
+	}
+	s.advance(nil)
+	return s, nil
+}
+
+// Next returns the address of the validator selected as the next proposer.
+func (s *ProposerPriorityState) Next() module.Address {
+	v, _ := s.validators.Get(s.proposer)
+	if v == nil {
+		return nil
+	}
+	return v.Address()
+}
+
+// Update moves to the next round with the voters of the last round.
+// voted is the result of CommitVoteSet.VerifyBlock with the validators.
+// It takes the result instead of the votes, because the voters are known
+// only by verifying the votes with the block, and the callers already do it
+// on importing the block. So the signatures aren't verified again here.
+func (s *ProposerPriorityState) Update(voted []bool) error {
+	if len(voted) != len(s.priorities) {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidVoters(len=%d,validators=%d)", len(voted), len(s.priorities))
+	}
+	s.advance(voted)
+	return nil
+}
+
+// advance accumulates priorities of voters, and selects the proposer.
+// All validators are considered as voters if voted is nil.
+func (s *ProposerPriorityState) advance(voted []bool) {
+	added := new(big.Int)
+	for i, power := range s.powers {
+		if voted == nil || voted[i] {
+			s.priorities[i].Add(s.priorities[i], power)
+			added.Add(added, power)
+		}
+	}
+	proposer := 0
+	for i, priority := range s.priorities {
+		if priority.Cmp(s.priorities[proposer]) > 0 {
+			proposer = i
+		}
+	}
+	s.priorities[proposer].Sub(s.priorities[proposer], added)
+	s.proposer = proposer
+}
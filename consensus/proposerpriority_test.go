@@ -0,0 +1,99 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func newTestValidatorList(t *testing.T, n int) module.ValidatorList {
+	var vals []module.Validator
+	for i := 0; i < n; i++ {
+		v, err := state.ValidatorFromAddress(wallet.New().Address())
+		assert.NoError(t, err)
+		vals = append(vals, v)
+	}
+	validators, err := state.ValidatorSnapshotFromSlice(db.NewMapDB(), vals)
+	assert.NoError(t, err)
+	return validators
+}
+
+func TestProposerPriorityState_Frequency(t *testing.T) {
+	const rounds = 1000
+	validators := newTestValidatorList(t, 4)
+	powers := map[int]*big.Int{
+		0: big.NewInt(1),
+		1: big.NewInt(2),
+		2: big.NewInt(3),
+		3: big.NewInt(4),
+	}
+	pps, err := NewProposerPriorityState(validators, powers)
+	assert.NoError(t, err)
+
+	counts := make([]int, validators.Len())
+	voted := []bool{true, true, true, true}
+	for i := 0; i < rounds; i++ {
+		idx := validators.IndexOf(pps.Next())
+		assert.True(t, idx >= 0)
+		counts[idx] += 1
+		assert.NoError(t, pps.Update(voted))
+	}
+	for idx, power := range powers {
+		assert.InDelta(t, rounds*power.Int64()/10, counts[idx], 1, "validator %d", idx)
+	}
+
+	// validator not voting isn't selected
+	pps, err = NewProposerPriorityState(validators, powers)
+	assert.NoError(t, err)
+	counts = make([]int, validators.Len())
+	voted = []bool{true, true, true, false}
+	for i := 0; i < rounds; i++ {
+		assert.NoError(t, pps.Update(voted))
+		counts[validators.IndexOf(pps.Next())] += 1
+	}
+	assert.LessOrEqual(t, counts[3], 1)
+	for idx := 0; idx < 3; idx++ {
+		assert.InDelta(t, rounds*powers[idx].Int64()/6, counts[idx], 2, "validator %d", idx)
+	}
+}
+
+func TestProposerPriorityState_Invalid(t *testing.T) {
+	validators := newTestValidatorList(t, 2)
+
+	_, err := NewProposerPriorityState(nil, map[int]*big.Int{0: big.NewInt(1)})
+	assert.Error(t, err)
+	_, err = NewProposerPriorityState(validators, nil)
+	assert.Error(t, err)
+	_, err = NewProposerPriorityState(validators, map[int]*big.Int{2: big.NewInt(1)})
+	assert.Error(t, err)
+	_, err = NewProposerPriorityState(validators, map[int]*big.Int{0: big.NewInt(-1)})
+	assert.Error(t, err)
+
+	pps, err := NewProposerPriorityState(validators, map[int]*big.Int{1: big.NewInt(1)})
+	assert.NoError(t, err)
+	v, _ := validators.Get(1)
+	assert.True(t, v.Address().Equal(pps.Next()))
+	assert.Error(t, pps.Update([]bool{true}))
+}
@@ -0,0 +1,83 @@
+package consensus
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/icon-project/goloop/common/crypto"
+)
+
+// PublicKeyCacheSize is the number of public keys recovered from signatures
+// kept in the cache shared by all signed data. Zero disables the cache. It
+// must be set on start-up.
+var PublicKeyCacheSize = 4096
+
+type recoveredPublicKey struct {
+	key       string
+	publicKey *crypto.PublicKey
+}
+
+// publicKeyCache keeps public keys recovered from signatures by hash and
+// signature, so that the same message handled by multiple code paths, for
+// example, a vote received alone and in a vote list, is recovered only once.
+type publicKeyCache struct {
+	mu     sync.Mutex
+	keyMap map[string]*list.Element
+	mru    *list.List
+}
+
+func newPublicKeyCache() *publicKeyCache {
+	return &publicKeyCache{
+		keyMap: make(map[string]*list.Element),
+		mru:    list.New(),
+	}
+}
+
+var recoveredPublicKeys = newPublicKeyCache()
+
+func publicKeyCacheKey(hash []byte, sig *crypto.Signature) (string, bool) {
+	sigBS, err := sig.SerializeRSV()
+	if err != nil {
+		return "", false
+	}
+	return string(hash) + string(sigBS), true
+}
+
+func (c *publicKeyCache) Get(key string) *crypto.PublicKey {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.keyMap[key]; ok {
+		c.mru.MoveToFront(e)
+		return e.Value.(*recoveredPublicKey).publicKey
+	}
+	return nil
+}
+
+func (c *publicKeyCache) Put(key string, publicKey *crypto.PublicKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if PublicKeyCacheSize <= 0 {
+		return
+	}
+	if e, ok := c.keyMap[key]; ok {
+		c.mru.MoveToFront(e)
+		return
+	}
+	for c.mru.Len() >= PublicKeyCacheSize {
+		rpk := c.mru.Remove(c.mru.Back()).(*recoveredPublicKey)
+		delete(c.keyMap, rpk.key)
+	}
+	c.keyMap[key] = c.mru.PushFront(&recoveredPublicKey{
+		key:       key,
+		publicKey: publicKey,
+	})
+}
+
+func (c *publicKeyCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.mru.Len()
+}
@@ -0,0 +1,86 @@
+package consensus
+
+import (
+	"sort"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+// RoundState is the round state of the consensus to be persisted, so that
+// collected votes of the height are restored after restart without
+// receiving them again.
+type RoundState struct {
+	Height int64
+	Round  int32
+	Votes  *VoteList
+}
+
+func newRoundState(height int64, round int32, hvs *heightVoteSet) *RoundState {
+	rounds := make([]int32, 0, len(hvs._votes))
+	for r := range hvs._votes {
+		rounds = append(rounds, r)
+	}
+	sort.Slice(rounds, func(i, j int) bool {
+		return rounds[i] < rounds[j]
+	})
+	vl := NewVoteList()
+	for _, r := range rounds {
+		for _, vs := range hvs._votes[r] {
+			if vs == nil {
+				continue
+			}
+			for _, msg := range vs.msgs {
+				if msg != nil {
+					vl.AddVote(msg)
+				}
+			}
+		}
+	}
+	return &RoundState{
+		Height: height,
+		Round:  round,
+		Votes:  vl,
+	}
+}
+
+func (rs *RoundState) Bytes() []byte {
+	return vlCodec.MustMarshalToBytes(rs)
+}
+
+// applyTo adds votes of the round state to hvs. Votes are from the node
+// itself, so signatures are not verified, but signers must be validators.
+func (rs *RoundState) applyTo(hvs *heightVoteSet, validators module.ValidatorList) error {
+	for i := 0; i < rs.Votes.Len(); i++ {
+		msg := rs.Votes.Get(i)
+		if msg.Height != rs.Height {
+			return errors.InvalidStateError.Errorf(
+				"InvalidVoteHeight(idx=%d,height=%d,exp=%d)", i, msg.Height, rs.Height)
+		}
+		index := validators.IndexOf(msg.address())
+		if index < 0 {
+			return errors.InvalidStateError.Errorf(
+				"InvalidVoter(idx=%d,addr=%v)", i, msg.address())
+		}
+		hvs.add(index, msg)
+	}
+	return nil
+}
+
+func RoundStateFromBytes(bs []byte) (*RoundState, error) {
+	rs := new(RoundState)
+	if _, err := vlCodec.UnmarshalFromBytes(bs, rs); err != nil {
+		return nil, err
+	}
+	if rs.Votes == nil {
+		rs.Votes = NewVoteList()
+	}
+	for i := range rs.Votes.VoteItems {
+		pi := int(rs.Votes.VoteItems[i].PrototypeIndex)
+		if pi < 0 || pi >= len(rs.Votes.Prototypes) {
+			return nil, errors.InvalidStateError.Errorf(
+				"InvalidPrototypeIndex(idx=%d,pi=%d)", i, pi)
+		}
+	}
+	return rs, nil
+}
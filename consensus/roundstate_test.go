@@ -0,0 +1,81 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundState_Bytes(t *testing.T) {
+	assert := assert.New(t)
+	wallets, validators := newTestValidatorsWithWallets(t, 4)
+
+	var hvs heightVoteSet
+	hvs.reset(validators.Len())
+	votes := []struct {
+		idx   int
+		vt    VoteType
+		round int32
+	}{
+		{0, VoteTypePrevote, 0},
+		{1, VoteTypePrevote, 0},
+		{0, VoteTypePrecommit, 0},
+		{2, VoteTypePrevote, 1},
+	}
+	for _, v := range votes {
+		hvs.add(v.idx, newTestVote(wallets[v.idx], v.vt, v.round, []byte{1}, 100))
+	}
+
+	rs := newRoundState(10, 1, &hvs)
+	rs2, err := RoundStateFromBytes(rs.Bytes())
+	assert.NoError(err)
+	assert.EqualValues(10, rs2.Height)
+	assert.EqualValues(1, rs2.Round)
+	assert.Equal(len(votes), rs2.Votes.Len())
+
+	var hvs2 heightVoteSet
+	hvs2.reset(validators.Len())
+	assert.NoError(rs2.applyTo(&hvs2, validators))
+	for _, round := range []int32{0, 1} {
+		for _, vt := range []VoteType{VoteTypePrevote, VoteTypePrecommit} {
+			vs := hvs.votesFor(round, vt)
+			vs2 := hvs2.votesFor(round, vt)
+			assert.Equal(vs.count, vs2.count)
+			for i, msg := range vs.msgs {
+				if msg == nil {
+					assert.Nil(vs2.msgs[i])
+					continue
+				}
+				if assert.NotNil(vs2.msgs[i]) {
+					assert.True(msg.EqualExceptSigs(vs2.msgs[i]))
+					assert.Equal(msg.Signature, vs2.msgs[i].Signature)
+				}
+			}
+		}
+	}
+
+	// votes of other validators can't be restored
+	_, validators2 := newTestValidatorsWithWallets(t, 4)
+	hvs2.reset(validators2.Len())
+	assert.Error(rs2.applyTo(&hvs2, validators2))
+
+	// broken bytes
+	_, err = RoundStateFromBytes([]byte{0x01, 0x02})
+	assert.Error(err)
+}
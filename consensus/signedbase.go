@@ -1,12 +1,62 @@
 package consensus
 
 import (
+	"sync/atomic"
+
+	"golang.org/x/crypto/sha3"
+
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/module"
 )
 
+// HashFunc returns the digest of signed data.
+type HashFunc func(data []byte) []byte
+
+// defaultHashFunc is used for signed data unless it's overridden by
+// SetHashFunc.
+var defaultHashFunc HashFunc = crypto.SHA3Sum256
+
+// Keccak256 is a HashFunc for signatures made by Ethereum-style tools,
+// which sign Keccak-256 digest instead of SHA3-256.
+func Keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+var hashFunc atomic.Value
+
+// SetHashFunc overrides the hash function used to sign and verify votes
+// and proposals, and returns the previous one. nil restores the default,
+// SHA3-256.
+//
+// The hash is not stored with signed data, so changing it makes signatures
+// of already committed votes unverifiable. It must be set once on start-up,
+// before any message is created, and all nodes of the network must agree on
+// it.
+func SetHashFunc(f HashFunc) HashFunc {
+	if f == nil {
+		f = defaultHashFunc
+	}
+	old := getHashFunc()
+	hashFunc.Store(f)
+	return old
+}
+
+func getHashFunc() HashFunc {
+	if f, ok := hashFunc.Load().(HashFunc); ok {
+		return f
+	}
+	return defaultHashFunc
+}
+
+var (
+	errMissingSignature      = errors.New("missing signature")
+	errNonCanonicalSignature = errors.New("non-canonical signature (high S)")
+)
+
 type byteser interface {
 	bytes() []byte
 }
@@ -17,24 +67,52 @@ type signedBase struct {
 	_byteser  byteser
 	Signature common.Signature
 
+	_hashFunc  HashFunc
 	_hash      []byte
 	_publicKey *crypto.PublicKey
 }
 
+// setHashFunc sets the hash function for this signed data only. It's for
+// verifying signatures made with other hash, for example, messages relayed
+// from other chains. nil uses the one set by SetHashFunc.
+func (s *signedBase) setHashFunc(f HashFunc) {
+	s._hashFunc = f
+	s._hash = nil
+	s._publicKey = nil
+}
+
 func (s *signedBase) hash() []byte {
 	if s._hash == nil {
 		bs := s._byteser.bytes()
-		s._hash = crypto.SHA3Sum256(bs)
+		f := s._hashFunc
+		if f == nil {
+			f = getHashFunc()
+		}
+		s._hash = f(bs)
 	}
 	return s._hash
 }
 
 func (s *signedBase) publicKey() *crypto.PublicKey {
+	if s.Signature.Signature == nil {
+		return nil
+	}
 	if s._publicKey == nil {
-		publicKey, err := s.Signature.RecoverPublicKey(s.hash())
+		hash := s.hash()
+		key, cacheable := publicKeyCacheKey(hash, s.Signature.Signature)
+		if cacheable {
+			if publicKey := recoveredPublicKeys.Get(key); publicKey != nil {
+				s._publicKey = publicKey
+				return publicKey
+			}
+		}
+		publicKey, err := s.Signature.RecoverPublicKey(hash)
 		if err != nil {
 			return nil
 		}
+		if cacheable {
+			recoveredPublicKeys.Put(key, publicKey)
+		}
 		s._publicKey = publicKey
 	}
 	return s._publicKey
@@ -49,12 +127,25 @@ func (s *signedBase) address() *common.Address {
 }
 
 func (s *signedBase) verify() error {
+	if s.Signature.Signature == nil {
+		return errMissingSignature
+	}
 	if s.publicKey() == nil {
 		return errors.New("bad signature")
 	}
 	return nil
 }
 
+// verifyCanonical returns an error if the signature has high S. It's checked
+// only for messages newly received by the engine, because committed votes
+// were accepted without the check, and they must be verified as they were.
+func (s *signedBase) verifyCanonical() error {
+	if s.Signature.Signature != nil && !s.Signature.Signature.IsLowS() {
+		return errNonCanonicalSignature
+	}
+	return nil
+}
+
 func (s *signedBase) Sign(wallet module.Wallet) error {
 	s._hash = nil
 	s._publicKey = nil
@@ -0,0 +1,220 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"encoding/hex"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestSignedBase_SetHashFunc(t *testing.T) {
+	w := wallet.New()
+	blockID := crypto.SHA3Sum256([]byte("block"))
+
+	old := SetHashFunc(crypto.SHASum256)
+	defer SetHashFunc(old)
+
+	msg := NewPrecommitMessage(w, 1, 0, blockID, nil, 0)
+	assert.Equal(t, crypto.SHASum256(msg._byteser.bytes()), msg.hash())
+	assert.NoError(t, msg.verify())
+	assert.True(t, w.Address().Equal(msg.address()))
+
+	// signature by other hash doesn't recover the signer
+	SetHashFunc(nil)
+	msg.setSignature(msg.Signature)
+	assert.Equal(t, crypto.SHA3Sum256(msg._byteser.bytes()), msg.hash())
+	assert.False(t, w.Address().Equal(msg.address()))
+
+	msg2 := NewPrecommitMessage(w, 1, 0, blockID, nil, 0)
+	assert.NoError(t, msg2.verify())
+	assert.True(t, w.Address().Equal(msg2.address()))
+}
+
+func TestSignedBase_Keccak256(t *testing.T) {
+	// well known digest of empty data
+	exp, _ := hex.DecodeString("c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470")
+	assert.Equal(t, exp, Keccak256(nil))
+
+	w := wallet.New()
+	msg := newVoteMessage()
+	msg.Height = 1
+	msg.Type = VoteTypePrecommit
+	msg.BlockID = crypto.SHA3Sum256([]byte("block"))
+
+	// signed with Keccak-256 as Ethereum-style tools do
+	sig, err := w.Sign(Keccak256(msg._byteser.bytes()))
+	assert.NoError(t, err)
+	var cs common.Signature
+	cs.Signature, err = crypto.ParseSignature(sig)
+	assert.NoError(t, err)
+
+	msg.setSignature(cs)
+	msg.setHashFunc(Keccak256)
+	assert.NoError(t, msg.verify())
+	assert.True(t, w.Address().Equal(msg.address()))
+
+	// SHA3-256 by default
+	msg.setHashFunc(nil)
+	assert.Equal(t, crypto.SHA3Sum256(msg._byteser.bytes()), msg.hash())
+	assert.False(t, w.Address().Equal(msg.address()))
+
+	// it doesn't affect others
+	msg2 := NewPrecommitMessage(w, 1, 0, msg.BlockID, nil, 0)
+	assert.Equal(t, crypto.SHA3Sum256(msg2._byteser.bytes()), msg2.hash())
+}
+
+func TestSignedBase_MissingSignature(t *testing.T) {
+	msg := newVoteMessage()
+	msg.Height = 1
+	msg.Type = VoteTypePrecommit
+
+	assert.Nil(t, msg.publicKey())
+	assert.Nil(t, msg.address())
+	assert.Equal(t, errMissingSignature, msg.verify())
+
+	assert.NoError(t, msg.Sign(wallet.New()))
+	assert.NoError(t, msg.verify())
+}
+
+// malleateSignature returns the signature (R, N-S) with the flipped
+// recovery flag, which is valid for the same key.
+func malleateSignature(t *testing.T, sig common.Signature) common.Signature {
+	rsv, err := sig.Signature.SerializeRSV()
+	assert.NoError(t, err)
+	sv := new(big.Int).SetBytes(rsv[32:64])
+	sv.Sub(secp256k1.S256().N, sv)
+	sv.FillBytes(rsv[32:64])
+	rsv[64] ^= 1
+	msig, err := crypto.ParseSignature(rsv)
+	assert.NoError(t, err)
+	return common.Signature{Signature: msig}
+}
+
+func TestSignedBase_HighS(t *testing.T) {
+	w := wallet.New()
+	blockID := crypto.SHA3Sum256([]byte("block"))
+	msg := NewPrecommitMessage(w, 1, 0, blockID, nil, 0)
+	assert.NoError(t, msg.verify())
+	assert.NoError(t, msg.verifyCanonical())
+
+	sig := malleateSignature(t, msg.Signature)
+	assert.False(t, sig.Signature.IsLowS())
+
+	// high S recovers the same key, so it's valid but not canonical
+	msg.setSignature(sig)
+	assert.NoError(t, msg.verify())
+	assert.True(t, w.Address().Equal(msg.address()))
+	assert.Equal(t, errNonCanonicalSignature, msg.verifyCanonical())
+}
+
+func TestSignedBase_PublicKeyCache(t *testing.T) {
+	w := wallet.New()
+	blockID := crypto.SHA3Sum256([]byte("block"))
+
+	msg := NewPrecommitMessage(w, 1, 0, blockID, nil, 0)
+	pk := msg.publicKey()
+	assert.NotNil(t, pk)
+
+	// another instance of the same message uses the recovered one
+	msg2 := NewPrecommitMessage(w, 1, 0, blockID, nil, 0)
+	assert.True(t, pk == msg2.publicKey())
+
+	// but not for other messages
+	msg3 := NewPrecommitMessage(w, 2, 0, blockID, nil, 0)
+	assert.False(t, pk == msg3.publicKey())
+	assert.True(t, w.Address().Equal(msg3.address()))
+}
+
+func TestPublicKeyCache_Eviction(t *testing.T) {
+	old := PublicKeyCacheSize
+	defer func() { PublicKeyCacheSize = old }()
+
+	_, pk := crypto.GenerateKeyPair()
+	c := newPublicKeyCache()
+	PublicKeyCacheSize = 2
+	c.Put("a", pk)
+	c.Put("b", pk)
+	assert.NotNil(t, c.Get("a"))
+	c.Put("c", pk)
+	assert.Equal(t, 2, c.Len())
+	assert.NotNil(t, c.Get("a"))
+	assert.Nil(t, c.Get("b"))
+	assert.NotNil(t, c.Get("c"))
+
+	PublicKeyCacheSize = 0
+	c.Put("d", pk)
+	assert.Nil(t, c.Get("d"))
+}
+
+func TestPublicKeyCache_Concurrent(t *testing.T) {
+	ws := make([]module.Wallet, 4)
+	for i := range ws {
+		ws[i] = wallet.New()
+	}
+	blockID := crypto.SHA3Sum256([]byte("block"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				w := ws[(i+j)%len(ws)]
+				msg := NewPrecommitMessage(w, int64(j%5), 0, blockID, nil, 0)
+				msg.setSignature(msg.Signature)
+				assert.True(t, w.Address().Equal(msg.address()))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func BenchmarkSignedBase_PublicKey(b *testing.B) {
+	w := wallet.New()
+	msg := NewPrecommitMessage(w, 1, 0, crypto.SHA3Sum256([]byte("block")), nil, 0)
+
+	bench := func(b *testing.B, size int) {
+		old := PublicKeyCacheSize
+		defer func() { PublicKeyCacheSize = old }()
+		PublicKeyCacheSize = size
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			msg.setSignature(msg.Signature)
+			if msg.publicKey() == nil {
+				b.Fatal("fail to recover public key")
+			}
+		}
+	}
+	b.Run("NoCache", func(b *testing.B) {
+		recoveredPublicKeys = newPublicKeyCache()
+		bench(b, 0)
+	})
+	b.Run("Cache", func(b *testing.B) {
+		bench(b, 16)
+	})
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+)
+
+// NewVoteListForTest returns a commit vote list having an item for each
+// signature. Unlike NewCommitVoteList, it doesn't check consistency of votes,
+// so tests can build arbitrary (even invalid) vote lists.
+func NewVoteListForTest(round int32, id *PartSetID, sigs []common.Signature) module.CommitVoteSet {
+	vl := &CommitVoteList{}
+	vl.Round = round
+	vl.BlockPartSetIDAndAppData = id.WithAppData(0)
+	if len(sigs) > 0 {
+		vl.Items = make([]blockCommitVoteItem, len(sigs))
+		for i, sig := range sigs {
+			vl.Items[i].Signature = sig
+		}
+	}
+	return vl
+}
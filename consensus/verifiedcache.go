@@ -0,0 +1,87 @@
+package consensus
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/module"
+)
+
+const verifiedVoteListCacheSize = 256
+
+type verifiedVoteList struct {
+	key    string
+	voters []bool
+}
+
+// verifiedCache keeps voters of vote lists already verified against
+// validators, so that verifying the same vote list again, for example on
+// replaying blocks, doesn't need to recover signatures again. Entries are
+// keyed by the content of the vote list, so modified vote lists never hit.
+type verifiedCache struct {
+	mu     sync.Mutex
+	cap    int
+	keyMap map[string]*list.Element
+	mru    *list.List
+}
+
+func newVerifiedCache(cap int) *verifiedCache {
+	return &verifiedCache{
+		cap:    cap,
+		keyMap: make(map[string]*list.Element),
+		mru:    list.New(),
+	}
+}
+
+var verifiedVoteLists = newVerifiedCache(verifiedVoteListCacheSize)
+
+func verifiedCacheKey(
+	bvl *blockCommitVoteList, height int64, blockID []byte, validators module.ValidatorList,
+) string {
+	// bvl.bytes is not used as it may be stale for modified items
+	content := vlCodec.MustMarshalToBytes(&blockCommitVoteList{
+		Round:                    bvl.Round,
+		BlockPartSetIDAndAppData: bvl.BlockPartSetIDAndAppData,
+		Items:                    bvl.Items,
+	})
+	return string(crypto.SHA3Sum256(vlCodec.MustMarshalToBytes([]interface{}{
+		validators.Hash(), height, blockID, crypto.SHA3Sum256(content),
+	})))
+}
+
+func (c *verifiedCache) Get(key string) []bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.keyMap[key]; ok {
+		c.mru.MoveToFront(e)
+		return append([]bool(nil), e.Value.(*verifiedVoteList).voters...)
+	}
+	return nil
+}
+
+func (c *verifiedCache) Put(key string, voters []bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.keyMap[key]; ok {
+		c.mru.MoveToFront(e)
+		return
+	}
+	if c.mru.Len() == c.cap {
+		vvl := c.mru.Remove(c.mru.Back()).(*verifiedVoteList)
+		delete(c.keyMap, vvl.key)
+	}
+	c.keyMap[key] = c.mru.PushFront(&verifiedVoteList{
+		key:    key,
+		voters: append([]bool(nil), voters...),
+	})
+}
+
+func (c *verifiedCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.mru.Len()
+}
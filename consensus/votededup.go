@@ -0,0 +1,73 @@
+package consensus
+
+import (
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+type voteDedupKey struct {
+	round    int32
+	voteType VoteType
+	index    int
+}
+
+// voteDedupSet keeps only the latest vote of a signer for each round and
+// vote type among incoming votes of a height. It's used to drop duplicated
+// votes before they are handled one by one.
+type voteDedupSet struct {
+	height     int64
+	validators module.ValidatorList
+	votes      map[voteDedupKey]*VoteMessage
+	keys       []voteDedupKey
+}
+
+// add adds the vote to the set. It returns false if msg is dropped as
+// duplicated or outdated vote. If there is a conflicting vote of the signer,
+// then it returns the one not kept in the set, which is the replaced vote or
+// msg itself if msg is older, so that it can be checked for double signing.
+// It returns an error if the signer is not a validator.
+func (s *voteDedupSet) add(msg *VoteMessage) (bool, *VoteMessage, error) {
+	if msg.Height != s.height {
+		return false, nil, errors.Errorf("bad height %d expected=%d", msg.Height, s.height)
+	}
+	index := s.validators.IndexOf(msg.address())
+	if index < 0 {
+		return false, nil, errors.Errorf("bad voter %v", msg.address())
+	}
+	key := voteDedupKey{msg.Round, msg.Type, index}
+	omsg, ok := s.votes[key]
+	if !ok {
+		s.votes[key] = msg
+		s.keys = append(s.keys, key)
+		return true, nil, nil
+	}
+	if omsg.EqualExceptSigs(msg) {
+		return false, nil, nil
+	}
+	if msg.Timestamp < omsg.Timestamp {
+		return false, msg, nil
+	}
+	s.votes[key] = msg
+	return true, omsg, nil
+}
+
+func (s *voteDedupSet) len() int {
+	return len(s.keys)
+}
+
+// voteList returns votes in the set in the order of their first arrival.
+func (s *voteDedupSet) voteList() *VoteList {
+	vl := NewVoteList()
+	for _, key := range s.keys {
+		vl.AddVote(s.votes[key])
+	}
+	return vl
+}
+
+func newVoteDedupSet(height int64, validators module.ValidatorList) *voteDedupSet {
+	return &voteDedupSet{
+		height:     height,
+		validators: validators,
+		votes:      make(map[voteDedupKey]*VoteMessage),
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func newTestValidatorsWithWallets(t *testing.T, n int) ([]module.Wallet, module.ValidatorList) {
+	var wallets []module.Wallet
+	var vals []module.Validator
+	for i := 0; i < n; i++ {
+		w := wallet.New()
+		v, err := state.ValidatorFromAddress(w.Address())
+		assert.NoError(t, err)
+		wallets = append(wallets, w)
+		vals = append(vals, v)
+	}
+	validators, err := state.ValidatorSnapshotFromSlice(db.NewMapDB(), vals)
+	assert.NoError(t, err)
+	return wallets, validators
+}
+
+func newTestVote(w module.Wallet, voteType VoteType, round int32, id []byte, ts int64) *VoteMessage {
+	return NewVoteMessage(w, voteType, 10, round, id, nil, ts, nil, nil, 0)
+}
+
+func TestVoteDedupSet(t *testing.T) {
+	assert := assert.New(t)
+	wallets, validators := newTestValidatorsWithWallets(t, 4)
+	s := newVoteDedupSet(10, validators)
+
+	v0 := newTestVote(wallets[0], VoteTypePrevote, 0, []byte{1}, 100)
+	added, replaced, err := s.add(v0)
+	assert.NoError(err)
+	assert.True(added)
+	assert.Nil(replaced)
+
+	// duplicated votes from the same signer
+	for i := 0; i < 3; i++ {
+		added, replaced, err = s.add(newTestVote(wallets[0], VoteTypePrevote, 0, []byte{1}, 100))
+		assert.NoError(err)
+		assert.False(added)
+		assert.Nil(replaced)
+	}
+
+	// votes of other type, round and signer are kept
+	added, _, _ = s.add(newTestVote(wallets[0], VoteTypePrecommit, 0, []byte{1}, 100))
+	assert.True(added)
+	added, _, _ = s.add(newTestVote(wallets[0], VoteTypePrevote, 1, []byte{1}, 100))
+	assert.True(added)
+	added, _, _ = s.add(newTestVote(wallets[1], VoteTypePrevote, 0, []byte{1}, 100))
+	assert.True(added)
+	assert.Equal(4, s.len())
+
+	// conflicting votes from the same signer. only the latest one is kept
+	v0c := newTestVote(wallets[0], VoteTypePrevote, 0, []byte{2}, 110)
+	added, replaced, err = s.add(v0c)
+	assert.NoError(err)
+	assert.True(added)
+	assert.True(v0.Equal(&replaced.voteBase))
+	assert.Equal(4, s.len())
+
+	// conflicting older vote is dropped, but returned for double signing
+	v0o := newTestVote(wallets[0], VoteTypePrevote, 0, []byte{3}, 105)
+	added, replaced, err = s.add(v0o)
+	assert.NoError(err)
+	assert.False(added)
+	assert.Same(v0o, replaced)
+	assert.Equal(4, s.len())
+
+	// out-of-set signer and other height
+	_, _, err = s.add(newTestVote(wallet.New(), VoteTypePrevote, 0, []byte{1}, 100))
+	assert.Error(err)
+	_, _, err = s.add(NewVoteMessage(wallets[2], VoteTypePrevote, 11, 0, []byte{1}, nil, 100, nil, nil, 0))
+	assert.Error(err)
+	assert.Equal(4, s.len())
+
+	// vote list has no duplicates
+	vl := s.voteList()
+	assert.Equal(4, vl.Len())
+	first := vl.Get(0)
+	assert.True(first.EqualExceptSigs(v0c))
+	assert.True(wallets[0].Address().Equal(first.address()))
+	seen := make(map[voteDedupKey]bool)
+	for i := 0; i < vl.Len(); i++ {
+		msg := vl.Get(i)
+		key := voteDedupKey{msg.Round, msg.Type, validators.IndexOf(msg.address())}
+		assert.False(seen[key])
+		seen[key] = true
+	}
+}
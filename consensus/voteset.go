@@ -77,6 +77,31 @@ func (vs *voteSet) hasOverTwoThirds() bool {
 	return vs.count > len(vs.msgs)*2/3
 }
 
+// quorumGap returns the number of votes required more to have +2/3 votes.
+// All validators have the same voting power.
+func (vs *voteSet) quorumGap() int {
+	if gap := len(vs.msgs)*2/3 + 1 - vs.count; gap > 0 {
+		return gap
+	}
+	return 0
+}
+
+// MissingVoters returns addresses of validators whose votes are not
+// collected in the set. validators shall be the list used for the indexes
+// of the votes.
+func (vs *voteSet) MissingVoters(validators module.ValidatorList) []module.Address {
+	var addrs []module.Address
+	for i := 0; i < validators.Len(); i++ {
+		if i < len(vs.msgs) && vs.msgs[i] != nil {
+			continue
+		}
+		if v, ok := validators.Get(i); ok {
+			addrs = append(addrs, v.Address())
+		}
+	}
+	return addrs
+}
+
 func (vs *voteSet) getRound() int32 {
 	return vs.round
 }
@@ -169,3 +169,28 @@ func TestHeightVoteSet_getRoundEvidences(t *testing.T) {
 		assert.EqualValues(10, vl.Get(i).Round)
 	}
 }
+
+func TestVoteSet_MissingVoters(t *testing.T) {
+	assert := assert.New(t)
+	validators := newTestValidatorList(t, 7)
+	vs := newVoteSet(validators.Len())
+	assert.Equal(5, vs.quorumGap())
+	assert.Len(vs.MissingVoters(validators), 7)
+
+	vs.add(1, newVoteMsgWithHashPrefix(0))
+	vs.add(4, newVoteMsgWithHashPrefix(0))
+	vs.add(5, newVoteMsgWithHashPrefix(1))
+	assert.Equal(2, vs.quorumGap())
+	missing := vs.MissingVoters(validators)
+	assert.Len(missing, 4)
+	for i, idx := range []int{0, 2, 3, 6} {
+		v, _ := validators.Get(idx)
+		assert.True(v.Address().Equal(missing[i]))
+	}
+
+	vs.add(0, newVoteMsgWithHashPrefix(0))
+	vs.add(2, newVoteMsgWithHashPrefix(0))
+	assert.Equal(0, vs.quorumGap())
+	assert.True(vs.hasOverTwoThirds())
+	assert.Len(vs.MissingVoters(validators), 2)
+}
@@ -25,8 +25,10 @@ import (
 
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss"
 	"github.com/icon-project/goloop/icon/iiss/icstate"
 	"github.com/icon-project/goloop/icon/iiss/icutils"
 	"github.com/icon-project/goloop/module"
@@ -196,6 +198,14 @@ var chainMethods = []*chainMethod{
 		},
 		nil,
 	}, 0, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "setStepRefundCap",
+		scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"cap", scoreapi.Integer, nil, nil},
+		},
+		nil,
+	}, icmodule.RevisionStepRefundCap, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "getRevision",
 		scoreapi.FlagReadOnly, 0,
@@ -240,6 +250,14 @@ var chainMethods = []*chainMethod{
 			scoreapi.Integer,
 		},
 	}, 0, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getStepRefundCap",
+		scoreapi.FlagReadOnly, 0,
+		nil,
+		[]scoreapi.DataType{
+			scoreapi.Integer,
+		},
+	}, icmodule.RevisionStepRefundCap, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "getScoreStatus",
 		scoreapi.FlagReadOnly, 1,
@@ -292,6 +310,14 @@ var chainMethods = []*chainMethod{
 			scoreapi.Dict,
 		},
 	}, icmodule.RevisionIISS, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getRewardCalcStatus",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
+		nil,
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionRewardCalcStatus, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "setIRep",
 		scoreapi.FlagExternal, 1,
@@ -333,7 +359,29 @@ var chainMethods = []*chainMethod{
 		[]scoreapi.DataType{
 			scoreapi.Dict,
 		},
-	}, icmodule.RevisionIISS, 0},
+	}, icmodule.RevisionIISS, icmodule.RevisionAmountPrecisionJSON - 1},
+	{scoreapi.Method{
+		scoreapi.Function, "getStake",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"address", scoreapi.Address, nil, nil},
+			{"precision", scoreapi.Integer, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionAmountPrecisionJSON, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getStakeAt",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 2,
+		[]scoreapi.Parameter{
+			{"address", scoreapi.Address, nil, nil},
+			{"height", scoreapi.Integer, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionStakeAtAPI, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "setDelegation",
 		scoreapi.FlagExternal, 0,
@@ -360,6 +408,16 @@ var chainMethods = []*chainMethod{
 		},
 		nil,
 	}, icmodule.RevisionFixSetDelegation, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "moveDelegation",
+		scoreapi.FlagExternal, 3,
+		[]scoreapi.Parameter{
+			{"from", scoreapi.Address, nil, nil},
+			{"to", scoreapi.Address, nil, nil},
+			{"value", scoreapi.Integer, nil, nil},
+		},
+		nil,
+	}, icmodule.RevisionMoveDelegation, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "getDelegation",
 		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
@@ -369,7 +427,18 @@ var chainMethods = []*chainMethod{
 		[]scoreapi.DataType{
 			scoreapi.Dict,
 		},
-	}, icmodule.RevisionIISS, 0},
+	}, icmodule.RevisionIISS, icmodule.RevisionAmountPrecisionJSON - 1},
+	{scoreapi.Method{
+		scoreapi.Function, "getDelegation",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"address", scoreapi.Address, nil, nil},
+			{"precision", scoreapi.Integer, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionAmountPrecisionJSON, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "claimIScore",
 		scoreapi.FlagExternal, 0,
@@ -411,6 +480,50 @@ var chainMethods = []*chainMethod{
 			scoreapi.Dict,
 		},
 	}, icmodule.RevisionIISS, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getTotalStaked",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
+		nil,
+		[]scoreapi.DataType{
+			scoreapi.Integer,
+		},
+	}, icmodule.RevisionTotalStakedAPI, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getNetworkStakeRatio",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
+		nil,
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionNetworkStakeRatioAPI, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getUnstakeSlotMax",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
+		nil,
+		[]scoreapi.DataType{
+			scoreapi.Integer,
+		},
+	}, icmodule.RevisionUnstakeSlotMaxAPI, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getAvailableVotingPower",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"address", scoreapi.Address, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Integer,
+		},
+	}, icmodule.RevisionAvailableVotingPowerAPI, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getBondedRatio",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"address", scoreapi.Address, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Integer,
+		},
+	}, icmodule.RevisionBondedRatioAPI, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "unregisterPRep",
 		scoreapi.FlagExternal, 0,
@@ -451,6 +564,17 @@ var chainMethods = []*chainMethod{
 			scoreapi.Dict,
 		},
 	}, icmodule.RevisionIISS, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getPRepsPage",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 2,
+		[]scoreapi.Parameter{
+			{"cursor", scoreapi.Bytes, nil, nil},
+			{"limit", scoreapi.Integer, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionPRepsPage, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "getMainPReps",
 		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
@@ -480,6 +604,26 @@ var chainMethods = []*chainMethod{
 		},
 		nil,
 	}, icmodule.RevisionEnableBondAPIs, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "setStakeDelegationBond",
+		scoreapi.FlagExternal, 3,
+		[]scoreapi.Parameter{
+			{"stake", scoreapi.Integer, nil, nil},
+			{"delegations", scoreapi.ListTypeOf(1, scoreapi.Struct), nil,
+				[]scoreapi.Field{
+					{"address", scoreapi.Address, nil},
+					{"value", scoreapi.Integer, nil},
+				},
+			},
+			{"bonds", scoreapi.ListTypeOf(1, scoreapi.Struct), nil,
+				[]scoreapi.Field{
+					{"address", scoreapi.Address, nil},
+					{"value", scoreapi.Integer, nil},
+				},
+			},
+		},
+		nil,
+	}, icmodule.RevisionSetStakeDelegationBond, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "getBond",
 		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
@@ -490,6 +634,16 @@ var chainMethods = []*chainMethod{
 			scoreapi.Dict,
 		},
 	}, icmodule.RevisionEnableBondAPIs, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getBonds",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"address", scoreapi.Address, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionGetBondsAPI, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "setBonderList",
 		scoreapi.FlagExternal, 1,
@@ -508,6 +662,17 @@ var chainMethods = []*chainMethod{
 			scoreapi.Dict,
 		},
 	}, icmodule.RevisionEnableBondAPIs, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getPRepDelegators",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"address", scoreapi.Address, nil, nil},
+			{"limit", scoreapi.Integer, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionDelegatorIndex, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "estimateUnstakeLockPeriod",
 		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
@@ -516,6 +681,38 @@ var chainMethods = []*chainMethod{
 			scoreapi.Dict,
 		},
 	}, icmodule.RevisionIISS, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "estimateUnstake",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"value", scoreapi.Integer, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionEstimateUnstakeAPI, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "estimateReward",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 3,
+		[]scoreapi.Parameter{
+			{"delegator", scoreapi.Address, nil, nil},
+			{"prep", scoreapi.Address, nil, nil},
+			{"amount", scoreapi.Integer, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionEstimateRewardAPI, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getStakeForUnstakePeriod",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"period", scoreapi.Integer, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionStakeForUnstakePeriodAPI, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "getPRepTerm",
 		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
@@ -815,6 +1012,78 @@ var chainMethods = []*chainMethod{
 		},
 		nil,
 	}, icmodule.RevisionIISS4R0, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "setIScoreICXRatio",
+		scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"ratio", scoreapi.Integer, nil, nil},
+		},
+		nil,
+	}, icmodule.RevisionIScoreICXRatio, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "setMaxValidators",
+		scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"value", scoreapi.Integer, nil, nil},
+		},
+		nil,
+	}, icmodule.RevisionMaxValidators, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getMaxValidators",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
+		nil,
+		[]scoreapi.DataType{
+			scoreapi.Integer,
+		},
+	}, icmodule.RevisionMaxValidators, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "setRegistrationBond",
+		scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"bond", scoreapi.Integer, nil, nil},
+		},
+		nil,
+	}, icmodule.RevisionRegistrationBond, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getRegistrationBond",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
+		nil,
+		[]scoreapi.DataType{
+			scoreapi.Integer,
+		},
+	}, icmodule.RevisionRegistrationBond, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "setStakeReductionPolicy",
+		scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"policy", scoreapi.Integer, nil, nil},
+		},
+		nil,
+	}, icmodule.RevisionStakeReductionPolicy, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getStakeReductionPolicy",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
+		nil,
+		[]scoreapi.DataType{
+			scoreapi.Integer,
+		},
+	}, icmodule.RevisionStakeReductionPolicy, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "setMinimumDelegation",
+		scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"amount", scoreapi.Integer, nil, nil},
+		},
+		nil,
+	}, icmodule.RevisionMinimumDelegation, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getMinimumDelegation",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
+		nil,
+		[]scoreapi.DataType{
+			scoreapi.Integer,
+		},
+	}, icmodule.RevisionMinimumDelegation, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "initCommissionRate",
 		scoreapi.FlagExternal, 3,
@@ -833,6 +1102,12 @@ var chainMethods = []*chainMethod{
 		},
 		nil,
 	}, icmodule.RevisionIISS4R0, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "claimCommission",
+		scoreapi.FlagExternal, 0,
+		nil,
+		nil,
+	}, icmodule.RevisionCommissionAccumulator, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "requestUnjail",
 		scoreapi.FlagExternal, 0,
@@ -1104,6 +1379,66 @@ type ChainConfig struct {
 	RoundLimitFactor   *common.HexInt64  `json:"roundLimitFactor"`
 	DepositTerm        *common.HexInt64  `json:"depositTerm"`
 	FeeSharingEnabled  *common.HexInt16  `json:"feeSharingEnabled"`
+	PReps              []*GenesisPRep    `json:"preps"`
+}
+
+// GenesisPRep is a P-Rep to be registered in the genesis.
+type GenesisPRep struct {
+	Address     *common.Address `json:"address"`
+	Name        string          `json:"name"`
+	Email       string          `json:"email"`
+	Website     string          `json:"website"`
+	Country     string          `json:"country"`
+	City        string          `json:"city"`
+	Details     string          `json:"details"`
+	P2PEndpoint string          `json:"p2pEndpoint"`
+	NodeAddress *common.Address `json:"nodeAddress"`
+	Delegation  *common.HexInt  `json:"delegation"`
+}
+
+func (p *GenesisPRep) info() *icstate.PRepInfo {
+	info := &icstate.PRepInfo{
+		City:        &p.City,
+		Country:     &p.Country,
+		Details:     &p.Details,
+		Email:       &p.Email,
+		Name:        &p.Name,
+		P2PEndpoint: &p.P2PEndpoint,
+		WebSite:     &p.Website,
+	}
+	if p.NodeAddress != nil {
+		info.Node = p.NodeAddress
+	}
+	return info
+}
+
+func (s *chainScore) registerGenesisPReps(preps []*GenesisPRep) error {
+	if len(preps) == 0 {
+		return nil
+	}
+	if contract.GetRevision(s.cc) < icmodule.RevisionIISS {
+		return transaction.InvalidGenesisError.Errorf(
+			"GenesisPRepsWithoutIISS(rev=%d)", contract.GetRevision(s.cc))
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return err
+	}
+	for i, p := range preps {
+		if p.Address == nil || p.Address.IsContract() {
+			return transaction.InvalidGenesisError.Errorf("InvalidPRepAddress(idx=%d,address=%v)", i, p.Address)
+		}
+		var delegation *big.Int
+		if p.Delegation != nil {
+			delegation = p.Delegation.Value()
+		}
+		cc := iiss.NewCallContext(s.cc, p.Address)
+		if err = es.RegisterGenesisPRep(cc, p.info(), delegation); err != nil {
+			return transaction.InvalidGenesisError.Wrapf(err, "FailToRegisterPRep(address=%s)", p.Address)
+		}
+		s.log.Debugf("register genesis prep %d: %s", i, p.Address)
+	}
+	return nil
 }
 
 func newIconConfig() *config {
@@ -1171,6 +1506,7 @@ func (s *chainScore) Install(param []byte) error {
 	var systemConfig int
 	var revision int
 	var validators []module.Validator
+	var preps []*GenesisPRep
 	var handlers []contract.ContractHandler
 	blockInterval := int64(2000)
 	roundLimitFactor := int64(3)
@@ -1302,12 +1638,15 @@ func (s *chainScore) Install(param []byte) error {
 			}
 		}
 
-		validators = make([]module.Validator, len(chainConfig.ValidatorList))
+		var err error
+		if validators, err = state.ValidatorsFromAddresses(chainConfig.ValidatorList); err != nil {
+			return err
+		}
 		for i, validator := range chainConfig.ValidatorList {
-			validators[i], _ = state.ValidatorFromAddress(validator)
 			s.log.Debugf("add validator %d: %v", i, validator)
 		}
 		feeConfig = &chainConfig.Fee
+		preps = chainConfig.PReps
 	}
 
 	if err := scoredb.NewVarDB(as, state.VarRevision).Set(revision); err != nil {
@@ -1363,6 +1702,10 @@ func (s *chainScore) Install(param []byte) error {
 		return err
 	}
 
+	if err := s.registerGenesisPReps(preps); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -1385,6 +1728,26 @@ func (s *chainScore) GetAPI() *scoreapi.Info {
 	return scoreapi.NewInfo(methods[:j])
 }
 
+// AllMethodsMetadata returns all methods regardless of the revision with
+// the range of revisions where the method is available. maxRevision is
+// omitted if the method is available for the latest revision.
+func (s *chainScore) AllMethodsMetadata() ([]map[string]interface{}, error) {
+	metadata := make([]map[string]interface{}, 0, len(chainMethods))
+	for _, m := range chainMethods {
+		jso, err := m.Method.ToJSON(module.JSONVersion3)
+		if err != nil {
+			return nil, err
+		}
+		mjso := jso.(map[string]interface{})
+		mjso["minRevision"] = intconv.FormatInt(int64(m.minVer))
+		if m.maxVer != 0 {
+			mjso["maxRevision"] = intconv.FormatInt(int64(m.maxVer))
+		}
+		metadata = append(metadata, mjso)
+	}
+	return metadata, nil
+}
+
 func (s *chainScore) checkGovernance(charge bool) error {
 	if !s.gov {
 		if charge {
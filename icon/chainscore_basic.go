@@ -401,6 +401,14 @@ func (s *chainScore) Ex_setMaxStepLimit(contextType string, cost *common.HexInt)
 	return err
 }
 
+func (s *chainScore) Ex_setStepRefundCap(c int64) error {
+	if err := s.checkGovernance(true); err != nil {
+		return err
+	}
+	_, err := contract.SetStepRefundCap(s.cc, c)
+	return err
+}
+
 func (s *chainScore) Ex_getRevision() (int64, error) {
 	if err := s.tryChargeCall(false); err != nil {
 		return 0, err
@@ -437,6 +445,13 @@ func (s *chainScore) Ex_getMaxStepLimit(contextType string) (*big.Int, error) {
 	return contract.GetMaxStepLimit(s.cc, contextType), nil
 }
 
+func (s *chainScore) Ex_getStepRefundCap() (int64, error) {
+	if err := s.tryChargeCall(false); err != nil {
+		return 0, err
+	}
+	return contract.GetStepRefundCap(s.cc), nil
+}
+
 func (s *chainScore) Ex_getScoreStatus(address module.Address) (map[string]interface{}, error) {
 	if err := s.tryChargeCall(false); err != nil {
 		return nil, err
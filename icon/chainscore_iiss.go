@@ -19,6 +19,7 @@ package icon
 import (
 	"bytes"
 	"encoding/hex"
+	"math"
 	"math/big"
 
 	"github.com/icon-project/goloop/common"
@@ -132,10 +133,13 @@ func (s *chainScore) Ex_setStake(value *common.HexInt) (err error) {
 	return es.SetStake(cc, &value.Int)
 }
 
-func (s *chainScore) Ex_getStake(address module.Address) (map[string]interface{}, error) {
+func (s *chainScore) Ex_getStake(address module.Address, precision *common.HexInt) (map[string]interface{}, error) {
 	if err := s.tryChargeCall(true); err != nil {
 		return nil, err
 	}
+	if err := s.checkAmountPrecision(precision); err != nil {
+		return nil, err
+	}
 	es, err := s.getExtensionState()
 	if err != nil {
 		return nil, err
@@ -145,7 +149,55 @@ func (s *chainScore) Ex_getStake(address module.Address) (map[string]interface{}
 		ia = icstate.GetEmptyAccountSnapshot()
 	}
 	blockHeight := s.cc.BlockHeight()
-	return ia.GetStakeInJSON(blockHeight), nil
+	jso := ia.GetStakeInJSON(blockHeight)
+	revision := s.cc.Revision().Value()
+	if revision >= icmodule.RevisionLegacyUnstakeJSON {
+		for k, v := range ia.GetLegacyUnstakeInJSON(blockHeight) {
+			jso[k] = v
+		}
+	}
+	if revision >= icmodule.RevisionStakeExistsFlag && !ia.Exists() {
+		jso["exists"] = false
+	}
+	if precision != nil {
+		icstate.AddStakeInICX(jso, int(precision.Int64()))
+	}
+	return jso, nil
+}
+
+func (s *chainScore) Ex_getStakeAt(address module.Address, height *common.HexInt) (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	if err := s.checkQueryMode(); err != nil {
+		return nil, err
+	}
+	if !height.IsInt64() {
+		return nil, scoreresult.InvalidParameterError.Errorf("InvalidHeight(height=%s)", height)
+	}
+	h := height.Int64()
+	wss, err := s.cc.GetWorldSnapshotByHeight(h)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, scoreresult.InvalidParameterError.Wrap(err, "StateNotAvailable")
+		}
+		return nil, scoreresult.UnknownFailureError.Wrap(err, "StateNotAvailable")
+	}
+	ess, ok := wss.GetExtensionSnapshot().(*iiss.ExtensionSnapshotImpl)
+	if !ok || ess == nil {
+		return nil, scoreresult.UnknownFailureError.Errorf("NoExtensionSnapshot(height=%d)", h)
+	}
+	es := ess.NewState(true).(*iiss.ExtensionStateImpl)
+	ia := es.State.GetAccountSnapshot(address)
+	if ia == nil {
+		ia = icstate.GetEmptyAccountSnapshot()
+	}
+	jso := ia.GetStakeInJSON(h)
+	for k, v := range s.getDelegationInJSON(ia) {
+		jso[k] = v
+	}
+	jso["blockHeight"] = h
+	return jso, nil
 }
 
 func (s *chainScore) Ex_setDelegation(param []interface{}) error {
@@ -164,10 +216,24 @@ func (s *chainScore) Ex_setDelegation(param []interface{}) error {
 	return es.SetDelegation(cc, ds)
 }
 
-func (s *chainScore) Ex_getDelegation(address module.Address) (map[string]interface{}, error) {
+func (s *chainScore) Ex_moveDelegation(from module.Address, to module.Address, value *common.HexInt) error {
+	if err := s.tryChargeCall(true); err != nil {
+		return err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return err
+	}
+	return es.MoveDelegation(s.newCallContext(s.cc), from, to, value.Value())
+}
+
+func (s *chainScore) Ex_getDelegation(address module.Address, precision *common.HexInt) (map[string]interface{}, error) {
 	if err := s.tryChargeCall(true); err != nil {
 		return nil, err
 	}
+	if err := s.checkAmountPrecision(precision); err != nil {
+		return nil, err
+	}
 	es, err := s.getExtensionState()
 	if err != nil {
 		return nil, err
@@ -177,7 +243,30 @@ func (s *chainScore) Ex_getDelegation(address module.Address) (map[string]interf
 		ia = icstate.GetEmptyAccountSnapshot()
 	}
 
-	return ia.GetDelegationInJSON(), nil
+	jso := s.getDelegationInJSON(ia)
+	if precision != nil {
+		icstate.AddDelegationInICX(jso, int(precision.Int64()))
+	}
+	return jso, nil
+}
+
+// checkAmountPrecision checks the number of digits after the decimal point
+// for amounts formatted in ICX. Nil means no formatted amounts.
+func (s *chainScore) checkAmountPrecision(precision *common.HexInt) error {
+	if precision == nil {
+		return nil
+	}
+	if precision.Sign() < 0 || precision.Cmp(big.NewInt(icutils.ICXDecimals)) > 0 {
+		return scoreresult.InvalidParameterError.Errorf("InvalidPrecision(%s)", precision)
+	}
+	return nil
+}
+
+func (s *chainScore) getDelegationInJSON(ia *icstate.AccountSnapshot) map[string]interface{} {
+	if s.cc.Revision().Value() >= icmodule.RevisionSortedDelegationJSON {
+		return ia.GetSortedDelegationInJSON()
+	}
+	return ia.GetDelegationInJSON()
 }
 
 func (s *chainScore) Ex_registerPRep(name string, email string, website string, country string,
@@ -256,6 +345,65 @@ func (s *chainScore) Ex_getPRep(address module.Address) (map[string]interface{},
 	}
 }
 
+func (s *chainScore) Ex_getTotalStaked() (*big.Int, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	return es.State.GetTotalStake(), nil
+}
+
+func (s *chainScore) Ex_getNetworkStakeRatio() (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	return es.GetNetworkStakeRatio(s.newCallContext(s.cc).GetTotalSupply()), nil
+}
+
+func (s *chainScore) Ex_getUnstakeSlotMax() (*big.Int, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	return big.NewInt(es.GetUnstakeSlotMax(s.cc.Revision().Value())), nil
+}
+
+func (s *chainScore) Ex_getAvailableVotingPower(address module.Address) (*big.Int, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	return es.GetAvailableVotingPower(address), nil
+}
+
+func (s *chainScore) Ex_getBondedRatio(address module.Address) (*big.Int, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	ratio, err := es.GetBondedRatio(address)
+	if err != nil {
+		return nil, scoreresult.InvalidInstanceError.Wrap(err, "Failed to get bonded ratio")
+	}
+	return ratio, nil
+}
+
 func (s *chainScore) Ex_getPReps(startRanking, endRanking *common.HexInt) (map[string]interface{}, error) {
 	if err := s.tryChargeCall(true); err != nil {
 		return nil, err
@@ -283,6 +431,30 @@ func (s *chainScore) Ex_getPReps(startRanking, endRanking *common.HexInt) (map[s
 	return jso, nil
 }
 
+func (s *chainScore) Ex_getPRepsPage(cursor []byte, limit *common.HexInt) (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	var n int
+	if limit != nil {
+		if !limit.IsInt64() || limit.Sign() < 0 || limit.Int64() > icstate.MaxPRepsPageLimit {
+			return nil, scoreresult.InvalidParameterError.Errorf("InvalidLimit(%s)", limit)
+		}
+		n = int(limit.Int64())
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	jso, err := es.GetPRepsPageInJSON(s.newCallContext(s.cc), cursor, n)
+	if err != nil {
+		return nil, scoreresult.InvalidParameterError.Wrapf(
+			err, "Failed to get PReps: cursor=%#x limit=%d", cursor, n,
+		)
+	}
+	return jso, nil
+}
+
 func (s *chainScore) Ex_getMainPReps() (map[string]interface{}, error) {
 	if err := s.tryChargeCall(true); err != nil {
 		return nil, err
@@ -375,6 +547,27 @@ func (s *chainScore) Ex_setBond(bondList []interface{}) error {
 	return nil
 }
 
+func (s *chainScore) Ex_setStakeDelegationBond(
+	stake *common.HexInt, delegationList []interface{}, bondList []interface{},
+) error {
+	if err := s.tryChargeCall(true); err != nil {
+		return err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return err
+	}
+	ds, err := icstate.NewDelegations(delegationList, es.State.GetDelegationSlotMax())
+	if err != nil {
+		return err
+	}
+	bonds, err := icstate.NewBonds(bondList, s.cc.Revision().Value())
+	if err != nil {
+		return err
+	}
+	return es.SetStakeDelegationBond(s.newCallContext(s.cc), stake.Value(), ds, bonds)
+}
+
 func (s *chainScore) Ex_getBond(address module.Address) (map[string]interface{}, error) {
 	if err := s.tryChargeCall(true); err != nil {
 		return nil, err
@@ -386,6 +579,17 @@ func (s *chainScore) Ex_getBond(address module.Address) (map[string]interface{},
 	return es.GetBond(address)
 }
 
+func (s *chainScore) Ex_getBonds(address module.Address) (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	return es.GetBonds(address)
+}
+
 func (s *chainScore) Ex_setBonderList(bonderList []interface{}) error {
 	if err := s.tryChargeCall(true); err != nil {
 		return err
@@ -422,6 +626,27 @@ func (s *chainScore) Ex_getBonderList(address module.Address) (map[string]interf
 	return res, nil
 }
 
+func (s *chainScore) Ex_getPRepDelegators(address module.Address, limit *common.HexInt) (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	if err := s.checkQueryMode(); err != nil {
+		return nil, err
+	}
+	var n int
+	if limit != nil {
+		if !limit.IsInt64() || limit.Sign() < 0 || limit.Int64() > math.MaxInt32 {
+			return nil, scoreresult.InvalidParameterError.Errorf("Invalid limit: %v", limit)
+		}
+		n = int(limit.Int64())
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	return es.GetPRepDelegators(address, n)
+}
+
 var skippedClaimTX, _ = hex.DecodeString("b9eeb235f715b166cf4b91ffcf8cc48a81913896086d30104ffc0cf47eed1cbd")
 
 func (s *chainScore) Ex_claimIScore() error {
@@ -462,7 +687,13 @@ func (s *chainScore) Ex_queryIScore(address module.Address) (map[string]interfac
 	jso := make(map[string]interface{})
 	jso["blockHeight"] = bh
 	jso["iscore"] = is
-	jso["estimatedICX"] = icutils.IScoreToICX(is)
+	if s.cc.Revision().Value() < icmodule.RevisionIScoreICXRatio {
+		jso["estimatedICX"] = icutils.IScoreToICX(is)
+	} else {
+		icx, remainder := es.State.IScoreToICX(is)
+		jso["estimatedICX"] = icx
+		jso["remainder"] = remainder
+	}
 	return jso, nil
 }
 
@@ -480,6 +711,50 @@ func (s *chainScore) Ex_estimateUnstakeLockPeriod() (map[string]interface{}, err
 	}, nil
 }
 
+func (s *chainScore) Ex_estimateUnstake(value *common.HexInt) (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	return es.EstimateUnstake(s.newCallContext(s.cc), value.Value())
+}
+
+func (s *chainScore) Ex_estimateReward(
+	delegator, prep module.Address, amount *common.HexInt,
+) (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	return es.EstimateReward(delegator, prep, amount.Value())
+}
+
+func (s *chainScore) Ex_getStakeForUnstakePeriod(period *common.HexInt) (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	if period == nil || !period.IsInt64() || period.Sign() < 0 {
+		return nil, scoreresult.InvalidParameterError.Errorf("Invalid period: %v", period)
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	cc := s.newCallContext(s.cc)
+	stake, lockPeriod := es.State.GetStakeForUnstakeLockPeriod(
+		cc.Revision().Value(), cc.GetTotalSupply(), period.Int64())
+	return map[string]interface{}{
+		"totalStake":        stake,
+		"unstakeLockPeriod": lockPeriod,
+	}, nil
+}
+
 func (s *chainScore) Ex_getPRepTerm() (map[string]interface{}, error) {
 	if err := s.tryChargeCall(true); err != nil {
 		return nil, err
@@ -552,6 +827,17 @@ func (s *chainScore) Ex_getIISSInfo() (map[string]interface{}, error) {
 	return jso, nil
 }
 
+func (s *chainScore) Ex_getRewardCalcStatus() (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	return es.GetRewardCalcStatus()
+}
+
 func (s *chainScore) Ex_getPRepStats() (map[string]interface{}, error) {
 	if err := s.tryChargeCall(true); err != nil {
 		return nil, err
@@ -942,6 +1228,111 @@ func (s *chainScore) Ex_setMinimumBond(nBond *big.Int) error {
 	return es.SetMinimumBond(s.newCallContext(s.cc), nBond)
 }
 
+func (s *chainScore) Ex_setIScoreICXRatio(ratio *big.Int) error {
+	if err := s.checkGovernance(true); err != nil {
+		return err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return err
+	}
+	return es.SetIScoreICXRatio(s.newCallContext(s.cc), ratio)
+}
+
+func (s *chainScore) Ex_setRegistrationBond(bond *big.Int) error {
+	if err := s.checkGovernance(true); err != nil {
+		return err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return err
+	}
+	return es.SetRegistrationBond(s.newCallContext(s.cc), bond)
+}
+
+func (s *chainScore) Ex_getRegistrationBond() (*big.Int, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	return es.State.GetRegistrationBond(), nil
+}
+
+func (s *chainScore) Ex_setMaxValidators(value *common.HexInt) error {
+	if err := s.checkGovernance(true); err != nil {
+		return err
+	}
+	if value == nil || !value.IsInt64() {
+		return scoreresult.InvalidParameterError.Errorf("InvalidMaxValidators(%v)", value)
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return err
+	}
+	return es.SetMaxValidators(s.newCallContext(s.cc), value.Int64())
+}
+
+func (s *chainScore) Ex_getMaxValidators() (int64, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return 0, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return 0, err
+	}
+	return es.State.GetMaxValidators(), nil
+}
+
+func (s *chainScore) Ex_setMinimumDelegation(amount *big.Int) error {
+	if err := s.checkGovernance(true); err != nil {
+		return err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return err
+	}
+	return es.SetMinimumDelegation(s.newCallContext(s.cc), amount)
+}
+
+func (s *chainScore) Ex_getMinimumDelegation() (*big.Int, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	return es.State.GetMinimumDelegation(), nil
+}
+
+func (s *chainScore) Ex_setStakeReductionPolicy(policy *common.HexInt) error {
+	if err := s.checkGovernance(true); err != nil {
+		return err
+	}
+	if policy == nil || !policy.IsInt64() {
+		return scoreresult.InvalidParameterError.Errorf("InvalidStakeReductionPolicy(%v)", policy)
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return err
+	}
+	return es.SetStakeReductionPolicy(s.newCallContext(s.cc), icstate.StakeReductionPolicy(policy.Int64()))
+}
+
+func (s *chainScore) Ex_getStakeReductionPolicy() (int64, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return 0, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return 0, err
+	}
+	return int64(es.State.GetStakeReductionPolicy()), nil
+}
+
 func (s *chainScore) newCallContext(cc contract.CallContext) icmodule.CallContext {
 	return iiss.NewCallContext(cc, s.from)
 }
@@ -972,6 +1363,17 @@ func (s *chainScore) Ex_setCommissionRate(rate int64) error {
 	return es.SetCommissionRate(s.newCallContext(s.cc), icmodule.Rate(rate))
 }
 
+func (s *chainScore) Ex_claimCommission() error {
+	if err := s.tryChargeCall(true); err != nil {
+		return err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return err
+	}
+	return es.ClaimCommission(s.newCallContext(s.cc))
+}
+
 func (s *chainScore) Ex_requestUnjail() error {
 	if err := s.tryChargeCall(true); err != nil {
 		return err
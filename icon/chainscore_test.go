@@ -18,21 +18,58 @@
 package icon
 
 import (
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/scoreresult"
 	"github.com/icon-project/goloop/service/state"
 )
 
 type fakeCallContext struct {
 	contract.CallContext
-	accounts map[string]*fakeAccountState
-	revision module.Revision
+	accounts  map[string]*fakeAccountState
+	revision  module.Revision
+	snapshots map[int64]state.WorldSnapshot
+	es        state.ExtensionState
+	txID      []byte
+}
+
+func (cc *fakeCallContext) GetExtensionState() state.ExtensionState {
+	return cc.es
+}
+
+func (cc *fakeCallContext) Logger() log.Logger {
+	return log.GlobalLogger()
+}
+
+func (cc *fakeCallContext) BlockHeight() int64 {
+	return 0
+}
+
+func (cc *fakeCallContext) TransactionID() []byte {
+	return cc.txID
+}
+
+func (cc *fakeCallContext) GetWorldSnapshotByHeight(height int64) (state.WorldSnapshot, error) {
+	if wss, ok := cc.snapshots[height]; ok {
+		return wss, nil
+	}
+	return nil, errors.NotFoundError.Errorf("PrunedBlock(height=%d)", height)
 }
 
 func (cc *fakeCallContext) GetAccountState(id []byte) state.AccountState {
@@ -61,6 +98,15 @@ func newFakeCallContext() *fakeCallContext {
 	}
 }
 
+type fakeWorldSnapshot struct {
+	state.WorldSnapshot
+	ess state.ExtensionSnapshot
+}
+
+func (wss *fakeWorldSnapshot) GetExtensionSnapshot() state.ExtensionSnapshot {
+	return wss.ess
+}
+
 type fakeAccountState struct {
 	state.AccountState
 	data map[string][]byte
@@ -107,3 +153,274 @@ func TestChainScore_GetAPI(t *testing.T) {
 		})
 	}
 }
+
+func TestChainScore_AllMethodsMetadata(t *testing.T) {
+	cc := newFakeCallContext()
+	score := &chainScore{
+		cc: cc,
+	}
+	metadata, err := score.AllMethodsMetadata()
+	assert.NoError(t, err)
+	assert.Len(t, metadata, len(chainMethods))
+
+	var delegations []map[string]interface{}
+	for i, m := range metadata {
+		assert.Equal(t, chainMethods[i].Name, m["name"])
+		assert.Equal(t, intconv.FormatInt(int64(chainMethods[i].minVer)), m["minRevision"])
+		if chainMethods[i].maxVer == 0 {
+			assert.NotContains(t, m, "maxRevision")
+		} else {
+			assert.Equal(t, intconv.FormatInt(int64(chainMethods[i].maxVer)), m["maxRevision"])
+		}
+		if m["name"] == "setDelegation" {
+			delegations = append(delegations, m)
+		}
+	}
+	assert.Len(t, delegations, 2)
+	assert.Equal(t, intconv.FormatInt(icmodule.Revision12), delegations[0]["maxRevision"])
+	assert.Equal(t, intconv.FormatInt(icmodule.RevisionFixSetDelegation), delegations[1]["minRevision"])
+
+	// metadata matches GetAPI for each revision
+	for i := 1; i <= icmodule.MaxRevision; i++ {
+		cc.revision = icmodule.ValueToRevision(i - 1)
+		_, err = contract.SetRevision(cc, i, false)
+		assert.NoError(t, err)
+
+		available := 0
+		for _, m := range metadata {
+			minRev, _ := intconv.ParseInt(m["minRevision"].(string), 32)
+			maxRev := int64(0)
+			if v, ok := m["maxRevision"]; ok {
+				maxRev, _ = intconv.ParseInt(v.(string), 32)
+			}
+			if minRev <= int64(i) && (maxRev == 0 || int64(i) <= maxRev) {
+				available++
+			}
+		}
+		jso, err := score.GetAPI().ToJSON(module.JSONVersion3)
+		assert.NoError(t, err)
+		assert.Len(t, jso, available, "revision %d", i)
+	}
+}
+
+func TestChainScore_GetStakeAt(t *testing.T) {
+	cc := newFakeCallContext()
+	cc.snapshots = make(map[int64]state.WorldSnapshot)
+	score := &chainScore{
+		cc:    cc,
+		flags: SysNoCharge,
+	}
+	address := common.MustNewAddressFromString("hx1234")
+	es := iiss.NewExtensionSnapshot(db.NewMapDB(), nil).NewState(false).(*iiss.ExtensionStateImpl)
+
+	// stake is changed after height 10
+	assert.NoError(t, es.State.GetAccountState(address).SetStake(big.NewInt(100)))
+	cc.snapshots[10] = &fakeWorldSnapshot{ess: es.GetSnapshot()}
+	assert.NoError(t, es.State.GetAccountState(address).SetStake(big.NewInt(300)))
+	cc.snapshots[20] = &fakeWorldSnapshot{ess: es.GetSnapshot()}
+
+	for _, c := range []struct {
+		height int64
+		stake  int64
+	}{
+		{10, 100},
+		{20, 300},
+	} {
+		jso, err := score.Ex_getStakeAt(address, common.NewHexInt(c.height))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, big.NewInt(c.stake).Cmp(jso["stake"].(*big.Int)), "height=%d", c.height)
+		assert.Equal(t, c.height, jso["blockHeight"])
+		assert.Contains(t, jso, "delegations")
+	}
+
+	// pruned height
+	_, err := score.Ex_getStakeAt(address, common.NewHexInt(5))
+	assert.Error(t, err)
+
+	// not allowed in a transaction
+	cc.txID = []byte{0x01}
+	_, err = score.Ex_getStakeAt(address, common.NewHexInt(10))
+	assert.True(t, scoreresult.AccessDeniedError.Equals(err))
+}
+
+func TestChainScore_GetStakeExists(t *testing.T) {
+	cc := newFakeCallContext()
+	cc.revision = icmodule.ValueToRevision(icmodule.RevisionStakeExistsFlag)
+	score := &chainScore{
+		cc:    cc,
+		flags: SysNoCharge,
+	}
+	es := iiss.NewExtensionSnapshot(db.NewMapDB(), nil).NewState(false).(*iiss.ExtensionStateImpl)
+	cc.es = es
+	staker := common.MustNewAddressFromString("hx1234")
+	cleared := common.MustNewAddressFromString("hx5678")
+	unknown := common.MustNewAddressFromString("hx9abc")
+
+	assert.NoError(t, es.State.GetAccountState(staker).SetStake(big.NewInt(100)))
+	assert.NoError(t, es.State.GetAccountState(cleared).SetStake(big.NewInt(100)))
+	es.State.GetAccountState(cleared).Clear()
+
+	jso, err := score.Ex_getStake(staker, nil)
+	assert.NoError(t, err)
+	assert.Zero(t, big.NewInt(100).Cmp(jso["stake"].(*big.Int)))
+	assert.NotContains(t, jso, "exists")
+
+	for _, addr := range []module.Address{cleared, unknown} {
+		jso, err = score.Ex_getStake(addr, nil)
+		assert.NoError(t, err)
+		assert.Zero(t, jso["stake"].(*big.Int).Sign())
+		assert.Equal(t, false, jso["exists"], "addr=%s", addr)
+	}
+
+	// not returned before the revision
+	cc.revision = icmodule.ValueToRevision(icmodule.RevisionStakeExistsFlag - 1)
+	jso, err = score.Ex_getStake(unknown, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, jso, "exists")
+}
+
+func TestChainScore_GetStakeWithPrecision(t *testing.T) {
+	cc := newFakeCallContext()
+	cc.revision = icmodule.ValueToRevision(icmodule.RevisionAmountPrecisionJSON)
+	score := &chainScore{
+		cc:    cc,
+		flags: SysNoCharge,
+	}
+	es := iiss.NewExtensionSnapshot(db.NewMapDB(), nil).NewState(false).(*iiss.ExtensionStateImpl)
+	cc.es = es
+	staker := common.MustNewAddressFromString("hx1234")
+	stake, _ := new(big.Int).SetString("12345678900000000000", 10)
+	assert.NoError(t, es.State.GetAccountState(staker).SetStake(stake))
+
+	// precision is optional, and accepted only since the revision
+	for _, m := range chainMethods {
+		if m.Name != "getStake" && m.Name != "getDelegation" {
+			continue
+		}
+		_, err := m.ConvertParamsToTypedObj([]byte(`{"address":"hx0000000000000000000000000000000000001234"}`), false)
+		assert.NoError(t, err)
+		_, err = m.ConvertParamsToTypedObj([]byte(`{"address":"hx0000000000000000000000000000000000001234","precision":"0x2"}`), false)
+		if m.minVer >= icmodule.RevisionAmountPrecisionJSON {
+			assert.NoError(t, err)
+		} else {
+			assert.Error(t, err)
+		}
+	}
+
+	// same conversion as the result of icx_call
+	toJSON := func(jso map[string]interface{}) map[string]interface{} {
+		obj, err := common.EncodeAny(jso)
+		assert.NoError(t, err)
+		value, err := common.DecodeAnyForJSON(obj)
+		assert.NoError(t, err)
+		bs, err := json.Marshal(value)
+		assert.NoError(t, err)
+		var out map[string]interface{}
+		assert.NoError(t, json.Unmarshal(bs, &out))
+		return out
+	}
+
+	jso, err := score.Ex_getStake(staker, nil)
+	assert.NoError(t, err)
+	assert.NotContains(t, jso, "stakeInICX")
+
+	jso, err = score.Ex_getStake(staker, common.NewHexInt(2))
+	assert.NoError(t, err)
+	out := toJSON(jso)
+	assert.Equal(t, "0xab54a98ca1890800", out["stake"])
+	assert.Equal(t, "12.34", out["stakeInICX"])
+	assert.Equal(t, "12.34", out["totalStakeInICX"])
+
+	jso, err = score.Ex_getDelegation(staker, common.NewHexInt(4))
+	assert.NoError(t, err)
+	out = toJSON(jso)
+	assert.Equal(t, "0x0", out["totalDelegated"])
+	assert.Equal(t, "0.0000", out["totalDelegatedInICX"])
+	assert.Equal(t, "12.3456", out["votingPowerInICX"])
+
+	for _, p := range []int64{-1, 19} {
+		_, err = score.Ex_getStake(staker, common.NewHexInt(p))
+		assert.True(t, scoreresult.InvalidParameterError.Equals(err), "precision=%d", p)
+		_, err = score.Ex_getDelegation(staker, common.NewHexInt(p))
+		assert.True(t, scoreresult.InvalidParameterError.Equals(err), "precision=%d", p)
+	}
+
+	// the API before the revision is invoked without precision
+	rev := icmodule.RevisionAmountPrecisionJSON - 1
+	cc.revision = icmodule.ValueToRevision(rev)
+	_, err = contract.SetRevision(cc, rev, false)
+	assert.NoError(t, err)
+	apis := score.GetAPI()
+	assert.NoError(t, contract.CheckMethod(score, apis))
+	for _, name := range []string{"getStake", "getDelegation"} {
+		m := apis.GetMethod(name)
+		assert.Equal(t, 1, len(m.Inputs))
+		params, err := m.ConvertParamsToTypedObj([]byte(`{"address":"hx0000000000000000000000000000000000001234"}`), false)
+		assert.NoError(t, err)
+		status, result, _ := contract.Invoke(score, name, params)
+		assert.NoError(t, status)
+		value, err := common.DecodeAnyForJSON(result)
+		assert.NoError(t, err)
+		for k := range value.(map[string]interface{}) {
+			assert.NotContains(t, k, "InICX", "method=%s", name)
+		}
+	}
+}
+
+type genesisChain struct {
+	module.Chain
+}
+
+func (c *genesisChain) CID() int {
+	return 0x1234
+}
+
+func TestChainScore_InstallGenesisPReps(t *testing.T) {
+	owners := []module.Address{
+		common.MustNewAddressFromString("hx0000000000000000000000000000000000000001"),
+		common.MustNewAddressFromString("hx0000000000000000000000000000000000000002"),
+	}
+	node := common.MustNewAddressFromString("hx0000000000000000000000000000000000000011")
+	genesis := fmt.Sprintf(`{
+		"revision": "0x%x",
+		"preps": [
+			{"address":"%s","name":"node1","email":"node1@example.com","website":"https://node1.example.com/","country":"KOR","city":"Seoul","details":"https://node1.example.com/details","p2pEndpoint":"node1.example.com:7100","nodeAddress":"%s","delegation":"0x200"},
+			{"address":"%s","name":"node2","email":"node2@example.com","website":"https://node2.example.com/","country":"KOR","city":"Seoul","details":"https://node2.example.com/details","p2pEndpoint":"node2.example.com:7100","delegation":"0x100"}
+		]
+	}`, icmodule.RevisionIISS, owners[0], node, owners[1])
+
+	dbase := db.NewMapDB()
+	ws := state.NewWorldState(dbase, nil, nil, nil, nil)
+	for _, owner := range owners {
+		ws.GetAccountState(owner.ID()).SetBalance(big.NewInt(0x1000))
+	}
+	wc := state.NewWorldContext(ws, common.NewBlockInfo(0, 0), nil, &platform{})
+	ctx := contract.NewContext(wc, nil, nil, &genesisChain{}, log.New(), nil, eeproxy.ForTransaction)
+	cc := contract.NewCallContext(ctx, big.NewInt(0), false)
+	score := &chainScore{
+		cc:  cc,
+		log: icutils.NewIconLogger(cc.Logger()),
+	}
+	assert.NoError(t, score.Install([]byte(genesis)))
+
+	wc = state.NewWorldContext(ws, common.NewBlockInfo(1, 0), nil, &platform{})
+	wc.UpdateSystemInfo()
+	ctx = contract.NewContext(wc, nil, nil, &genesisChain{}, log.New(), nil, eeproxy.ForQuery)
+	score.cc = contract.NewCallContext(ctx, big.NewInt(0), true)
+	score.flags = SysNoCharge
+	jso, err := score.Ex_getPReps(nil, nil)
+	assert.NoError(t, err)
+	preps := jso["preps"].([]interface{})
+	assert.Equal(t, len(owners), len(preps))
+	for i, p := range preps {
+		prep := p.(map[string]interface{})
+		assert.True(t, owners[i].Equal(prep["address"].(module.Address)))
+		assert.Zero(t, big.NewInt(int64(0x200>>i)).Cmp(prep["delegated"].(*big.Int)))
+	}
+	assert.True(t, node.Equal(preps[0].(map[string]interface{})["nodeAddress"].(module.Address)))
+
+	jso, err = score.Ex_getStake(owners[1], nil)
+	assert.NoError(t, err)
+	assert.Zero(t, big.NewInt(0x100).Cmp(jso["stake"].(*big.Int)))
+	assert.Zero(t, big.NewInt(0xf00).Cmp(ws.GetAccountState(owners[1].ID()).GetBalance()))
+}
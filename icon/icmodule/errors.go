@@ -27,6 +27,9 @@ const (
 	InvalidStateError
 	NotFoundError
 	NotReadyError
+	NotEnoughVotingPowerError
+	TooManyUnbondSlotsError
+	NotEnoughBondError
 )
 
 const (
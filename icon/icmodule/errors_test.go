@@ -33,6 +33,9 @@ func TestErrors(t *testing.T) {
 		InvalidStateError,
 		NotFoundError,
 		NotReadyError,
+		NotEnoughVotingPowerError,
+		TooManyUnbondSlotsError,
+		NotEnoughBondError,
 	}
 	for i, arg := range args {
 		name := fmt.Sprintf("case-%02d", i)
@@ -48,6 +48,7 @@ const (
 	Revision26
 	Revision27
 	Revision28
+	Revision29
 	RevisionReserved
 )
 
@@ -129,6 +130,41 @@ const (
 	RevisionRecoverUnderIssuance = Revision27
 
 	RevisionSetBondRequirementRate = Revision28
+
+	RevisionPRepsPage                = Revision29
+	RevisionBondedRatioAPI           = Revision29
+	RevisionStakeForUnstakePeriodAPI = Revision29
+	RevisionMoveDelegation           = Revision29
+	RevisionIScoreICXRatio           = Revision29
+	RevisionVotingPowerError         = Revision29
+	RevisionTotalStakedAPI           = Revision29
+	RevisionStakeAtAPI               = Revision29
+	RevisionCommissionAccumulator    = Revision29
+	RevisionLegacyUnstakeJSON        = Revision29
+	RevisionGetBondsAPI              = Revision29
+	RevisionUnstakeSlotMaxAPI        = Revision29
+	RevisionAvailableVotingPowerAPI  = Revision29
+	RevisionPRepGradeEvent           = Revision29
+	RevisionEstimateUnstakeAPI       = Revision29
+	RevisionNetworkStakeRatioAPI     = Revision29
+	RevisionStakeExistsFlag          = Revision29
+	RevisionSetStakeDelegationBond   = Revision29
+	RevisionEstimateRewardAPI        = Revision29
+	RevisionEffectiveDelegatedJSON   = Revision29
+	RevisionDelegatorIndex           = Revision29
+	RevisionMaxValidators            = Revision29
+	RevisionRewardCalcStatus         = Revision29
+	RevisionSortedDelegationJSON     = Revision29
+	RevisionRegistrationBond         = Revision29
+	RevisionUnstakeLockPeriodInfo    = Revision29
+	RevisionUnbondOnUnregister       = Revision29
+	RevisionStakeReductionPolicy     = Revision29
+	RevisionMinimumDelegation        = Revision29
+	RevisionUnbondSlotMax            = Revision29
+	RevisionAmountPrecisionJSON      = Revision29
+	RevisionStepRefundCap            = Revision29
+	RevisionNegativeVotingCheck      = Revision29
+	RevisionSlashStakeClamp          = Revision29
 )
 
 var revisionFlags []module.Revision
@@ -149,6 +185,7 @@ var toggleFlagsOnRevision = []struct {
 	{RevisionFixJCLSteps, module.FixJCLSteps},
 	{RevisionChainScoreEventLog, module.ReportConfigureEvents},
 	{RevisionIISS4R1, module.ReportDoubleSign},
+	{RevisionStepRefundCap, module.CapStepRefund},
 }
 
 func init() {
@@ -299,6 +299,7 @@ func (es *ExtensionStateImpl) handleICXIssue(cc icmodule.CallContext, data []byt
 	term := es.State.GetTermSnapshot()
 	if cc.BlockHeight() == term.StartHeight() {
 		EmitTermStartedEvent(cc, term.Sequence(), term.StartHeight(), term.GetEndHeight())
+		EmitPRepGradeChangedEvents(cc, es.State.GetPrevTermSnapshot(), term)
 	}
 	return nil
 }
@@ -444,7 +445,20 @@ func CheckBaseTX(tx module.Transaction) bool {
 	return ok
 }
 
+// OnBaseTx handles the base transaction. Base transaction can't fail as it's
+// made by the system, so any failure is returned as a critical error to abort
+// the transition instead of making a receipt.
 func (es *ExtensionStateImpl) OnBaseTx(cc icmodule.CallContext, data []byte) error {
+	if err := es.onBaseTx(cc, data); err != nil {
+		if errors.IsCritical(err) || errors.ExecutionFailError.Equals(err) {
+			return err
+		}
+		return errors.CriticalUnknownError.Wrap(err, "FailToHandleBaseTx")
+	}
+	return nil
+}
+
+func (es *ExtensionStateImpl) onBaseTx(cc icmodule.CallContext, data []byte) error {
 	if err := es.handleICXIssue(cc, data); err != nil {
 		return err
 	}
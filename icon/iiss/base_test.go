@@ -16,6 +16,12 @@
 package iiss
 
 import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/icon/icmodule"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/contract"
 )
@@ -38,3 +44,17 @@ func (cc *testCallContext) BlockHeight() int64 {
 func (cc *testCallContext) setBlockHeight(blockHeight int64) {
 	cc.blockHeight = blockHeight
 }
+
+func TestExtensionStateImpl_OnBaseTxFailure(t *testing.T) {
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(icmodule.RevisionICON2R2),
+		CallCtxOptionBlockHeight: int64(10),
+	})
+	es := newDummyExtensionState(t)
+
+	// broken base transaction aborts the transition
+	err := es.OnBaseTx(cc, []byte("{broken"))
+	assert.Error(t, err)
+	assert.True(t, errors.IsCritical(err), "err=%+v", err)
+	assert.False(t, errors.CriticalRerunError.Equals(err))
+}
@@ -226,6 +226,59 @@ func processClaim(ctx Context) error {
 	return nil
 }
 
+func processCommissionClaim(ctx Context) error {
+	back := ctx.Back()
+	temp := ctx.Temp()
+	for iter := back.Filter(icstage.CommissionClaimKey.Build()); iter.Has(); iter.Next() {
+		o, key, err := iter.Get()
+		if err != nil {
+			return err
+		}
+		obj := o.(*icobject.Object)
+		if obj.Tag().Type() == icstage.TypeIScoreClaim {
+			claim := icstage.ToIScoreClaim(o)
+			keySplit, err := containerdb.SplitKeys(key)
+			if err != nil {
+				return err
+			}
+			addr, err := common.NewAddress(keySplit[1])
+			if err != nil {
+				return err
+			}
+			commission, err := temp.GetCommission(addr)
+			if err != nil {
+				return err
+			}
+			nCommission := commission.Subtracted(claim.Value())
+			if nCommission.Value().Sign() == -1 {
+				return errors.Errorf("Invalid negative commission for %s. %+v - %+v = %+v",
+					addr, commission, claim, nCommission)
+			}
+			ctx.Logger().Tracef("Claim commission %s. %+v - %+v = %+v", addr, commission, claim, nCommission)
+			if err = temp.SetCommission(addr, nCommission); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// updateCommission accumulates commission of the PRep, which is claimed by claimCommission.
+func updateCommission(ctx Context, addr module.Address, commission *big.Int) error {
+	temp := ctx.Temp()
+	c, err := temp.GetCommission(addr)
+	if err != nil {
+		return err
+	}
+	nc := c.Added(commission)
+	if err = temp.SetCommission(addr, nc); err != nil {
+		return err
+	}
+	ctx.Logger().Tracef("Update commission %s: %+v + %s = %+v", addr, c, commission, nc)
+	ctx.Stats().IncreaseReward(RTPRep, commission)
+	return nil
+}
+
 func processBTP(ctx Context) error {
 	back := ctx.Back()
 	temp := ctx.Temp()
@@ -20,6 +20,7 @@ import (
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/containerdb"
 	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/icon/icmodule"
 	"github.com/icon-project/goloop/icon/iiss/icobject"
 	"github.com/icon-project/goloop/icon/iiss/icreward"
 	"github.com/icon-project/goloop/icon/iiss/icstage"
@@ -42,6 +43,10 @@ func (r *iiss4Reward) Calculate() error {
 		return err
 	}
 
+	if err = processCommissionClaim(r); err != nil {
+		return err
+	}
+
 	if err = r.loadPRepInfo(); err != nil {
 		return err
 	}
@@ -176,8 +181,18 @@ func (r *iiss4Reward) processPrepReward() error {
 		return err
 	}
 
+	accumulate := r.g.GetRevision() >= icmodule.RevisionCommissionAccumulator
 	for _, prep := range r.pi.PReps() {
-		if err = r.UpdateIScore(prep.Owner(), prep.GetReward(), RTPRep); err != nil {
+		if !accumulate {
+			if err = r.UpdateIScore(prep.Owner(), prep.GetReward(), RTPRep); err != nil {
+				return err
+			}
+			continue
+		}
+		if err = r.UpdateIScore(prep.Owner(), prep.Wage(), RTPRep); err != nil {
+			return err
+		}
+		if err = updateCommission(r, prep.Owner(), prep.Commission()); err != nil {
 			return err
 		}
 	}
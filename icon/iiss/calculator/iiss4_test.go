@@ -80,6 +80,10 @@ func (t *testCalculator) UpdateIScore(addr module.Address, reward *big.Int, type
 }
 
 func (t *testCalculator) AddGlobal(electedPRepCount int) error {
+	return t.AddGlobalWithRevision(0, electedPRepCount)
+}
+
+func (t *testCalculator) AddGlobalWithRevision(revision, electedPRepCount int) error {
 	rFund := icstate.NewRewardFund(icstate.RFVersion2)
 	if err := rFund.SetIGlobal(big.NewInt(1_000_000)); err != nil {
 		return err
@@ -93,7 +97,7 @@ func (t *testCalculator) AddGlobal(electedPRepCount int) error {
 	if err := rFund.SetAllocation(alloc); err != nil {
 		return err
 	}
-	return t.stage.AddGlobalV3(0, 0, 99, electedPRepCount, icmodule.ToRate(5),
+	return t.stage.AddGlobalV3(0, revision, 99, electedPRepCount, icmodule.ToRate(5),
 		rFund, big.NewInt(100))
 }
 
@@ -627,3 +631,54 @@ func TestReward(t *testing.T) {
 		})
 	}
 }
+
+func TestReward_AccumulateCommission(t *testing.T) {
+	a1 := common.MustNewAddressFromString("hx1")
+
+	tc := newTestCalculator()
+	assert.NoError(t, tc.AddGlobalWithRevision(icmodule.RevisionCommissionAccumulator, 1))
+	v1 := icreward.NewVotedV2()
+	v1.SetStatus(icmodule.ESEnable)
+	v1.SetCommissionRate(icmodule.ToRate(10))
+	v1.SetBonded(big.NewInt(1000))
+	v1.SetDelegated(big.NewInt(1000))
+	assert.NoError(t, tc.AddVoted(a1, v1))
+	assert.NoError(t, tc.SetDSA(0))
+	assert.NoError(t, tc.SetPublicKey(a1, 0))
+	tc.Build()
+
+	// accrue commission over a term
+	r, err := NewIISS4Reward(tc)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Calculate())
+
+	p := r.pi.GetPRep(icutils.ToKey(a1))
+	assert.Equal(t, 1, p.Commission().Sign())
+	assert.Equal(t, 1, p.Wage().Sign())
+
+	commission, err := tc.temp.GetCommission(a1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, p.Commission().Cmp(commission.Value()))
+	iScore, err := tc.GetIScoreFromTemp(a1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, p.Wage().Cmp(iScore.Value()), "commission is not added to IScore")
+	assert.Equal(t, 0, p.GetReward().Cmp(tc.stats.GetValue(RTPRep)))
+
+	// claim commission
+	_, err = tc.stage.AddCommissionClaim(a1, commission.Value())
+	assert.NoError(t, err)
+	tc.Build()
+	assert.NoError(t, processCommissionClaim(tc))
+	commission, err = tc.temp.GetCommission(a1)
+	assert.NoError(t, err)
+	assert.Nil(t, commission)
+	iScore, err = tc.GetIScoreFromTemp(a1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, p.Wage().Cmp(iScore.Value()))
+
+	// claim more than accumulated
+	_, err = tc.stage.AddCommissionClaim(a1, big.NewInt(1))
+	assert.NoError(t, err)
+	tc.Build()
+	assert.Error(t, processCommissionClaim(tc))
+}
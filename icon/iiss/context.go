@@ -140,7 +140,17 @@ func (ctx *worldContextImpl) AddTotalSupply(amount *big.Int) (*big.Int, error) {
 }
 
 func (ctx *worldContextImpl) SetValidators(validators []module.Validator) error {
-	return ctx.GetValidatorState().Set(validators)
+	vs := ctx.GetValidatorState()
+	old := vs.GetSnapshot()
+	if err := vs.Set(validators); err != nil {
+		return err
+	}
+	added, removed := state.DiffValidators(old, vs.GetSnapshot())
+	if len(added) > 0 || len(removed) > 0 {
+		ctx.tlog.TSystemf("VALIDATORS bh=%d added=%v removed=%v",
+			ctx.BlockHeight(), added, removed)
+	}
+	return nil
 }
 
 func (ctx *worldContextImpl) GetScoreOwner(score module.Address) (module.Address, error) {
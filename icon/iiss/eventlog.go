@@ -23,6 +23,7 @@ import (
 	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/icon/icmodule"
 	"github.com/icon-project/goloop/icon/iiss/icstate"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/state"
 )
@@ -35,6 +36,7 @@ const (
 	EventPenaltyImposed            = "PenaltyImposed(Address,int,int)"
 	EventSlashed                   = "Slashed(Address,Address,int)"
 	EventIScoreClaimedV2           = "IScoreClaimedV2(Address,int,int)"
+	EventCommissionClaimed         = "CommissionClaimed(Address,int,int)"
 	EventPRepIssued                = "PRepIssued(int,int,int,int)"
 	EventICXIssued                 = "ICXIssued(int,int,int,int)"
 	EventTermStarted               = "TermStarted(int,int,int)"
@@ -59,6 +61,12 @@ const (
 	EventRewardFundAllocationSet   = "RewardFundAllocationSet(str,int)"
 	EventNetworkScoreSet           = "NetworkScoreSet(str,Address)"
 	EventBondRequirementRateSet    = "BondRequirementRateSet(int)"
+	EventPRepGradeChanged          = "PRepGradeChanged(Address,int,int)"
+	EventMaxValidatorsSet          = "MaxValidatorsSet(int)"
+	EventRegistrationBondSet       = "RegistrationBondSet(int)"
+	EventStakeReductionPolicySet   = "StakeReductionPolicySet(int)"
+	EventMinimumDelegationSet      = "MinimumDelegationSet(int)"
+	EventIScoreICXRatioSet         = "IScoreICXRatioSet(int)"
 )
 
 func EmitSlashingRateSetEvent(cc icmodule.CallContext, penaltyType icmodule.PenaltyType, rate icmodule.Rate) {
@@ -157,6 +165,19 @@ func EmitIScoreClaimEvent(cc icmodule.CallContext, claim, icx *big.Int) {
 	}
 }
 
+func EmitCommissionClaimEvent(cc icmodule.CallContext, claim, icx *big.Int) {
+	cc.OnEvent(state.SystemAddress,
+		[][]byte{
+			[]byte(EventCommissionClaimed),
+			cc.From().Bytes(),
+		},
+		[][]byte{
+			intconv.BigIntToBytes(claim),
+			intconv.BigIntToBytes(icx),
+		},
+	)
+}
+
 func EmitPRepIssuedEvent(cc icmodule.CallContext, prep *IssuePRepJSON) {
 	if prep != nil {
 		cc.OnEvent(state.SystemAddress,
@@ -308,6 +329,41 @@ func EmitMinimumBondSetEvent(cc icmodule.CallContext, bond *big.Int) {
 	)
 }
 
+func EmitRegistrationBondSetEvent(cc icmodule.CallContext, bond *big.Int) {
+	cc.OnEvent(state.SystemAddress,
+		[][]byte{[]byte(EventRegistrationBondSet)},
+		[][]byte{intconv.BigIntToBytes(bond)},
+	)
+}
+
+func EmitMaxValidatorsSetEvent(cc icmodule.CallContext, value int64) {
+	cc.OnEvent(state.SystemAddress,
+		[][]byte{[]byte(EventMaxValidatorsSet)},
+		[][]byte{intconv.Int64ToBytes(value)},
+	)
+}
+
+func EmitIScoreICXRatioSetEvent(cc icmodule.CallContext, ratio *big.Int) {
+	cc.OnEvent(state.SystemAddress,
+		[][]byte{[]byte(EventIScoreICXRatioSet)},
+		[][]byte{intconv.BigIntToBytes(ratio)},
+	)
+}
+
+func EmitMinimumDelegationSetEvent(cc icmodule.CallContext, amount *big.Int) {
+	cc.OnEvent(state.SystemAddress,
+		[][]byte{[]byte(EventMinimumDelegationSet)},
+		[][]byte{intconv.BigIntToBytes(amount)},
+	)
+}
+
+func EmitStakeReductionPolicySetEvent(cc icmodule.CallContext, policy icstate.StakeReductionPolicy) {
+	cc.OnEvent(state.SystemAddress,
+		[][]byte{[]byte(EventStakeReductionPolicySet)},
+		[][]byte{intconv.Int64ToBytes(int64(policy))},
+	)
+}
+
 func EmitICXBurnedEvent(cc icmodule.CallContext, from module.Address, amount, ts *big.Int) {
 	rev := cc.Revision().Value()
 	if rev < icmodule.RevisionBurnV2 {
@@ -401,3 +457,68 @@ func EmitBondRequirementRateSetEvent(cc icmodule.CallContext, rate icmodule.Rate
 		[][]byte{intconv.Int64ToBytes(rate.NumInt64())},
 	)
 }
+
+func EmitPRepGradeChangedEvent(cc icmodule.CallContext, owner module.Address, oldGrade, newGrade icstate.Grade) {
+	cc.OnEvent(state.SystemAddress,
+		[][]byte{[]byte(EventPRepGradeChanged), owner.Bytes()},
+		[][]byte{
+			intconv.Int64ToBytes(int64(oldGrade)),
+			intconv.Int64ToBytes(int64(newGrade)),
+		},
+	)
+}
+
+// EmitPRepGradeChangedEvents emits PRepGradeChanged events for PReps whose
+// grades differ between prev and term. PReps not elected in a term are
+// regarded as candidates. It does nothing unless prev is the term right
+// before the term.
+func EmitPRepGradeChangedEvents(cc icmodule.CallContext, prev, term *icstate.TermSnapshot) {
+	if cc.Revision().Value() < icmodule.RevisionPRepGradeEvent {
+		return
+	}
+	if prev == nil || term == nil || prev.GetEndHeight()+1 != term.StartHeight() {
+		return
+	}
+	oldGrades := gradesOfTerm(prev)
+	newGrades := gradesOfTerm(term)
+	for _, pss := range term.PRepSnapshots() {
+		key := icutils.ToKey(pss.Owner())
+		newGrade, ok := newGrades[key]
+		if !ok {
+			continue
+		}
+		oldGrade, ok := oldGrades[key]
+		if !ok {
+			oldGrade = icstate.GradeCandidate
+		}
+		if oldGrade != newGrade {
+			EmitPRepGradeChangedEvent(cc, pss.Owner(), oldGrade, newGrade)
+		}
+	}
+	for _, pss := range prev.PRepSnapshots() {
+		key := icutils.ToKey(pss.Owner())
+		oldGrade, ok := oldGrades[key]
+		if !ok {
+			continue
+		}
+		if _, ok = newGrades[key]; !ok {
+			EmitPRepGradeChangedEvent(cc, pss.Owner(), oldGrade, icstate.GradeCandidate)
+		}
+	}
+}
+
+func gradesOfTerm(term *icstate.TermSnapshot) map[string]icstate.Grade {
+	grades := make(map[string]icstate.Grade)
+	if !term.IsDecentralized() {
+		return grades
+	}
+	mainPReps := term.MainPRepCount()
+	for i, pss := range term.PRepSnapshots() {
+		grade := icstate.GradeSub
+		if i < mainPReps {
+			grade = icstate.GradeMain
+		}
+		grades[icutils.ToKey(pss.Owner())] = grade
+	}
+	return grades
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/icon-project/goloop/btp/ntm"
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/containerdb"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
@@ -113,6 +114,15 @@ func (s *ExtensionSnapshotImpl) Flush() error {
 	return nil
 }
 
+// GetStake returns the stake of the account. It returns zero if there is
+// no account.
+func (s *ExtensionSnapshotImpl) GetStake(addr module.Address) *big.Int {
+	if as := s.state.GetAccountSnapshot(addr); as != nil && as.Stake() != nil {
+		return as.Stake()
+	}
+	return new(big.Int)
+}
+
 func (s *ExtensionSnapshotImpl) NewState(readonly bool) state.ExtensionState {
 	logger := icutils.NewIconLogger(nil)
 
@@ -175,6 +185,10 @@ type ExtensionStateImpl struct {
 	Back1  *icstage.State
 	Back2  *icstage.State
 	Reward *icreward.State
+
+	// votingCheckDeferred makes SetStake, SetDelegation and SetBond skip
+	// checking voting power, which is checked once by SetStakeDelegationBond
+	votingCheckDeferred bool
 }
 
 func (es *ExtensionStateImpl) Logger() log.Logger {
@@ -187,6 +201,35 @@ func (es *ExtensionStateImpl) SetLogger(logger log.Logger) {
 	}
 }
 
+// AccountMutation is a change of stake, delegation, bond or unbond of
+// an account. Field is one of icstate.AccountField* and values are totals.
+type AccountMutation struct {
+	Owner    module.Address
+	Field    string
+	OldValue *big.Int
+	NewValue *big.Int
+}
+
+// MutationTracer is called for each AccountMutation for debugging.
+type MutationTracer func(m *AccountMutation)
+
+// SetTracer sets the tracer of account mutations made through the state.
+// nil tracer, the default, disables tracing.
+func (es *ExtensionStateImpl) SetTracer(tracer MutationTracer) {
+	if tracer == nil {
+		es.State.SetAccountObserver(nil)
+		return
+	}
+	es.State.SetAccountObserver(func(owner module.Address, field string, oldValue, newValue *big.Int) {
+		tracer(&AccountMutation{
+			Owner:    owner,
+			Field:    field,
+			OldValue: new(big.Int).Set(oldValue),
+			NewValue: new(big.Int).Set(newValue),
+		})
+	})
+}
+
 func (es *ExtensionStateImpl) GetSnapshot() state.ExtensionSnapshot {
 	return &ExtensionSnapshotImpl{
 		database: es.database,
@@ -224,6 +267,25 @@ func (es *ExtensionStateImpl) CalculationBlockHeight() int64 {
 	return rcInfo.StartHeight()
 }
 
+// GetRewardCalcStatus returns the status of reward calculation recorded in
+// the state. The calculation started at lastCalcHeight is in progress until
+// its result is applied at the start of the next term.
+func (es *ExtensionStateImpl) GetRewardCalcStatus() (map[string]interface{}, error) {
+	rcInfo, err := es.State.GetRewardCalcInfo()
+	if err != nil {
+		return nil, err
+	}
+	g, err := es.Back2.GetGlobal()
+	if err != nil {
+		return nil, err
+	}
+	jso := make(map[string]interface{})
+	jso["lastCalcHeight"] = rcInfo.StartHeight()
+	jso["inProgress"] = g != nil
+	jso["calcResultHash"] = rcInfo.PrevHash()
+	return jso, nil
+}
+
 func (es *ExtensionStateImpl) setNewFront() (err error) {
 	term := es.State.GetTermSnapshot()
 
@@ -285,13 +347,55 @@ func (es *ExtensionStateImpl) GetPRep(address module.Address) *icstate.PRep {
 	return es.State.GetPRepByOwner(address)
 }
 
+// GetTermSnapshot returns the information of the current term.
+// It returns nil if there is no term yet.
+func (es *ExtensionStateImpl) GetTermSnapshot() *icstate.TermInfo {
+	term := es.State.GetTermSnapshot()
+	if term == nil {
+		return nil
+	}
+	return term.Info()
+}
+
 func (es *ExtensionStateImpl) GetPRepInJSON(cc icmodule.CallContext, address module.Address) (map[string]interface{}, error) {
 	prep := es.State.GetPRepByOwner(address)
 	if prep == nil {
 		return nil, errors.Errorf("PRep not found: %s", address)
 	}
 	sc := NewStateContext(cc, es)
-	return prep.ToJSON(sc), nil
+	jso := prep.ToJSON(sc)
+	if cc.Revision().Value() >= icmodule.RevisionCommissionAccumulator {
+		commission, err := es.getCommission(address)
+		if err != nil {
+			return nil, err
+		}
+		jso["accumulatedCommission"] = commission
+	}
+	return jso, nil
+}
+
+func (es *ExtensionStateImpl) GetBondedRatio(address module.Address) (*big.Int, error) {
+	ps := es.State.GetPRepStatusByOwner(address, false)
+	if ps == nil {
+		return nil, errors.Errorf("PRep not found: %s", address)
+	}
+	return ps.GetBondedRatio(), nil
+}
+
+// GetNetworkStakeRatio returns the total stake of the network and its ratio
+// to totalSupply in basis points. The ratio is 0 if totalSupply is not positive.
+func (es *ExtensionStateImpl) GetNetworkStakeRatio(totalSupply *big.Int) map[string]interface{} {
+	totalStake := es.State.GetTotalStake()
+	ratio := new(big.Int)
+	if totalSupply.Sign() > 0 {
+		ratio.Mul(totalStake, big.NewInt(icmodule.DenomInRate))
+		ratio.Div(ratio, totalSupply)
+	}
+	return map[string]interface{}{
+		"totalStake":  totalStake,
+		"totalSupply": new(big.Int).Set(totalSupply),
+		"stakeRatio":  ratio,
+	}
 }
 
 func (es *ExtensionStateImpl) GetPRepsInJSON(cc icmodule.CallContext, start, end int) (map[string]interface{}, error) {
@@ -299,6 +403,38 @@ func (es *ExtensionStateImpl) GetPRepsInJSON(cc icmodule.CallContext, start, end
 	return es.State.GetPRepsInJSON(sc, start, end)
 }
 
+// VerifyPRepConsistency returns inconsistencies found in P-Rep related data.
+func (es *ExtensionStateImpl) VerifyPRepConsistency(cc icmodule.CallContext) []error {
+	sc := NewStateContext(cc, es)
+	return es.State.VerifyPRepConsistency(sc)
+}
+
+// ReconcileAccount checks that totals of delegations, bonds and unbonds of the
+// account match the sums of their lists, and corrects them unless dryRun is
+// true. It returns true if the account is corrected. In dry-run mode, it
+// returns InvalidStateError describing the discrepancies instead.
+func (es *ExtensionStateImpl) ReconcileAccount(owner module.Address, dryRun bool) (bool, error) {
+	if es.State.GetAccountSnapshot(owner) == nil {
+		return false, nil
+	}
+	account := es.State.GetAccountState(owner)
+	ds := account.Discrepancies()
+	if len(ds) == 0 {
+		return false, nil
+	}
+	es.logger.Warnf("ReconcileAccount() owner=%s discrepancies=%v dryRun=%t", owner, ds, dryRun)
+	if dryRun {
+		return false, icmodule.InvalidStateError.Errorf("AccountInconsistent(owner=%s,%v)", owner, ds)
+	}
+	account.Reconcile()
+	return true, nil
+}
+
+func (es *ExtensionStateImpl) GetPRepsPageInJSON(cc icmodule.CallContext, cursor []byte, limit int) (map[string]interface{}, error) {
+	sc := NewStateContext(cc, es)
+	return es.State.GetPRepsPageInJSON(sc, cursor, limit)
+}
+
 func (es *ExtensionStateImpl) GetMainPRepsInJSON(blockHeight int64) (map[string]interface{}, error) {
 	term := es.State.GetTermSnapshot()
 	if term == nil {
@@ -365,6 +501,33 @@ func (es *ExtensionStateImpl) GetSubPRepsInJSON(blockHeight int64) (map[string]i
 	return jso, nil
 }
 
+// MoveDelegation moves the amount of delegation from a P-Rep to another.
+// Like SetDelegation, both of the resulting delegations should be zero or
+// at least the minimum delegation.
+func (es *ExtensionStateImpl) MoveDelegation(cc icmodule.CallContext, from, to module.Address, amount *big.Int) error {
+	ia := es.State.GetAccountSnapshot(cc.From())
+	if ia == nil {
+		ia = icstate.GetEmptyAccountSnapshot()
+	}
+	ds, err := ia.Delegations().Move(from, to, amount, es.State.GetDelegationSlotMax())
+	if err != nil {
+		return err
+	}
+	return es.SetDelegation(cc, ds)
+}
+
+// checkVoting checks that voting of the account isn't broken before changing
+// it, since a negative component of voting lets the account use more stake.
+func checkVoting(cc icmodule.CallContext, account *icstate.AccountState) error {
+	if cc.Revision().Value() < icmodule.RevisionNegativeVotingCheck {
+		return nil
+	}
+	if err := account.CheckVoting(); err != nil {
+		return scoreresult.UnknownFailureError.Wrap(err, "BrokenAccount")
+	}
+	return nil
+}
+
 func (es *ExtensionStateImpl) SetDelegation(cc icmodule.CallContext, ds icstate.Delegations) error {
 
 	var account *icstate.AccountState
@@ -374,11 +537,34 @@ func (es *ExtensionStateImpl) SetDelegation(cc icmodule.CallContext, ds icstate.
 	account = es.State.GetAccountState(from)
 	revision := cc.Revision().Value()
 	replayPRepIllegalDelegated := revision >= icmodule.RevisionSystemSCORE && revision < icmodule.RevisionFixIllegalDelegation
+	if err := checkVoting(cc, account); err != nil {
+		return err
+	}
+
+	if minDelegation := es.State.GetMinimumDelegation(); minDelegation.Sign() > 0 {
+		for _, d := range ds {
+			// zero delegations are removed
+			if d.Amount().Sign() > 0 && d.Amount().Cmp(minDelegation) < 0 {
+				return icmodule.IllegalArgumentError.Errorf(
+					"DelegationBelowMinimum(to=%s,value=%v,min=%v)",
+					d.To(), d.Amount(), minDelegation,
+				)
+			}
+		}
+	}
 
 	using := new(big.Int).Set(ds.GetDelegationAmount())
 	using.Add(using, account.Unbond())
 	using.Add(using, account.Bond())
-	if account.Stake().Cmp(using) < 0 {
+	if !es.votingCheckDeferred && account.Stake().Cmp(using) < 0 {
+		if revision >= icmodule.RevisionVotingPowerError {
+			available := new(big.Int).Sub(account.Stake(), account.Bond())
+			available.Sub(available, account.Unbond())
+			return icmodule.NotEnoughVotingPowerError.Errorf(
+				"NotEnoughVotingPower(delegation=%v,available=%v)",
+				ds.GetDelegationAmount(), available,
+			)
+		}
 		return icmodule.IllegalArgumentError.Errorf("Not enough voting power")
 	}
 
@@ -441,6 +627,11 @@ func (es *ExtensionStateImpl) SetDelegation(cc icmodule.CallContext, ds icstate.
 		es.AppendExtensionLog(dLog)
 	}
 
+	if revision >= icmodule.RevisionDelegatorIndex {
+		if err = es.State.UpdateDelegatorIndex(from, account.Delegations(), ds); err != nil {
+			return scoreresult.UnknownFailureError.Wrapf(err, "Failed to update delegator index")
+		}
+	}
 	account.SetDelegation(ds)
 	if icmodule.RevisionMultipleUnstakes <= revision && revision < icmodule.RevisionFixInvalidUnstake {
 		migrate.ReproduceUnstakeBugForDelegation(cc, es.logger)
@@ -571,6 +762,11 @@ func (es *ExtensionStateImpl) UnregisterPRep(cc icmodule.CallContext) error {
 	owner := cc.From()
 	sc := NewStateContext(cc, es)
 
+	if cc.Revision().Value() >= icmodule.RevisionUnbondOnUnregister {
+		if err = es.unbondSelfBond(cc, owner); err != nil {
+			return err
+		}
+	}
 	if err = es.State.DisablePRep(sc, owner, icstate.Unregistered); err != nil {
 		return scoreresult.InvalidParameterError.Wrapf(err, "Failed to unregister P-Rep %s", owner)
 	}
@@ -582,6 +778,32 @@ func (es *ExtensionStateImpl) UnregisterPRep(cc icmodule.CallContext) error {
 	return nil
 }
 
+// unbondSelfBond moves the bond of the P-Rep to itself into unbonding before
+// unregistering it. It fails without changes if others bond to the P-Rep,
+// as the P-Rep can't be unregistered then.
+func (es *ExtensionStateImpl) unbondSelfBond(cc icmodule.CallContext, owner module.Address) error {
+	ps := es.State.GetPRepStatusByOwner(owner, false)
+	if ps == nil {
+		return scoreresult.InvalidParameterError.Errorf("PRep not found: %s", owner)
+	}
+	bonds := es.State.GetAccountState(owner).Bonds()
+	selfBond := bonds.AmountOf(owner)
+	if selfBond.Sign() == 0 {
+		return nil
+	}
+	if ps.Bonded().Cmp(selfBond) != 0 {
+		return scoreresult.InvalidParameterError.Errorf(
+			"Failed to unregister P-Rep %s: A P-Rep that has a bond can't unregister", owner)
+	}
+	newBonds := make(icstate.Bonds, 0, len(bonds)-1)
+	for _, b := range bonds {
+		if !b.To().Equal(owner) {
+			newBonds = append(newBonds, b.Clone())
+		}
+	}
+	return es.SetBond(cc, newBonds)
+}
+
 func (es *ExtensionStateImpl) DisqualifyPRep(cc icmodule.CallContext, address module.Address) error {
 	blockHeight := cc.BlockHeight()
 	sc := NewStateContext(cc, es)
@@ -622,6 +844,9 @@ func (es *ExtensionStateImpl) SetBond(cc icmodule.CallContext, bonds icstate.Bon
 
 	var account *icstate.AccountState
 	account = es.State.GetAccountState(from)
+	if err := checkVoting(cc, account); err != nil {
+		return err
+	}
 
 	bondAmount := big.NewInt(0)
 	for _, bond := range bonds {
@@ -635,7 +860,8 @@ func (es *ExtensionStateImpl) SetBond(cc icmodule.CallContext, bonds icstate.Bon
 			return scoreresult.InvalidParameterError.Errorf("%s is not in bonder List of %s", from, bond.To())
 		}
 	}
-	if account.Stake().Cmp(new(big.Int).Add(bondAmount, account.Delegating())) == -1 {
+	if !es.votingCheckDeferred &&
+		account.Stake().Cmp(new(big.Int).Add(bondAmount, account.Delegating())) == -1 {
 		return icmodule.IllegalArgumentError.Errorf("Not enough voting power")
 	}
 
@@ -662,15 +888,22 @@ func (es *ExtensionStateImpl) SetBond(cc icmodule.CallContext, bonds icstate.Bon
 
 	account.SetBonds(bonds)
 	unbondingHeight := es.State.GetUnbondingPeriodMultiplier()*es.State.GetTermPeriod() + blockHeight
-	tl, err := account.UpdateUnbonds(delta, unbondingHeight)
+	slotMax := 0
+	if cc.Revision().Value() >= icmodule.RevisionUnbondSlotMax {
+		slotMax = int(es.State.GetUnbondingMax())
+	}
+	tl, err := account.UpdateUnbonds(delta, unbondingHeight, slotMax)
 	if err != nil {
+		if icmodule.TooManyUnbondSlotsError.Equals(err) {
+			return err
+		}
 		return scoreresult.UnknownFailureError.Wrapf(err, "Failed to update unbonds")
 	}
 	unbondingCount := len(account.Unbonds())
 	if unbondingCount > int(es.State.GetUnbondingMax()) {
 		return icmodule.IllegalArgumentError.Errorf("Too many unbonds %d", unbondingCount)
 	}
-	if account.Stake().Cmp(account.UsingStake()) == -1 {
+	if !es.votingCheckDeferred && account.Stake().Cmp(account.UsingStake()) == -1 {
 		return icmodule.IllegalArgumentError.Errorf("Not enough voting power")
 	}
 	for _, timerJobInfo := range tl {
@@ -699,6 +932,140 @@ func (es *ExtensionStateImpl) GetBond(address module.Address) (map[string]interf
 	return a.GetBondInJSON(), nil
 }
 
+func (es *ExtensionStateImpl) GetBonds(address module.Address) (map[string]interface{}, error) {
+	a := es.State.GetAccountSnapshot(address)
+	if a == nil {
+		a = icstate.GetEmptyAccountSnapshot()
+	}
+	return a.GetBondsInJSON(), nil
+}
+
+// GetAvailableVotingPower returns the amount of stake which the account can
+// delegate or bond additionally. It's zero if votes exceed the stake.
+func (es *ExtensionStateImpl) GetAvailableVotingPower(address module.Address) *big.Int {
+	a := es.State.GetAccountSnapshot(address)
+	if a == nil {
+		return new(big.Int)
+	}
+	vp := a.GetVotingPower()
+	if vp.Sign() < 0 {
+		vp.SetInt64(0)
+	}
+	return vp
+}
+
+// EstimateUnstake returns the result of unstaking value by cc.From()
+// at the current block without changing the state.
+func (es *ExtensionStateImpl) EstimateUnstake(cc icmodule.CallContext, value *big.Int) (map[string]interface{}, error) {
+	if value == nil || value.Sign() <= 0 {
+		return nil, scoreresult.InvalidParameterError.Errorf("InvalidValue(value=%v)", value)
+	}
+	from := cc.From()
+	ia := es.State.GetAccountSnapshot(from)
+	if ia == nil {
+		ia = icstate.GetEmptyAccountSnapshot()
+	}
+	if value.Cmp(ia.Stake()) > 0 {
+		return nil, scoreresult.InvalidParameterError.Errorf(
+			"NotEnoughStake(value=%v,stake=%v,from=%v)", value, ia.Stake(), from)
+	}
+	newStake := new(big.Int).Sub(ia.Stake(), value)
+	if usingStake := ia.UsingStake(); newStake.Cmp(usingStake) < 0 {
+		excess := new(big.Int).Sub(usingStake, newStake)
+		if es.State.GetStakeReductionPolicy() != icstate.StakeReductionTrimDelegation ||
+			excess.Cmp(ia.Delegating()) > 0 {
+			return nil, scoreresult.InvalidParameterError.Errorf(
+				"StakeInUse(newStake=%v,usingStake=%v,from=%v)", newStake, usingStake, from)
+		}
+	}
+
+	revision := cc.Revision().Value()
+	lockPeriod := es.State.GetUnstakeLockPeriod(revision, cc.GetTotalSupply())
+	expireHeight := cc.BlockHeight() + lockPeriod
+
+	// Same as increaseUnstake, the last slot is extended if there's no free one
+	unstakes := ia.UnStakes()
+	slotAvailable := len(unstakes) < int(es.GetUnstakeSlotMax(revision))
+	if !slotAvailable && len(unstakes) > 0 {
+		lastExpire := unstakes[len(unstakes)-1].GetExpire()
+		if revision >= icmodule.RevisionMultipleUnstakes && expireHeight <= lastExpire {
+			expireHeight = lastExpire
+		}
+	}
+	return map[string]interface{}{
+		"expireHeight":     expireHeight,
+		"lockPeriodBlocks": lockPeriod,
+		"slotAvailable":    slotAvailable,
+	}, nil
+}
+
+// EstimateReward returns an approximate voter reward of delegator for
+// a term after delegating amount more to prep. It assumes that parameters of
+// the current term and votes of the PReps don't change during the term.
+func (es *ExtensionStateImpl) EstimateReward(
+	delegator, prep module.Address, amount *big.Int,
+) (map[string]interface{}, error) {
+	if amount == nil || amount.Sign() < 0 {
+		return nil, scoreresult.InvalidParameterError.Errorf("InvalidAmount(amount=%v)", amount)
+	}
+	term := es.State.GetTermSnapshot()
+	if term == nil || term.GetIISSVersion() < icstate.IISSVersion4 {
+		return nil, scoreresult.InvalidRequestError.New("NotSupportedIISSVersion")
+	}
+	ps := es.State.GetPRepStatusByOwner(prep, false)
+	pb := es.State.GetPRepBaseByOwner(prep, false)
+	if ps == nil || pb == nil {
+		return nil, scoreresult.InvalidParameterError.Errorf("PRepNotFound(%s)", prep)
+	}
+
+	// votes of the delegator including the current delegation to the PRep
+	votes := new(big.Int).Set(amount)
+	if ia := es.State.GetAccountSnapshot(delegator); ia != nil {
+		for _, d := range ia.Delegations() {
+			if d.To().Equal(prep) {
+				votes.Add(votes, d.Amount())
+			}
+		}
+	}
+	voted := new(big.Int).Add(ps.Bonded(), ps.Delegated())
+	voted.Add(voted, amount)
+	power := icutils.CalcPower(term.BondRequirement(), ps.Bonded(), voted)
+
+	elected := false
+	totalPower := new(big.Int)
+	for _, pss := range term.PRepSnapshots() {
+		if pss.Owner().Equal(prep) {
+			elected = true
+			totalPower.Add(totalPower, power)
+		} else {
+			totalPower.Add(totalPower, pss.Power())
+		}
+	}
+
+	iScore := new(big.Int)
+	if elected && ps.IsActive() && totalPower.Sign() > 0 && voted.Sign() > 0 {
+		// PRep reward for a term, same as the reward calculator
+		reward := term.RewardFund().GetAmount(icstate.KeyIprep)
+		reward.Mul(reward, big.NewInt(term.Period()*icmodule.IScoreICXRatio))
+		reward.Div(reward, big.NewInt(icmodule.MonthBlock))
+		reward.Mul(reward, power)
+		reward.Div(reward, totalPower)
+		reward.Sub(reward, pb.CommissionRate().MulBigInt(reward))
+
+		iScore.Mul(reward, votes)
+		iScore.Div(iScore, voted)
+	}
+	icx, _ := es.State.IScoreToICX(iScore)
+	return map[string]interface{}{
+		"estimatedIScore": iScore,
+		"estimatedICX":    icx,
+		"elected":         elected,
+		"power":           power,
+		"termPeriod":      term.Period(),
+		"approximate":     true,
+	}, nil
+}
+
 func (es *ExtensionStateImpl) AddEventBond(blockHeight int64, from module.Address, delta map[string]*big.Int) (err error) {
 	votes, err := deltaToVotes(delta)
 	if err != nil {
@@ -750,6 +1117,86 @@ func (es *ExtensionStateImpl) GetBonderList(address module.Address) (map[string]
 	return jso, nil
 }
 
+// GetPRepDelegators returns delegators of the PRep sorted by delegation
+// amount in descending order. It returns all delegators if limit is zero.
+// It loads all delegators of the PRep, so it's only for queries.
+func (es *ExtensionStateImpl) GetPRepDelegators(address module.Address, limit int) (map[string]interface{}, error) {
+	if ps := es.State.GetPRepStatusByOwner(address, false); ps == nil {
+		return nil, scoreresult.InvalidParameterError.Errorf("PRepNotFound(%s)", address)
+	}
+	delegators, count := es.State.GetDelegatorsOf(address, limit)
+	ds := make([]interface{}, len(delegators))
+	for i, d := range delegators {
+		ds[i] = d.ToJSON()
+	}
+	return map[string]interface{}{
+		"address":    address,
+		"delegators": ds,
+		"count":      count,
+	}, nil
+}
+
+// MigrateDelegatorIndex builds the reverse index of delegations set before
+// RevisionDelegatorIndex. Delegators are accounts having delegations in the
+// last reward calculation result or delegation events after it, and their
+// current delegations are indexed.
+func (es *ExtensionStateImpl) MigrateDelegatorIndex() error {
+	delegators := make(map[string]*common.Address)
+	if es.Reward != nil {
+		for iter := es.Reward.GetSnapshot().Filter(icreward.DelegatingKey.Build()); iter.Has(); iter.Next() {
+			_, key, err := iter.Get()
+			if err != nil {
+				return err
+			}
+			keySplit, err := containerdb.SplitKeys(key)
+			if err != nil {
+				return err
+			}
+			addr, err := common.NewAddress(keySplit[1])
+			if err != nil {
+				return err
+			}
+			delegators[icutils.ToKey(addr)] = addr
+		}
+	}
+	for _, stage := range []*icstage.State{es.Front, es.Back1, es.Back2} {
+		if stage == nil {
+			continue
+		}
+		for iter := stage.GetSnapshot().Filter(icstage.EventKey.Build()); iter.Has(); iter.Next() {
+			o, _, err := iter.Get()
+			if err != nil {
+				return err
+			}
+			var addr *common.Address
+			switch o.(*icobject.Object).Tag().Type() {
+			case icstage.TypeEventDelegation:
+				addr = icstage.ToEventVote(o).From()
+			case icstage.TypeEventDelegationV2:
+				addr = icstage.ToEventDelegationV2(o).From()
+			default:
+				continue
+			}
+			delegators[icutils.ToKey(addr)] = addr
+		}
+	}
+
+	keys := make([]string, 0, len(delegators))
+	for key := range delegators {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		addr := delegators[key]
+		ds := es.State.GetAccountSnapshot(addr).Delegations()
+		if err := es.State.UpdateDelegatorIndex(addr, nil, ds); err != nil {
+			return err
+		}
+	}
+	es.logger.Infof("MigrateDelegatorIndex: %d delegators", len(keys))
+	return nil
+}
+
 func (es *ExtensionStateImpl) SetGovernanceVariables(from module.Address, irep *big.Int, blockHeight int64) error {
 	pb := es.State.GetPRepBaseByOwner(from, false)
 	if pb == nil {
@@ -982,6 +1429,10 @@ func (es *ExtensionStateImpl) onTermEnd(wc icmodule.WorldContext) error {
 
 	revision := wc.Revision().Value()
 	pcCfg := es.State.GetPRepCountConfig(revision)
+	if revision >= icmodule.RevisionMaxValidators {
+		// Main P-Reps are validators, so they are limited as well
+		pcCfg = es.State.LimitPRepCountConfig(pcCfg)
+	}
 
 	totalSupply := wc.GetTotalSupply()
 	isDecentralized := es.IsDecentralized()
@@ -1024,6 +1475,12 @@ func (es *ExtensionStateImpl) onTermEnd(wc icmodule.WorldContext) error {
 	es.setRrepToTerm(revision, totalSupply, nextTerm)
 
 	es.logger.Debugf(nextTerm.String())
+	if revision >= icmodule.RevisionPRepGradeEvent {
+		// Keep the current term to find grade changes at the start of the next term
+		if err = es.State.SetPrevTermSnapshot(es.State.GetTermSnapshot()); err != nil {
+			return err
+		}
+	}
 	return es.State.SetTermSnapshot(nextTerm.GetSnapshot())
 }
 
@@ -1207,16 +1664,33 @@ func (es *ExtensionStateImpl) handleExtensionLog() error {
 	return nil
 }
 
+// GetUnstakeSlotMax returns the maximum number of unstake slots of an account
+// for the revision. Multiple unstake slots are allowed since
+// RevisionMultipleUnstakes, and the configured value is used after that.
+func (es *ExtensionStateImpl) GetUnstakeSlotMax(revision int) int64 {
+	if revision < icmodule.RevisionMultipleUnstakes {
+		return icmodule.InitialUnstakeSlotMax
+	}
+	return es.State.GetUnstakeSlotMax()
+}
+
 func (es *ExtensionStateImpl) SetStake(cc icmodule.CallContext, v *big.Int) (err error) {
 	from := cc.From()
 	ia := es.State.GetAccountState(from)
+	if err = checkVoting(cc, ia); err != nil {
+		return err
+	}
 
 	usingStake := ia.UsingStake()
-	if v.Cmp(usingStake) < 0 {
-		return scoreresult.InvalidParameterError.Errorf(
-			"Failed to set stake: newStake=%v < usingStake=%v from=%v",
-			v, usingStake, from,
-		)
+	if !es.votingCheckDeferred && v.Cmp(usingStake) < 0 {
+		excess := new(big.Int).Sub(usingStake, v)
+		if es.State.GetStakeReductionPolicy() != icstate.StakeReductionTrimDelegation ||
+			excess.Cmp(ia.Delegating()) > 0 {
+			return scoreresult.InvalidParameterError.Errorf(
+				"Failed to set stake: newStake=%v < usingStake=%v excessVoting=%v from=%v",
+				v, usingStake, excess, from,
+			)
+		}
 	}
 
 	revision := cc.Revision().Value()
@@ -1240,7 +1714,8 @@ func (es *ExtensionStateImpl) SetStake(cc icmodule.CallContext, v *big.Int) (err
 	oldTotalStake := ia.GetTotalStake()
 
 	// update IISS account
-	expireHeight := cc.BlockHeight() + es.State.GetUnstakeLockPeriod(revision, tSupply)
+	lockPeriod := es.State.GetUnstakeLockPeriod(revision, tSupply)
+	expireHeight := cc.BlockHeight() + lockPeriod
 	var tl []icstate.TimerJobInfo
 	switch stakeInc.Sign() {
 	case 0, 1:
@@ -1248,7 +1723,13 @@ func (es *ExtensionStateImpl) SetStake(cc icmodule.CallContext, v *big.Int) (err
 		tl, err = ia.DecreaseUnstake(stakeInc, expireHeight, revision)
 	case -1:
 		slotMax := int(es.State.GetUnstakeSlotMax())
+		if revision >= icmodule.RevisionUnstakeSlotMaxAPI {
+			slotMax = int(es.GetUnstakeSlotMax(revision))
+		}
 		tl, err = ia.IncreaseUnstake(new(big.Int).Abs(stakeInc), expireHeight, slotMax, revision)
+		if err == nil && revision >= icmodule.RevisionUnstakeLockPeriodInfo {
+			ia.SetUnstakeLockPeriod(lockPeriod)
+		}
 	}
 	if err != nil {
 		return scoreresult.UnknownFailureError.Wrapf(
@@ -1279,6 +1760,11 @@ func (es *ExtensionStateImpl) SetStake(cc icmodule.CallContext, v *big.Int) (err
 			stakeInc,
 		)
 	}
+	if excess := ia.ExcessVoting(); excess.Sign() > 0 && !es.votingCheckDeferred {
+		if err = es.trimDelegation(cc, ia, excess); err != nil {
+			return err
+		}
+	}
 
 	// Update the balance
 	totalStake := ia.GetTotalStake()
@@ -1300,6 +1786,64 @@ func (es *ExtensionStateImpl) SetStake(cc icmodule.CallContext, v *big.Int) (err
 	return
 }
 
+// SetStakeDelegationBond sets stake, delegations and bonds of cc.From() at
+// once. Voting power is checked only with the final state, so intermediate
+// states exceeding the stake are allowed. On failure, the changes on the
+// extension state are rolled back.
+func (es *ExtensionStateImpl) SetStakeDelegationBond(
+	cc icmodule.CallContext, stake *big.Int, ds icstate.Delegations, bonds icstate.Bonds,
+) (err error) {
+	if stake == nil || stake.Sign() < 0 {
+		return scoreresult.InvalidParameterError.Errorf("InvalidStake(stake=%v)", stake)
+	}
+	ess := es.GetSnapshot()
+	es.votingCheckDeferred = true
+	defer func() {
+		es.votingCheckDeferred = false
+		if err != nil {
+			es.Reset(ess)
+		}
+	}()
+
+	if err = es.SetDelegation(cc, ds); err != nil {
+		return err
+	}
+	if err = es.SetBond(cc, bonds); err != nil {
+		return err
+	}
+	if err = es.SetStake(cc, stake); err != nil {
+		return err
+	}
+	ia := es.State.GetAccountState(cc.From())
+	if usingStake := ia.UsingStake(); ia.Stake().Cmp(usingStake) < 0 {
+		return icmodule.NotEnoughVotingPowerError.Errorf(
+			"NotEnoughVotingPower(stake=%v,using=%v)", ia.Stake(), usingStake)
+	}
+	return nil
+}
+
+// trimDelegation reduces delegations of the account proportionally so that
+// the total delegation decreases by at least excess.
+func (es *ExtensionStateImpl) trimDelegation(cc icmodule.CallContext, ia *icstate.AccountState, excess *big.Int) error {
+	delegating := ia.Delegating()
+	if excess.Cmp(delegating) > 0 {
+		return scoreresult.InvalidParameterError.Errorf(
+			"Failed to trim delegation: excess=%v > delegating=%v from=%v",
+			excess, delegating, cc.From(),
+		)
+	}
+	remain := new(big.Int).Sub(delegating, excess)
+	var ds icstate.Delegations
+	for _, d := range ia.Delegations() {
+		amount := new(big.Int).Mul(d.Amount(), remain)
+		amount.Div(amount, delegating)
+		if amount.Sign() > 0 {
+			ds = append(ds, icstate.NewDelegation(d.Address, amount))
+		}
+	}
+	return es.SetDelegation(cc, ds)
+}
+
 func (es *ExtensionStateImpl) RegisterPRep(cc icmodule.CallContext, info *icstate.PRepInfo) error {
 	var err error
 	from := cc.From()
@@ -1309,6 +1853,11 @@ func (es *ExtensionStateImpl) RegisterPRep(cc icmodule.CallContext, info *icstat
 			err, "Failed to validate regInfo: from=%v", from,
 		)
 	}
+	if cc.Revision().Value() >= icmodule.RevisionRegistrationBond {
+		if err = es.checkRegistrationBond(from); err != nil {
+			return err
+		}
+	}
 
 	// Subtract RegPRepFee from SystemAddress
 	err = cc.Withdraw(state.SystemAddress, icmodule.BigIntRegPRepFee, module.RegPRep)
@@ -1353,6 +1902,98 @@ func (es *ExtensionStateImpl) RegisterPRep(cc icmodule.CallContext, info *icstat
 	return nil
 }
 
+// checkRegistrationBond checks that owner is able to bond the registration
+// bond to itself. Bonds to a P-Rep are allowed only after its registration,
+// so voting power of owner is checked instead of the bond.
+func (es *ExtensionStateImpl) checkRegistrationBond(owner module.Address) error {
+	regBond := es.State.GetRegistrationBond()
+	if regBond.Sign() <= 0 {
+		return nil
+	}
+	votingPower := new(big.Int)
+	if account := es.State.GetAccountSnapshot(owner); account != nil {
+		votingPower = account.GetVotingPower()
+	}
+	if votingPower.Cmp(regBond) < 0 {
+		return icmodule.NotEnoughBondError.Errorf(
+			"NotEnoughBond(owner=%s,votingPower=%d,regBond=%d)", owner, votingPower, regBond)
+	}
+	return nil
+}
+
+// RegisterGenesisPRep registers a P-Rep listed in the genesis without charging
+// the registration fee. If delegation is positive, the owner stakes it and
+// delegates it to itself. Events are recorded at the start of the genesis term.
+func (es *ExtensionStateImpl) RegisterGenesisPRep(
+	cc icmodule.CallContext, info *icstate.PRepInfo, delegation *big.Int) error {
+	var err error
+	from := cc.From()
+
+	term := es.State.GetTermSnapshot()
+	if term == nil {
+		return scoreresult.UnknownFailureError.Errorf("Term is nil")
+	}
+	if err = info.Validate(cc.Revision().Value(), true); err != nil {
+		return scoreresult.InvalidParameterError.Wrapf(
+			err, "Failed to validate regInfo: from=%v", from,
+		)
+	}
+	if err = es.State.RegisterPRep(from, info, icmodule.BigIntInitialIRep, 0); err != nil {
+		return scoreresult.InvalidParameterError.Wrapf(
+			err, "Failed to register PRep: from=%v", from,
+		)
+	}
+	if err = es.AddEventEnable(term.StartHeight(), from, icmodule.ESEnable); err != nil {
+		return scoreresult.UnknownFailureError.Wrapf(
+			err, "Failed to add EventEnable: from=%v", from,
+		)
+	}
+	EmitPRepRegisteredEvent(cc)
+
+	if delegation == nil || delegation.Sign() <= 0 {
+		return nil
+	}
+
+	// stake the delegation
+	ia := es.State.GetAccountState(from)
+	if err = cc.Withdraw(from, delegation, module.Stake); err != nil {
+		return err
+	}
+	if err = ia.SetStake(new(big.Int).Add(ia.Stake(), delegation)); err != nil {
+		return scoreresult.InvalidParameterError.Wrapf(
+			err, "Failed to set stake: from=%v stake=%v", from, delegation,
+		)
+	}
+	tStake := new(big.Int).Add(es.State.GetTotalStake(), delegation)
+	if err = es.State.SetTotalStake(tStake); err != nil {
+		return scoreresult.UnknownFailureError.Wrapf(
+			err, "Failed to set totalStake: from=%v totalStake=%v", from, tStake,
+		)
+	}
+
+	// delegate it to the P-Rep itself
+	ds := icstate.Delegations{
+		icstate.NewDelegation(common.AddressToPtr(from), delegation),
+	}
+	ps := es.State.GetPRepStatusByOwner(from, false)
+	ps.SetDelegated(new(big.Int).Add(ps.Delegated(), delegation))
+	tDelegation := new(big.Int).Add(es.State.GetTotalDelegation(), delegation)
+	if err = es.State.SetTotalDelegation(tDelegation); err != nil {
+		return scoreresult.UnknownFailureError.Wrapf(err, "Failed to update total delegation")
+	}
+	if _, _, _, err = es.addEventDelegation(term.StartHeight(), from, ia.Delegations().Delta(ds)); err != nil {
+		return scoreresult.UnknownFailureError.Wrapf(err, "Failed to add EventDelegation")
+	}
+	if cc.Revision().Value() >= icmodule.RevisionDelegatorIndex {
+		if err = es.State.UpdateDelegatorIndex(from, ia.Delegations(), ds); err != nil {
+			return scoreresult.UnknownFailureError.Wrapf(err, "Failed to update delegator index")
+		}
+	}
+	ia.SetDelegation(ds)
+	EmitDelegationSetEvent(cc, ds)
+	return nil
+}
+
 func (es *ExtensionStateImpl) SetPRep(cc icmodule.CallContext, info *icstate.PRepInfo, fromBTP bool) error {
 	var err error
 	var nodeUpdate bool
@@ -1469,6 +2110,14 @@ func (es *ExtensionStateImpl) GetIScore(from module.Address, revision int, txID
 func (es *ExtensionStateImpl) ClaimIScore(cc icmodule.CallContext) error {
 	from := cc.From()
 
+	if cc.Revision().Value() >= icmodule.RevisionCommissionAccumulator {
+		// commission is accumulated apart from IScore, so P-Reps claiming
+		// IScore are paid their commission as before.
+		if err := es.claimCommission(cc, from, false); err != nil {
+			return err
+		}
+	}
+
 	iScore, err := es.getIScore(from)
 	if err != nil {
 		return err
@@ -1479,7 +2128,13 @@ func (es *ExtensionStateImpl) ClaimIScore(cc icmodule.CallContext) error {
 		return nil
 	}
 
-	icx, remains := new(big.Int).DivMod(iScore, icmodule.BigIntIScoreICXRatio, new(big.Int))
+	revision := cc.Revision().Value()
+	var icx, remains *big.Int
+	if revision >= icmodule.RevisionIScoreICXRatio {
+		icx, remains = es.State.IScoreToICX(iScore)
+	} else {
+		icx, remains = new(big.Int).DivMod(iScore, icmodule.BigIntIScoreICXRatio, new(big.Int))
+	}
 	claim := new(big.Int).Sub(iScore, remains)
 
 	if err = cc.Transfer(cc.Treasury(), from, icx, module.Claim); err != nil {
@@ -1493,7 +2148,6 @@ func (es *ExtensionStateImpl) ClaimIScore(cc icmodule.CallContext) error {
 	// IISS 2.x : do not burn iScore < 1000
 	// IISS 3.x : burn iScore < 1000. To burn remains, set full iScore
 	var ic *icstage.IScoreClaim
-	revision := cc.Revision().Value()
 	if revision < icmodule.RevisionEnableIISS3 {
 		ic, err = es.Front.AddIScoreClaim(from, claim)
 	} else {
@@ -1556,6 +2210,87 @@ func (es *ExtensionStateImpl) getIScore(from module.Address) (*big.Int, error) {
 	return iScore, nil
 }
 
+// ClaimCommission transfers accumulated commission of the PRep to its owner.
+func (es *ExtensionStateImpl) ClaimCommission(cc icmodule.CallContext) error {
+	from := cc.From()
+	if es.State.GetPRepBaseByOwner(from, false) == nil {
+		return icmodule.NotFoundError.Errorf("PRepBaseNotFound(%s)", from)
+	}
+	return es.claimCommission(cc, from, true)
+}
+
+// claimCommission transfers accumulated commission of from. If there is no
+// commission, it emits the event only if emitEmpty is true.
+func (es *ExtensionStateImpl) claimCommission(cc icmodule.CallContext, from module.Address, emitEmpty bool) error {
+	commission, err := es.getCommission(from)
+	if err != nil {
+		return err
+	}
+	if commission.Sign() == 0 {
+		if emitEmpty {
+			EmitCommissionClaimEvent(cc, icmodule.BigIntZero, icmodule.BigIntZero)
+		}
+		return nil
+	}
+
+	// remains are burned like IScore claim of IISS 3.x
+	icx, _ := es.State.IScoreToICX(commission)
+	if err = cc.Transfer(cc.Treasury(), from, icx, module.Claim); err != nil {
+		return scoreresult.InvalidInstanceError.Errorf(
+			"Failed to transfer: from=%v to=%v amount=%v",
+			cc.Treasury(), from, icx,
+		)
+	}
+	if _, err = es.Front.AddCommissionClaim(from, commission); err != nil {
+		return scoreresult.UnknownFailureError.Wrapf(
+			err,
+			"Failed to add commission claim: from=%v",
+			from,
+		)
+	}
+	EmitCommissionClaimEvent(cc, commission, icx)
+	return nil
+}
+
+// getCommission returns accumulated commission which is not claimed yet.
+func (es *ExtensionStateImpl) getCommission(from module.Address) (*big.Int, error) {
+	commission := new(big.Int)
+	if es.Reward == nil {
+		return commission, nil
+	}
+	c, err := es.Reward.GetCommission(from)
+	if err != nil {
+		return nil, scoreresult.UnknownFailureError.Wrapf(
+			err,
+			"Failed to get commission data: from=%v",
+			from,
+		)
+	}
+	if c == nil {
+		return commission, nil
+	}
+
+	commission.Set(c.Value())
+	stages := []*icstage.State{es.Front, es.Back1, es.Back2}
+	for _, stage := range stages {
+		if stage == nil {
+			continue
+		}
+		claim, err := stage.GetCommissionClaim(from)
+		if err != nil {
+			return nil, scoreresult.UnknownFailureError.Wrapf(
+				err,
+				"Failed to get commission claim data: from=%v",
+				from,
+			)
+		}
+		if claim != nil {
+			commission.Sub(commission, claim.Value())
+		}
+	}
+	return commission, nil
+}
+
 func calculateIRep(prepSet icstate.PRepSet) *big.Int {
 	irep := new(big.Int)
 	mainPRepCount := prepSet.GetPRepSize(icstate.GradeMain)
@@ -2074,6 +2809,70 @@ func (es *ExtensionStateImpl) SetMinimumBond(cc icmodule.CallContext, nBond *big
 	return nil
 }
 
+func (es *ExtensionStateImpl) SetRegistrationBond(cc icmodule.CallContext, bond *big.Int) error {
+	if bond == nil || bond.Sign() < 0 {
+		return scoreresult.InvalidParameterError.Errorf("InvalidRegistrationBond(%v)", bond)
+	}
+	if es.State.GetRegistrationBond().Cmp(bond) == 0 {
+		return nil
+	}
+	if err := es.State.SetRegistrationBond(bond); err != nil {
+		return err
+	}
+	EmitRegistrationBondSetEvent(cc, bond)
+	return nil
+}
+
+func (es *ExtensionStateImpl) SetMaxValidators(cc icmodule.CallContext, value int64) error {
+	if es.State.GetMaxValidators() == value {
+		return nil
+	}
+	if err := es.State.SetMaxValidators(value); err != nil {
+		return err
+	}
+	EmitMaxValidatorsSetEvent(cc, value)
+	return nil
+}
+
+func (es *ExtensionStateImpl) SetIScoreICXRatio(cc icmodule.CallContext, ratio *big.Int) error {
+	if ratio == nil || ratio.Sign() <= 0 {
+		return scoreresult.InvalidParameterError.Errorf("InvalidIScoreICXRatio(%v)", ratio)
+	}
+	if es.State.GetIScoreICXRatio().Cmp(ratio) == 0 {
+		return nil
+	}
+	if err := es.State.SetIScoreICXRatio(ratio); err != nil {
+		return err
+	}
+	EmitIScoreICXRatioSetEvent(cc, ratio)
+	return nil
+}
+
+func (es *ExtensionStateImpl) SetMinimumDelegation(cc icmodule.CallContext, amount *big.Int) error {
+	if amount == nil || amount.Sign() < 0 {
+		return scoreresult.InvalidParameterError.Errorf("InvalidMinimumDelegation(%v)", amount)
+	}
+	if es.State.GetMinimumDelegation().Cmp(amount) == 0 {
+		return nil
+	}
+	if err := es.State.SetMinimumDelegation(amount); err != nil {
+		return err
+	}
+	EmitMinimumDelegationSetEvent(cc, amount)
+	return nil
+}
+
+func (es *ExtensionStateImpl) SetStakeReductionPolicy(cc icmodule.CallContext, policy icstate.StakeReductionPolicy) error {
+	if es.State.GetStakeReductionPolicy() == policy {
+		return nil
+	}
+	if err := es.State.SetStakeReductionPolicy(policy); err != nil {
+		return err
+	}
+	EmitStakeReductionPolicySetEvent(cc, policy)
+	return nil
+}
+
 func (es *ExtensionStateImpl) SetBondRequirementRate(cc icmodule.CallContext, rate icmodule.Rate) error {
 	revision := cc.Revision().Value()
 	if revision < icmodule.RevisionSetBondRequirementRate {
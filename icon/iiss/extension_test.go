@@ -9,6 +9,7 @@ import (
 
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/icon/icmodule"
 	"github.com/icon-project/goloop/icon/iiss/icreward"
@@ -16,6 +17,10 @@ import (
 	"github.com/icon-project/goloop/icon/iiss/icstate"
 	"github.com/icon-project/goloop/icon/iiss/icutils"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/trace"
+	"github.com/icon-project/goloop/service/txresult"
 )
 
 func newDummyAddress(value int) module.Address {
@@ -153,6 +158,31 @@ func (cc *mockCallContext) HandleBurn(address module.Address, amount *big.Int) e
 	return nil
 }
 
+func (cc *mockCallContext) Transfer(from module.Address, to module.Address, amount *big.Int, opType module.OpType) error {
+	cc.AddCall("Transfer", from, to, amount, opType)
+	return nil
+}
+
+func (cc *mockCallContext) Treasury() module.Address {
+	return common.MustNewAddressFromString("hx1000000000000000000000000000000000000000")
+}
+
+func (cc *mockCallContext) GetBalance(address module.Address) *big.Int {
+	return icmodule.BigIntICX
+}
+
+func (cc *mockCallContext) GetTotalSupply() *big.Int {
+	return new(big.Int).Mul(icmodule.BigIntICX, big.NewInt(1_000_000))
+}
+
+func (cc *mockCallContext) FrameLogger() *trace.Logger {
+	return trace.NewLogger(log.GlobalLogger(), nil)
+}
+
+func (cc *mockCallContext) GetActiveDSAMask() int64 {
+	return 0
+}
+
 func (cc *mockCallContext) Set(params map[CallCtxOption]interface{}) {
 	for key, value := range params {
 		switch key {
@@ -762,3 +792,1322 @@ func TestExtensionStateImpl_SetPRepCountConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestExtensionStateImpl_RegisterGenesisPRep(t *testing.T) {
+	var err error
+	size := 2
+	rev := icmodule.RevisionIISS
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+		CallCtxOptionBlockHeight: int64(0),
+	})
+	es := newDummyExtensionState(t)
+
+	err = es.GenesisTerm(cc.BlockHeight(), rev)
+	assert.NoError(t, err)
+
+	for i := 0; i < size; i++ {
+		cc.SetFrom(newDummyAddress(i + 1))
+		pi := newDummyPRepInfo(i + 1)
+		err = es.RegisterGenesisPRep(cc, pi, big.NewInt(int64(100*(i+1))))
+		assert.NoError(t, err)
+	}
+	// no registration fee is charged, but the delegation is staked
+	assert.Zero(t, len(cc.GetCalls("HandleBurn")))
+	assert.Equal(t, size, len(cc.GetCalls("Withdraw")))
+
+	cc.IncreaseBlockHeightBy(1)
+	jso, err := es.GetPRepsInJSON(cc, 0, 0)
+	assert.NoError(t, err)
+	preps := jso["preps"].([]interface{})
+	assert.Equal(t, size, len(preps))
+	for i, p := range preps {
+		prep := p.(map[string]interface{})
+		assert.True(t, newDummyAddress(size-i).Equal(prep["address"].(module.Address)))
+		assert.Zero(t, big.NewInt(int64(100*(size-i))).Cmp(prep["delegated"].(*big.Int)))
+	}
+	assert.Zero(t, big.NewInt(300).Cmp(es.State.GetTotalStake()))
+	assert.Zero(t, big.NewInt(300).Cmp(es.State.GetTotalDelegation()))
+
+	// already registered
+	cc.SetFrom(newDummyAddress(1))
+	err = es.RegisterGenesisPRep(cc, newDummyPRepInfo(1), nil)
+	assert.Error(t, err)
+}
+
+func TestExtensionStateImpl_SetStake_ExcessVoting(t *testing.T) {
+	rev := icmodule.RevisionIISS4R1
+	user := newDummyAddress(100)
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+		CallCtxOptionBlockHeight: int64(10),
+		CallCtxOptionFrom:        user,
+	})
+	es := newDummyExtensionState(t)
+	assert.NoError(t, es.State.SetTermPeriod(100))
+	assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+	assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+	assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+
+	err := es.SetStake(cc, big.NewInt(100))
+	assert.NoError(t, err)
+	err = es.SetDelegation(cc, icstate.Delegations{
+		icstate.NewDelegation(common.AddressToPtr(newDummyAddress(1)), big.NewInt(60)),
+		icstate.NewDelegation(common.AddressToPtr(newDummyAddress(2)), big.NewInt(30)),
+	})
+	assert.NoError(t, err)
+	ia := es.State.GetAccountState(user)
+	assert.Zero(t, ia.ExcessVoting().Sign())
+
+	// rejected by default
+	err = es.SetStake(cc, big.NewInt(40))
+	assert.Error(t, err)
+	assert.Zero(t, big.NewInt(100).Cmp(ia.Stake()))
+	assert.Zero(t, big.NewInt(90).Cmp(ia.Delegating()))
+
+	// delegations are reduced proportionally
+	cc.Clear()
+	assert.Error(t, es.SetStakeReductionPolicy(cc, icstate.StakeReductionPolicy(2)))
+	assert.Zero(t, len(cc.GetCalls("OnEvent")))
+	err = es.SetStakeReductionPolicy(cc, icstate.StakeReductionTrimDelegation)
+	assert.NoError(t, err)
+	assert.Equal(t, icstate.StakeReductionTrimDelegation, es.State.GetStakeReductionPolicy())
+	assert.Equal(t, 1, len(cc.GetCalls("OnEvent")))
+	assert.Equal(t, []byte(EventStakeReductionPolicySet), cc.GetCall("OnEvent", 0).Params()[1].([][]byte)[0])
+	err = es.SetStake(cc, big.NewInt(40))
+	assert.NoError(t, err)
+	assert.Zero(t, big.NewInt(40).Cmp(ia.Stake()))
+	assert.Zero(t, ia.ExcessVoting().Sign())
+	ds := ia.Delegations()
+	assert.Equal(t, 2, len(ds))
+	assert.Zero(t, big.NewInt(26).Cmp(ds[0].Amount()))
+	assert.Zero(t, big.NewInt(13).Cmp(ds[1].Amount()))
+	assert.Zero(t, big.NewInt(39).Cmp(ia.Delegating()))
+	assert.Zero(t, big.NewInt(26).Cmp(es.State.GetPRepStatusByOwner(newDummyAddress(1), false).Delegated()))
+}
+
+func TestExtensionStateImpl_SetStake_UnstakeLockPeriod(t *testing.T) {
+	for _, rev := range []int{icmodule.RevisionUnstakeLockPeriodInfo - 1, icmodule.RevisionUnstakeLockPeriodInfo} {
+		t.Run(fmt.Sprintf("Rev%d", rev), func(t *testing.T) {
+			user := newDummyAddress(100)
+			cc := newMockCallContext(map[CallCtxOption]interface{}{
+				CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+				CallCtxOptionBlockHeight: int64(10),
+				CallCtxOptionFrom:        user,
+			})
+			es := newDummyExtensionState(t)
+			assert.NoError(t, es.State.SetTermPeriod(100))
+			assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+			assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+			assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+
+			assert.NoError(t, es.SetStake(cc, big.NewInt(100)))
+			ia := es.State.GetAccountState(user)
+			_, ok := ia.GetStakeInJSON(cc.BlockHeight())["unstakeLockPeriod"]
+			assert.False(t, ok)
+
+			assert.NoError(t, es.SetStake(cc, big.NewInt(60)))
+			period := es.State.GetUnstakeLockPeriod(rev, cc.GetTotalSupply())
+			jso := ia.GetStakeInJSON(cc.BlockHeight())
+			if rev < icmodule.RevisionUnstakeLockPeriodInfo {
+				_, ok = jso["unstakeLockPeriod"]
+				assert.False(t, ok)
+				return
+			}
+			assert.Equal(t, period, jso["unstakeLockPeriod"])
+			us := ia.UnStakes()
+			assert.Equal(t, 1, len(us))
+			assert.Equal(t, cc.BlockHeight()+period, us[0].GetExpire())
+
+			// the period is kept in the stored account
+			es2 := es.GetSnapshot().NewState(true).(*ExtensionStateImpl)
+			assert.Equal(t, period, es2.State.GetAccountSnapshot(user).UnstakeLockPeriod())
+		})
+	}
+}
+
+func TestExtensionStateImpl_SetDelegation_VotingPower(t *testing.T) {
+	user := newDummyAddress(100)
+	prep := common.AddressToPtr(newDummyAddress(1))
+	for _, rev := range []int{icmodule.RevisionVotingPowerError - 1, icmodule.RevisionVotingPowerError} {
+		t.Run(fmt.Sprintf("rev%d", rev), func(t *testing.T) {
+			cc := newMockCallContext(map[CallCtxOption]interface{}{
+				CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+				CallCtxOptionBlockHeight: int64(10),
+				CallCtxOptionFrom:        user,
+			})
+			es := newDummyExtensionState(t)
+			assert.NoError(t, es.State.SetTermPeriod(100))
+			assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+			assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+			assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+
+			// stake: 100, bond: 30
+			assert.NoError(t, es.SetStake(cc, big.NewInt(100)))
+			ia := es.State.GetAccountState(user)
+			ia.SetBonds(icstate.Bonds{icstate.NewBond(prep, big.NewInt(30))})
+
+			// exact boundary
+			err := es.SetDelegation(cc, icstate.Delegations{icstate.NewDelegation(prep, big.NewInt(70))})
+			assert.NoError(t, err)
+			assert.Zero(t, big.NewInt(70).Cmp(ia.Delegating()))
+
+			// above the boundary
+			err = es.SetDelegation(cc, icstate.Delegations{icstate.NewDelegation(prep, big.NewInt(71))})
+			assert.Error(t, err)
+			if rev >= icmodule.RevisionVotingPowerError {
+				assert.Equal(t, icmodule.NotEnoughVotingPowerError, errors.CodeOf(err))
+			} else {
+				assert.Equal(t, icmodule.IllegalArgumentError, errors.CodeOf(err))
+			}
+			assert.Zero(t, big.NewInt(70).Cmp(ia.Delegating()))
+		})
+	}
+}
+
+func TestExtensionStateImpl_TotalStake(t *testing.T) {
+	rev := icmodule.RevisionIISS4R1
+	users := []module.Address{newDummyAddress(100), newDummyAddress(101)}
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+		CallCtxOptionBlockHeight: int64(10),
+	})
+	es := newDummyExtensionState(t)
+	assert.NoError(t, es.State.SetTermPeriod(100))
+	assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+	assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+	assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+
+	steps := []struct {
+		user  int
+		stake int64
+		total int64
+	}{
+		{0, 100, 100},
+		{1, 50, 150},
+		{0, 30, 80},
+		{0, 60, 110},
+		{1, 0, 60},
+		{1, 40, 100},
+	}
+	for i, s := range steps {
+		cc.SetFrom(users[s.user])
+		err := es.SetStake(cc, big.NewInt(s.stake))
+		assert.NoError(t, err, "step=%d", i)
+		assert.Zero(t, big.NewInt(s.total).Cmp(es.State.GetTotalStake()), "step=%d", i)
+	}
+
+	// slashing bond of users[1]
+	owner := newDummyAddress(1)
+	err := es.State.RegisterPRep(owner, newDummyPRepInfo(1), icmodule.BigIntInitialIRep, 0)
+	assert.NoError(t, err)
+	es.State.GetPRepBaseByOwner(owner, false).SetBonderList(icstate.BonderList{common.AddressToPtr(users[1])})
+	ia := es.State.GetAccountState(users[1])
+	ia.SetBonds(icstate.Bonds{icstate.NewBond(common.AddressToPtr(owner), big.NewInt(20))})
+	es.State.GetPRepStatusByOwner(owner, false).SetBonded(big.NewInt(20))
+
+	err = es.slash(cc, owner, icmodule.ToRate(50))
+	assert.NoError(t, err)
+	assert.Zero(t, big.NewInt(30).Cmp(ia.Stake()))
+	assert.Zero(t, big.NewInt(90).Cmp(es.State.GetTotalStake()))
+}
+
+func TestExtensionStateImpl_SlashBrokenStake(t *testing.T) {
+	for _, rev := range []int{icmodule.RevisionSlashStakeClamp - 1, icmodule.RevisionSlashStakeClamp} {
+		t.Run(fmt.Sprintf("rev%d", rev), func(t *testing.T) {
+			user := newDummyAddress(100)
+			cc := newMockCallContext(map[CallCtxOption]interface{}{
+				CallCtxOptionFrom:        user,
+				CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+				CallCtxOptionBlockHeight: int64(10),
+			})
+			es := newDummyExtensionState(t)
+			assert.NoError(t, es.State.SetTermPeriod(100))
+			assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+			assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+			assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+			assert.NoError(t, es.SetStake(cc, big.NewInt(5)))
+
+			// stake is less than bond, which breaks the invariant
+			owner := newDummyAddress(1)
+			err := es.State.RegisterPRep(owner, newDummyPRepInfo(1), icmodule.BigIntInitialIRep, 0)
+			assert.NoError(t, err)
+			es.State.GetPRepBaseByOwner(owner, false).SetBonderList(icstate.BonderList{common.AddressToPtr(user)})
+			ia := es.State.GetAccountState(user)
+			ia.SetBonds(icstate.Bonds{icstate.NewBond(common.AddressToPtr(owner), big.NewInt(20))})
+			es.State.GetPRepStatusByOwner(owner, false).SetBonded(big.NewInt(20))
+
+			err = es.slash(cc, owner, icmodule.ToRate(50))
+			if rev < icmodule.RevisionSlashStakeClamp {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Zero(t, ia.Stake().Sign())
+			assert.Zero(t, es.State.GetTotalStake().Sign())
+		})
+	}
+}
+
+func TestExtensionStateImpl_ClaimCommission(t *testing.T) {
+	owner := common.MustNewAddressFromString("hx1234")
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionFrom:     owner,
+		CallCtxOptionRevision: icmodule.ValueToRevision(icmodule.RevisionCommissionAccumulator),
+	})
+	es := newDummyExtensionState(t)
+
+	// not a PRep
+	assert.Error(t, es.ClaimCommission(cc))
+
+	err := es.State.RegisterPRep(owner, newDummyPRepInfo(1), icmodule.BigIntZero, 0)
+	assert.NoError(t, err)
+
+	// commission accumulated by the calculator
+	accumulated := new(big.Int).Mul(big.NewInt(12), icmodule.BigIntIScoreICXRatio)
+	accumulated.Add(accumulated, big.NewInt(345))
+	assert.NoError(t, es.Reward.SetCommission(owner, icreward.NewIScore(accumulated)))
+
+	jso, err := es.GetPRepInJSON(cc, owner)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, accumulated.Cmp(jso["accumulatedCommission"].(*big.Int)))
+
+	assert.NoError(t, es.ClaimCommission(cc))
+	call := cc.GetCall("Transfer", 0)
+	assert.NotNil(t, call)
+	assert.True(t, owner.Equal(call.Params()[1].(module.Address)))
+	assert.Equal(t, int64(12), call.Params()[2].(*big.Int).Int64())
+	call = cc.GetCall("OnEvent", 0)
+	assert.Equal(t, []byte(EventCommissionClaimed), call.Params()[1].([][]byte)[0])
+
+	commission, err := es.getCommission(owner)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, commission.Sign())
+	jso, err = es.GetPRepInJSON(cc, owner)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, jso["accumulatedCommission"].(*big.Int).Sign())
+
+	// nothing to claim
+	cc.Clear()
+	assert.NoError(t, es.ClaimCommission(cc))
+	assert.Nil(t, cc.GetCalls("Transfer"))
+	assert.Equal(t, 1, len(cc.GetCalls("OnEvent")))
+}
+
+func TestExtensionStateImpl_ClaimIScoreWithCommission(t *testing.T) {
+	owner := common.MustNewAddressFromString("hx1234")
+	es := newDummyExtensionState(t)
+	err := es.State.RegisterPRep(owner, newDummyPRepInfo(1), icmodule.BigIntZero, 0)
+	assert.NoError(t, err)
+	accumulated := new(big.Int).Mul(big.NewInt(12), icmodule.BigIntIScoreICXRatio)
+	assert.NoError(t, es.Reward.SetCommission(owner, icreward.NewIScore(accumulated)))
+
+	// commission is not claimed before the revision
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionFrom:     owner,
+		CallCtxOptionRevision: icmodule.ValueToRevision(icmodule.RevisionCommissionAccumulator - 1),
+	})
+	assert.NoError(t, es.ClaimIScore(cc))
+	assert.Nil(t, cc.GetCalls("Transfer"))
+	commission, err := es.getCommission(owner)
+	assert.NoError(t, err)
+	assert.Zero(t, accumulated.Cmp(commission))
+
+	// claimIScore pays the commission as well
+	cc = newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionFrom:     owner,
+		CallCtxOptionRevision: icmodule.ValueToRevision(icmodule.RevisionCommissionAccumulator),
+	})
+	assert.NoError(t, es.ClaimIScore(cc))
+	call := cc.GetCall("Transfer", 0)
+	assert.NotNil(t, call)
+	assert.True(t, owner.Equal(call.Params()[1].(module.Address)))
+	assert.Equal(t, int64(12), call.Params()[2].(*big.Int).Int64())
+	assert.Equal(t, []byte(EventCommissionClaimed), cc.GetCall("OnEvent", 0).Params()[1].([][]byte)[0])
+	commission, err = es.getCommission(owner)
+	assert.NoError(t, err)
+	assert.Zero(t, commission.Sign())
+
+	// only IScoreClaimed for nothing to claim
+	cc.Clear()
+	assert.NoError(t, es.ClaimIScore(cc))
+	assert.Nil(t, cc.GetCalls("Transfer"))
+	assert.Equal(t, 1, len(cc.GetCalls("OnEvent")))
+}
+
+func TestExtensionStateImpl_ClaimIScoreWithIScoreICXRatio(t *testing.T) {
+	for _, tc := range []struct {
+		rev int
+		icx int64
+	}{
+		{icmodule.RevisionIScoreICXRatio - 1, 12},
+		{icmodule.RevisionIScoreICXRatio, 123},
+	} {
+		t.Run(fmt.Sprintf("Rev%d", tc.rev), func(t *testing.T) {
+			user := newDummyAddress(100)
+			cc := newMockCallContext(map[CallCtxOption]interface{}{
+				CallCtxOptionFrom:     user,
+				CallCtxOptionRevision: icmodule.ValueToRevision(tc.rev),
+			})
+			es := newDummyExtensionState(t)
+			assert.NoError(t, es.Reward.SetIScore(user, icreward.NewIScore(big.NewInt(12345))))
+
+			assert.Error(t, es.SetIScoreICXRatio(cc, big.NewInt(0)))
+			assert.Zero(t, len(cc.GetCalls("OnEvent")))
+			assert.NoError(t, es.SetIScoreICXRatio(cc, big.NewInt(100)))
+			assert.Zero(t, big.NewInt(100).Cmp(es.State.GetIScoreICXRatio()))
+			assert.Equal(t, 1, len(cc.GetCalls("OnEvent")))
+			assert.Equal(t, []byte(EventIScoreICXRatioSet), cc.GetCall("OnEvent", 0).Params()[1].([][]byte)[0])
+
+			cc.Clear()
+			assert.NoError(t, es.ClaimIScore(cc))
+			call := cc.GetCall("Transfer", 0)
+			assert.NotNil(t, call)
+			assert.True(t, user.Equal(call.Params()[1].(module.Address)))
+			assert.Equal(t, tc.icx, call.Params()[2].(*big.Int).Int64())
+		})
+	}
+}
+
+func TestExtensionStateImpl_GetTermSnapshot(t *testing.T) {
+	es := newDummyExtensionState(t)
+	assert.Nil(t, es.GetTermSnapshot())
+
+	assert.NoError(t, es.State.SetTermPeriod(100))
+	assert.NoError(t, es.State.SetIRep(big.NewInt(1000)))
+	assert.NoError(t, es.State.SetRRep(big.NewInt(2000)))
+	term := icstate.GenesisTerm(es.State, 10, icmodule.RevisionIISS)
+	assert.NoError(t, es.State.SetTermSnapshot(term.GetSnapshot()))
+
+	info := es.GetTermSnapshot()
+	assert.NotNil(t, info)
+	assert.Zero(t, info.Sequence)
+	assert.Equal(t, int64(10), info.StartHeight)
+	assert.Equal(t, int64(109), info.EndHeight)
+	assert.Zero(t, big.NewInt(1000).Cmp(info.Irep))
+	assert.Zero(t, big.NewInt(2000).Cmp(info.Rrep))
+	assert.Zero(t, info.MainPRepCount)
+	assert.Zero(t, info.SubPRepCount)
+}
+
+func TestExtensionStateImpl_GetBonds(t *testing.T) {
+	user := newDummyAddress(100)
+	es := newDummyExtensionState(t)
+
+	// no bond
+	jso, err := es.GetBonds(user)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(jso["bonds"].([]interface{})))
+	assert.Equal(t, 0, jso["totalBonded"].(*big.Int).Sign())
+
+	p1 := common.AddressToPtr(newDummyAddress(1))
+	p2 := common.AddressToPtr(newDummyAddress(2))
+	ia := es.State.GetAccountState(user)
+	ia.SetBonds(icstate.Bonds{
+		icstate.NewBond(p1, big.NewInt(30)),
+		icstate.NewBond(p2, big.NewInt(20)),
+	})
+
+	jso, err = es.GetBonds(user)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, big.NewInt(50).Cmp(jso["totalBonded"].(*big.Int)))
+	bonds := jso["bonds"].([]interface{})
+	assert.Equal(t, 2, len(bonds))
+	for i, b := range []*icstate.Bond{icstate.NewBond(p1, big.NewInt(30)), icstate.NewBond(p2, big.NewInt(20))} {
+		bond := bonds[i].(map[string]interface{})
+		assert.True(t, b.To().Equal(bond["address"].(module.Address)))
+		assert.Equal(t, 0, b.Amount().Cmp(bond["value"].(*common.HexInt).Value()))
+	}
+}
+
+func TestExtensionStateImpl_GetUnstakeSlotMax(t *testing.T) {
+	es := newDummyExtensionState(t)
+	assert.NoError(t, es.State.SetUnstakeSlotMax(icmodule.DefaultUnstakeSlotMax))
+
+	rev := icmodule.RevisionMultipleUnstakes
+	assert.Equal(t, int64(icmodule.InitialUnstakeSlotMax), es.GetUnstakeSlotMax(rev-1))
+	assert.Equal(t, int64(icmodule.DefaultUnstakeSlotMax), es.GetUnstakeSlotMax(rev))
+
+	// governance value is used after the revision
+	assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+	assert.Equal(t, int64(icmodule.InitialUnstakeSlotMax), es.GetUnstakeSlotMax(rev-1))
+	assert.Equal(t, int64(10), es.GetUnstakeSlotMax(rev))
+
+	// unstake slots are limited by the revision value
+	for _, tc := range []struct {
+		rev   int
+		slots int
+	}{
+		{rev - 1, 1},
+		{rev, 2},
+	} {
+		ia := es.State.GetAccountState(newDummyAddress(100 + tc.rev))
+		slotMax := int(es.GetUnstakeSlotMax(tc.rev))
+		_, err := ia.IncreaseUnstake(big.NewInt(10), 20, slotMax, tc.rev)
+		assert.NoError(t, err)
+		_, err = ia.IncreaseUnstake(big.NewInt(10), 30, slotMax, tc.rev)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.slots, len(ia.UnStakes()), "rev=%d", tc.rev)
+	}
+}
+
+func TestExtensionStateImpl_SetDelegation_MinimumDelegation(t *testing.T) {
+	rev := icmodule.RevisionIISS4R1
+	user := newDummyAddress(100)
+	p1 := common.AddressToPtr(newDummyAddress(1))
+	p2 := common.AddressToPtr(newDummyAddress(2))
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+		CallCtxOptionBlockHeight: int64(10),
+		CallCtxOptionFrom:        user,
+	})
+	es := newDummyExtensionState(t)
+	assert.NoError(t, es.State.SetTermPeriod(100))
+	assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+	assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+	assert.NoError(t, es.State.SetDelegationSlotMax(10))
+	assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+	assert.NoError(t, es.SetStake(cc, big.NewInt(100)))
+
+	cc.Clear()
+	assert.Error(t, es.SetMinimumDelegation(cc, big.NewInt(-1)))
+	assert.Zero(t, len(cc.GetCalls("OnEvent")))
+	assert.NoError(t, es.SetMinimumDelegation(cc, big.NewInt(10)))
+	assert.Zero(t, big.NewInt(10).Cmp(es.State.GetMinimumDelegation()))
+	assert.Equal(t, 1, len(cc.GetCalls("OnEvent")))
+	assert.Equal(t, []byte(EventMinimumDelegationSet), cc.GetCall("OnEvent", 0).Params()[1].([][]byte)[0])
+	ia := es.State.GetAccountState(user)
+
+	// below the minimum
+	err := es.SetDelegation(cc, icstate.Delegations{
+		icstate.NewDelegation(p1, big.NewInt(20)),
+		icstate.NewDelegation(p2, big.NewInt(9)),
+	})
+	assert.Error(t, err)
+	assert.Equal(t, icmodule.IllegalArgumentError, errors.CodeOf(err))
+	assert.Contains(t, err.Error(), p2.String())
+	assert.Zero(t, ia.Delegating().Sign())
+
+	// at the minimum
+	err = es.SetDelegation(cc, icstate.Delegations{
+		icstate.NewDelegation(p1, big.NewInt(20)),
+		icstate.NewDelegation(p2, big.NewInt(10)),
+	})
+	assert.NoError(t, err)
+	assert.Zero(t, big.NewInt(30).Cmp(ia.Delegating()))
+
+	// moving makes a delegation below the minimum
+	err = es.MoveDelegation(cc, p1, p2, big.NewInt(15))
+	assert.Equal(t, icmodule.IllegalArgumentError, errors.CodeOf(err))
+	assert.Contains(t, err.Error(), p1.String())
+	p3 := common.AddressToPtr(newDummyAddress(3))
+	err = es.MoveDelegation(cc, p1, p3, big.NewInt(5))
+	assert.Equal(t, icmodule.IllegalArgumentError, errors.CodeOf(err))
+	assert.Contains(t, err.Error(), p3.String())
+
+	// moving the whole delegation or leaving the minimum
+	assert.NoError(t, es.MoveDelegation(cc, p1, p2, big.NewInt(10)))
+	assert.NoError(t, es.MoveDelegation(cc, p1, p3, big.NewInt(10)))
+	ds := ia.Delegations()
+	assert.Equal(t, 2, len(ds))
+	assert.Zero(t, big.NewInt(20).Cmp(es.State.GetPRepStatusByOwner(p2, false).Delegated()))
+	assert.Zero(t, big.NewInt(10).Cmp(es.State.GetPRepStatusByOwner(p3, false).Delegated()))
+	assert.Zero(t, big.NewInt(30).Cmp(ia.Delegating()))
+}
+
+func TestExtensionStateImpl_GetAvailableVotingPower(t *testing.T) {
+	user := newDummyAddress(100)
+	prep := common.AddressToPtr(newDummyAddress(1))
+	es := newDummyExtensionState(t)
+
+	// unknown account
+	assert.Zero(t, es.GetAvailableVotingPower(user).Sign())
+
+	ia := es.State.GetAccountState(user)
+	assert.NoError(t, ia.SetStake(big.NewInt(100)))
+	ia.SetBonds(icstate.Bonds{icstate.NewBond(prep, big.NewInt(30))})
+	ia.SetDelegation(icstate.Delegations{icstate.NewDelegation(prep, big.NewInt(20))})
+	assert.Equal(t, int64(50), es.GetAvailableVotingPower(user).Int64())
+
+	// votes exceeding the stake
+	assert.NoError(t, ia.SetStake(big.NewInt(40)))
+	assert.Zero(t, es.GetAvailableVotingPower(user).Sign())
+}
+
+func TestExtensionStateImpl_PRepGradeChangedEvent(t *testing.T) {
+	rev := icmodule.RevisionPRepGradeEvent
+	user := newDummyAddress(100)
+	p1 := newDummyAddress(1)
+	p2 := newDummyAddress(2)
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+		CallCtxOptionBlockHeight: int64(0),
+	})
+	es := newDummyExtensionState(t)
+	assert.NoError(t, es.State.SetTermPeriod(100))
+	assert.NoError(t, es.State.SetMainPRepCount(1))
+	assert.NoError(t, es.State.SetSubPRepCount(1))
+	assert.NoError(t, es.State.SetExtraMainPRepCount(0))
+	assert.NoError(t, es.State.SetBondRequirement(rev, icmodule.ToRate(0)))
+	assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+	assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+	assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+
+	icx := func(v int64) *big.Int {
+		return new(big.Int).Mul(icmodule.BigIntICX, big.NewInt(v))
+	}
+	for i, delegation := range []*big.Int{
+		icx(3000),
+		new(big.Int).Sub(icx(3000), big.NewInt(1)),
+		icx(1000),
+	} {
+		cc.SetFrom(newDummyAddress(i + 1))
+		assert.NoError(t, es.RegisterGenesisPRep(cc, newDummyPRepInfo(i+1), delegation))
+	}
+
+	onTermStart := func() {
+		assert.NoError(t, es.onTermEnd(cc))
+		term := es.State.GetTermSnapshot()
+		cc.SetBlockHeight(term.StartHeight())
+		cc.Clear()
+		EmitPRepGradeChangedEvents(cc, es.State.GetPrevTermSnapshot(), term)
+	}
+	assertGradeChanged := func(idx int, owner module.Address, oldGrade, newGrade icstate.Grade) {
+		call := cc.GetCall("OnEvent", idx)
+		e := &txresult.TestEventLog{
+			Address: call.Params()[0].(module.Address),
+			Indexed: call.Params()[1].([][]byte),
+			Data:    call.Params()[2].([][]byte),
+		}
+		expData := []any{int64(oldGrade), int64(newGrade)}
+		assert.NoError(t, e.Assert(state.SystemAddress, EventPRepGradeChanged, []any{owner}, expData))
+	}
+
+	// decentralization: p1 is main and p2 is sub
+	onTermStart()
+	assert.Equal(t, 2, len(cc.GetCalls("OnEvent")))
+	assertGradeChanged(0, p1, icstate.GradeCandidate, icstate.GradeMain)
+	assertGradeChanged(1, p2, icstate.GradeCandidate, icstate.GradeSub)
+
+	// no change
+	onTermStart()
+	assert.Zero(t, len(cc.GetCalls("OnEvent")))
+
+	// delegation increase promotes p2 to main
+	cc.SetFrom(user)
+	stake := icx(1)
+	assert.NoError(t, es.SetStake(cc, stake))
+	assert.NoError(t, es.SetDelegation(cc, icstate.Delegations{
+		icstate.NewDelegation(common.AddressToPtr(p2), stake),
+	}))
+	onTermStart()
+	assert.Equal(t, 2, len(cc.GetCalls("OnEvent")))
+	assertGradeChanged(0, p2, icstate.GradeSub, icstate.GradeMain)
+	assertGradeChanged(1, p1, icstate.GradeMain, icstate.GradeSub)
+
+	// not emitted before the revision
+	cc.Clear()
+	cc.SetRevision(icmodule.ValueToRevision(rev - 1))
+	EmitPRepGradeChangedEvents(cc, es.State.GetPrevTermSnapshot(), es.State.GetTermSnapshot())
+	assert.Zero(t, len(cc.GetCalls("OnEvent")))
+}
+
+func TestExtensionStateImpl_EstimateUnstake(t *testing.T) {
+	rev := icmodule.RevisionEstimateUnstakeAPI
+	user := newDummyAddress(100)
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+		CallCtxOptionBlockHeight: int64(10),
+		CallCtxOptionFrom:        user,
+	})
+	es := newDummyExtensionState(t)
+	assert.NoError(t, es.State.SetTermPeriod(100))
+	assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+	assert.NoError(t, es.State.SetUnstakeSlotMax(2))
+	assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+	assert.NoError(t, es.SetStake(cc, big.NewInt(100)))
+	lockPeriod := es.State.GetUnstakeLockPeriod(rev, cc.GetTotalSupply())
+
+	// invalid values
+	for _, v := range []*big.Int{nil, big.NewInt(0), big.NewInt(-1), big.NewInt(101)} {
+		_, err := es.EstimateUnstake(cc, v)
+		assert.Error(t, err, "value=%v", v)
+	}
+
+	jso, err := es.EstimateUnstake(cc, big.NewInt(10))
+	assert.NoError(t, err)
+	assert.Equal(t, cc.BlockHeight()+lockPeriod, jso["expireHeight"])
+	assert.Equal(t, lockPeriod, jso["lockPeriodBlocks"])
+	assert.Equal(t, true, jso["slotAvailable"])
+
+	// the state is not changed
+	ia := es.State.GetAccountSnapshot(user)
+	assert.Zero(t, big.NewInt(100).Cmp(ia.Stake()))
+	assert.Zero(t, len(ia.UnStakes()))
+
+	// fill slots, then the last unstake is extended
+	assert.NoError(t, es.SetStake(cc, big.NewInt(90)))
+	cc.IncreaseBlockHeightBy(1)
+	assert.NoError(t, es.SetStake(cc, big.NewInt(80)))
+	cc.IncreaseBlockHeightBy(1)
+	lockPeriod = es.State.GetUnstakeLockPeriod(rev, cc.GetTotalSupply())
+	jso, err = es.EstimateUnstake(cc, big.NewInt(10))
+	assert.NoError(t, err)
+	assert.Equal(t, cc.BlockHeight()+lockPeriod, jso["expireHeight"])
+	assert.Equal(t, false, jso["slotAvailable"])
+
+	// using stake can't be unstaked
+	ia2 := es.State.GetAccountState(user)
+	ia2.SetDelegation(icstate.Delegations{
+		icstate.NewDelegation(common.AddressToPtr(newDummyAddress(1)), big.NewInt(80)),
+	})
+	_, err = es.EstimateUnstake(cc, big.NewInt(10))
+	assert.Error(t, err)
+}
+
+func TestExtensionStateImpl_GetNetworkStakeRatio(t *testing.T) {
+	es := newDummyExtensionState(t)
+	for _, tc := range []struct {
+		totalStake  int64
+		totalSupply int64
+		ratio       int64
+	}{
+		{0, 0, 0},
+		{100, 0, 0},
+		{0, 1000, 0},
+		{250, 1000, 2500},
+		{1, 3, 3333},
+		{1000, 1000, 10000},
+	} {
+		assert.NoError(t, es.State.SetTotalStake(big.NewInt(tc.totalStake)))
+		jso := es.GetNetworkStakeRatio(big.NewInt(tc.totalSupply))
+		assert.Equal(t, tc.totalStake, jso["totalStake"].(*big.Int).Int64())
+		assert.Equal(t, tc.totalSupply, jso["totalSupply"].(*big.Int).Int64())
+		assert.Equal(t, tc.ratio, jso["stakeRatio"].(*big.Int).Int64(), "%+v", tc)
+	}
+}
+
+func TestExtensionStateImpl_SetTracer(t *testing.T) {
+	rev := icmodule.RevisionIISS4R1
+	user := newDummyAddress(100)
+	prep := common.AddressToPtr(newDummyAddress(1))
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+		CallCtxOptionBlockHeight: int64(10),
+		CallCtxOptionFrom:        user,
+	})
+	es := newDummyExtensionState(t)
+	assert.NoError(t, es.State.SetTermPeriod(100))
+	assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+	assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+	assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+
+	var mutations []*AccountMutation
+	es.SetTracer(func(m *AccountMutation) {
+		mutations = append(mutations, m)
+	})
+
+	assert.NoError(t, es.SetStake(cc, big.NewInt(100)))
+	assert.Equal(t, 1, len(mutations))
+	m := mutations[0]
+	assert.True(t, user.Equal(m.Owner))
+	assert.Equal(t, icstate.AccountFieldStake, m.Field)
+	assert.Zero(t, m.OldValue.Sign())
+	assert.Zero(t, big.NewInt(100).Cmp(m.NewValue))
+
+	mutations = nil
+	assert.NoError(t, es.SetStake(cc, big.NewInt(70)))
+	assert.Equal(t, 1, len(mutations))
+	assert.Zero(t, big.NewInt(100).Cmp(mutations[0].OldValue))
+	assert.Zero(t, big.NewInt(70).Cmp(mutations[0].NewValue))
+
+	mutations = nil
+	assert.NoError(t, es.SetDelegation(cc, icstate.Delegations{icstate.NewDelegation(prep, big.NewInt(30))}))
+	assert.Equal(t, 1, len(mutations))
+	assert.Equal(t, icstate.AccountFieldTotalDelegation, mutations[0].Field)
+	assert.Zero(t, big.NewInt(30).Cmp(mutations[0].NewValue))
+
+	// disabled
+	mutations = nil
+	es.SetTracer(nil)
+	assert.NoError(t, es.SetStake(cc, big.NewInt(80)))
+	assert.Zero(t, len(mutations))
+}
+
+func TestExtensionStateImpl_SetStakeDelegationBond(t *testing.T) {
+	rev := icmodule.RevisionSetStakeDelegationBond
+	user := newDummyAddress(100)
+	owner := newDummyAddress(1)
+	prep := common.AddressToPtr(owner)
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+		CallCtxOptionBlockHeight: int64(10),
+		CallCtxOptionFrom:        user,
+	})
+	es := newDummyExtensionState(t)
+	assert.NoError(t, es.State.SetTermPeriod(100))
+	assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+	assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+	assert.NoError(t, es.State.SetUnbondingMax(10))
+	assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+	assert.NoError(t, es.State.RegisterPRep(owner, newDummyPRepInfo(1), icmodule.BigIntInitialIRep, 0))
+	es.State.GetPRepBaseByOwner(owner, false).SetBonderList(icstate.BonderList{common.AddressToPtr(user)})
+
+	assert.NoError(t, es.SetStake(cc, big.NewInt(100)))
+	assert.NoError(t, es.SetDelegation(cc, icstate.Delegations{icstate.NewDelegation(prep, big.NewInt(90))}))
+
+	// bond can't be set before increasing stake
+	bonds := icstate.Bonds{icstate.NewBond(prep, big.NewInt(100))}
+	assert.Error(t, es.SetBond(cc, bonds))
+
+	// but it's valid with the final stake
+	ds := icstate.Delegations{icstate.NewDelegation(prep, big.NewInt(30))}
+	assert.NoError(t, es.SetStakeDelegationBond(cc, big.NewInt(150), ds, bonds))
+	ia := es.State.GetAccountState(user)
+	assert.Zero(t, big.NewInt(150).Cmp(ia.Stake()))
+	assert.Zero(t, big.NewInt(30).Cmp(ia.Delegating()))
+	assert.Zero(t, big.NewInt(100).Cmp(ia.Bond()))
+	ps := es.State.GetPRepStatusByOwner(owner, false)
+	assert.Zero(t, big.NewInt(30).Cmp(ps.Delegated()))
+	assert.Zero(t, big.NewInt(100).Cmp(ps.Bonded()))
+
+	// invalid final state is rolled back entirely
+	err := es.SetStakeDelegationBond(cc, big.NewInt(120), icstate.Delegations{}, icstate.Bonds{
+		icstate.NewBond(prep, big.NewInt(130)),
+	})
+	assert.True(t, icmodule.NotEnoughVotingPowerError.Equals(err))
+	ia = es.State.GetAccountState(user)
+	assert.Zero(t, big.NewInt(150).Cmp(ia.Stake()))
+	assert.Zero(t, big.NewInt(30).Cmp(ia.Delegating()))
+	assert.Zero(t, big.NewInt(100).Cmp(ia.Bond()))
+	assert.Zero(t, len(ia.UnStakes()))
+	ps = es.State.GetPRepStatusByOwner(owner, false)
+	assert.Zero(t, big.NewInt(30).Cmp(ps.Delegated()))
+	assert.Zero(t, big.NewInt(100).Cmp(ps.Bonded()))
+
+	// voting check is not skipped any more
+	assert.Error(t, es.SetBond(cc, icstate.Bonds{icstate.NewBond(prep, big.NewInt(130))}))
+}
+
+func TestExtensionStateImpl_EstimateReward(t *testing.T) {
+	rev := icmodule.RevisionEstimateRewardAPI
+	user := newDummyAddress(100)
+	p2 := newDummyAddress(2)
+	p3 := newDummyAddress(3)
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+		CallCtxOptionBlockHeight: int64(0),
+	})
+	es := newDummyExtensionState(t)
+	assert.NoError(t, es.State.SetTermPeriod(100))
+	assert.NoError(t, es.State.SetMainPRepCount(1))
+	assert.NoError(t, es.State.SetSubPRepCount(1))
+	assert.NoError(t, es.State.SetExtraMainPRepCount(0))
+	assert.NoError(t, es.State.SetBondRequirement(rev, icmodule.ToRate(0)))
+	assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+	assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+
+	icx := func(v int64) *big.Int {
+		return new(big.Int).Mul(icmodule.BigIntICX, big.NewInt(v))
+	}
+	// Iprep for a month is 648,000 ICX, so it's 50 ICX for a term of 100 blocks
+	rf, err := icstate.NewSafeRewardFundV2(icx(1_296_000),
+		icmodule.ToRate(50), icmodule.ToRate(10), icmodule.ToRate(30), icmodule.ToRate(10))
+	assert.NoError(t, err)
+	assert.NoError(t, es.State.SetRewardFund(rf))
+	assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+
+	for i, delegation := range []*big.Int{icx(3000), icx(1000), icx(500)} {
+		cc.SetFrom(newDummyAddress(i + 1))
+		assert.NoError(t, es.RegisterGenesisPRep(cc, newDummyPRepInfo(i+1), delegation))
+	}
+
+	// p1 is main and p2 is sub
+	assert.NoError(t, es.onTermEnd(cc))
+	ci, err := icstate.NewCommissionInfo(icmodule.ToRate(10), icmodule.ToRate(20), icmodule.ToRate(1))
+	assert.NoError(t, err)
+	assert.NoError(t, es.State.GetPRepBaseByOwner(p2, true).InitCommissionInfo(ci))
+
+	// p2 gets 2000 / 5000 of 50 ICX, 18 ICX after commission,
+	// and a half of it goes to the delegator
+	ret, err := es.EstimateReward(user, p2, icx(1000))
+	assert.NoError(t, err)
+	assert.Zero(t, icx(9000).Cmp(ret["estimatedIScore"].(*big.Int)))
+	assert.Zero(t, icx(9).Cmp(ret["estimatedICX"].(*big.Int)))
+	assert.Zero(t, icx(2000).Cmp(ret["power"].(*big.Int)))
+	assert.Equal(t, true, ret["elected"])
+	assert.Equal(t, true, ret["approximate"])
+	assert.Equal(t, int64(100), ret["termPeriod"])
+
+	// current delegation of the delegator is counted
+	ret, err = es.EstimateReward(p2, p2, icx(1000))
+	assert.NoError(t, err)
+	assert.Zero(t, icx(18000).Cmp(ret["estimatedIScore"].(*big.Int)))
+
+	// candidate doesn't get reward
+	ret, err = es.EstimateReward(user, p3, icx(1000))
+	assert.NoError(t, err)
+	assert.Zero(t, ret["estimatedIScore"].(*big.Int).Sign())
+	assert.Equal(t, false, ret["elected"])
+
+	// invalid parameters
+	_, err = es.EstimateReward(user, newDummyAddress(4), icx(1000))
+	assert.Error(t, err)
+	_, err = es.EstimateReward(user, p2, big.NewInt(-1))
+	assert.Error(t, err)
+
+	// state is not changed
+	assert.Zero(t, icx(1000).Cmp(es.State.GetPRepStatusByOwner(p2, false).Delegated()))
+	assert.Nil(t, es.State.GetAccountSnapshot(user))
+}
+
+func TestExtensionStateImpl_GetPRepDelegators(t *testing.T) {
+	rev := icmodule.RevisionDelegatorIndex
+	p1 := newDummyAddress(1)
+	p2 := newDummyAddress(2)
+	users := []module.Address{newDummyAddress(101), newDummyAddress(102), newDummyAddress(103)}
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+		CallCtxOptionBlockHeight: int64(10),
+	})
+	es := newDummyExtensionState(t)
+	assert.NoError(t, es.State.SetTermPeriod(100))
+	assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+	assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+	assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+	for i, owner := range []module.Address{p1, p2} {
+		assert.NoError(t, es.State.RegisterPRep(owner, newDummyPRepInfo(i+1), icmodule.BigIntInitialIRep, 0))
+	}
+	for _, user := range users {
+		cc.SetFrom(user)
+		assert.NoError(t, es.SetStake(cc, big.NewInt(1000)))
+	}
+
+	setDelegation := func(from module.Address, amounts ...int64) {
+		var ds icstate.Delegations
+		for i, amount := range amounts {
+			if amount > 0 {
+				to := common.AddressToPtr(newDummyAddress(i + 1))
+				ds = append(ds, icstate.NewDelegation(to, big.NewInt(amount)))
+			}
+		}
+		cc.SetFrom(from)
+		assert.NoError(t, es.SetDelegation(cc, ds))
+	}
+	assertDelegators := func(prep module.Address, limit int, expected ...interface{}) {
+		jso, err := es.GetPRepDelegators(prep, limit)
+		assert.NoError(t, err)
+		ds := jso["delegators"].([]interface{})
+		assert.Equal(t, len(expected)/2, len(ds))
+		for i := 0; i < len(ds); i++ {
+			d := ds[i].(map[string]interface{})
+			assert.True(t, expected[i*2].(module.Address).Equal(d["address"].(module.Address)))
+			assert.Equal(t, expected[i*2+1].(int64), d["value"].(*big.Int).Int64())
+		}
+	}
+
+	setDelegation(users[0], 100, 200)
+	setDelegation(users[1], 300)
+	setDelegation(users[2], 50, 50)
+	assertDelegators(p1, 0, users[1], int64(300), users[0], int64(100), users[2], int64(50))
+	assertDelegators(p2, 0, users[0], int64(200), users[2], int64(50))
+
+	// truncated by limit, but count has all of them
+	assertDelegators(p1, 2, users[1], int64(300), users[0], int64(100))
+	jso, err := es.GetPRepDelegators(p1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, jso["count"])
+
+	// amount changes and removals
+	setDelegation(users[1], 0, 10)
+	setDelegation(users[0], 400)
+	assertDelegators(p1, 0, users[0], int64(400), users[2], int64(50))
+	assertDelegators(p2, 0, users[2], int64(50), users[1], int64(10))
+
+	setDelegation(users[0])
+	setDelegation(users[2])
+	assertDelegators(p1, 0)
+	assertDelegators(p2, 0, users[1], int64(10))
+
+	// unknown PRep
+	_, err = es.GetPRepDelegators(newDummyAddress(3), 0)
+	assert.Error(t, err)
+}
+
+func TestExtensionStateImpl_MigrateDelegatorIndex(t *testing.T) {
+	rev := icmodule.RevisionDelegatorIndex - 1
+	p1 := newDummyAddress(1)
+	p2 := newDummyAddress(2)
+	users := []module.Address{newDummyAddress(101), newDummyAddress(102), newDummyAddress(103)}
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+		CallCtxOptionBlockHeight: int64(10),
+	})
+	es := newDummyExtensionState(t)
+	assert.NoError(t, es.State.SetTermPeriod(100))
+	assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+	assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+	assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+	for i, owner := range []module.Address{p1, p2} {
+		assert.NoError(t, es.State.RegisterPRep(owner, newDummyPRepInfo(i+1), icmodule.BigIntInitialIRep, 0))
+	}
+	for _, user := range users {
+		cc.SetFrom(user)
+		assert.NoError(t, es.SetStake(cc, big.NewInt(1000)))
+	}
+
+	// delegations set before the revision are not indexed
+	cc.SetFrom(users[0])
+	assert.NoError(t, es.SetDelegation(cc, icstate.Delegations{
+		icstate.NewDelegation(common.AddressToPtr(p1), big.NewInt(100)),
+		icstate.NewDelegation(common.AddressToPtr(p2), big.NewInt(200)),
+	}))
+	cc.SetFrom(users[1])
+	assert.NoError(t, es.SetDelegation(cc, icstate.Delegations{
+		icstate.NewDelegation(common.AddressToPtr(p1), big.NewInt(300)),
+	}))
+	assert.NoError(t, es.SetDelegation(cc, nil))
+	// delegation in the last reward calculation result
+	ds := icstate.Delegations{icstate.NewDelegation(common.AddressToPtr(p1), big.NewInt(50))}
+	es.State.GetAccountState(users[2]).SetDelegation(ds)
+	delegating := icreward.NewDelegating()
+	delegating.Delegations = ds
+	assert.NoError(t, es.Reward.SetDelegating(users[2], delegating))
+
+	delegators, count := es.State.GetDelegatorsOf(p1, 0)
+	assert.Empty(t, delegators)
+	assert.Zero(t, count)
+
+	assert.NoError(t, es.MigrateDelegatorIndex())
+	delegators, count = es.State.GetDelegatorsOf(p1, 0)
+	assert.Equal(t, 2, count)
+	assert.True(t, users[0].Equal(delegators[0].Address))
+	assert.Zero(t, big.NewInt(100).Cmp(delegators[0].Amount))
+	assert.True(t, users[2].Equal(delegators[1].Address))
+	assert.Zero(t, big.NewInt(50).Cmp(delegators[1].Amount))
+	delegators, count = es.State.GetDelegatorsOf(p2, 0)
+	assert.Equal(t, 1, count)
+	assert.True(t, users[0].Equal(delegators[0].Address))
+}
+
+func TestExtensionStateImpl_MaxValidators(t *testing.T) {
+	rev := icmodule.RevisionMaxValidators
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+		CallCtxOptionBlockHeight: int64(0),
+	})
+	es := newDummyExtensionState(t)
+	assert.NoError(t, es.State.SetTermPeriod(100))
+	assert.NoError(t, es.State.SetMainPRepCount(5))
+	assert.NoError(t, es.State.SetSubPRepCount(1))
+	assert.NoError(t, es.State.SetExtraMainPRepCount(0))
+	assert.NoError(t, es.State.SetBondRequirement(rev, icmodule.ToRate(0)))
+	assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+	assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+	assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+
+	for i, delegation := range []int64{2000, 4000, 3000, 6000, 5000, 1000} {
+		cc.SetFrom(newDummyAddress(i + 1))
+		amount := new(big.Int).Mul(icmodule.BigIntICX, big.NewInt(delegation))
+		assert.NoError(t, es.RegisterGenesisPRep(cc, newDummyPRepInfo(i+1), amount))
+	}
+
+	// no limit by default
+	assert.Zero(t, es.State.GetMaxValidators())
+	assert.NoError(t, es.onTermEnd(cc))
+	assert.Equal(t, 5, es.State.GetValidatorsSnapshot().Len())
+
+	cc.Clear()
+	assert.Error(t, es.SetMaxValidators(cc, -1))
+	assert.Error(t, es.SetMaxValidators(cc, icstate.MinMaxValidators-1))
+	assert.Zero(t, len(cc.GetCalls("OnEvent")))
+	assert.NoError(t, es.SetMaxValidators(cc, 4))
+	assert.EqualValues(t, 4, es.State.GetMaxValidators())
+	assert.Equal(t, 1, len(cc.GetCalls("OnEvent")))
+	assert.Equal(t, []byte(EventMaxValidatorsSet), cc.GetCall("OnEvent", 0).Params()[1].([][]byte)[0])
+
+	// P-Reps over the limit become sub P-Reps
+	cc.SetBlockHeight(es.State.GetTermSnapshot().GetEndHeight())
+	assert.NoError(t, es.onTermEnd(cc))
+	vss := es.State.GetValidatorsSnapshot()
+	assert.Equal(t, 4, vss.Len())
+	assert.Equal(t, 4, es.State.GetTermSnapshot().MainPRepCount())
+	for i, idx := range []int{4, 5, 2, 3} {
+		node := es.State.GetNodeByOwner(newDummyAddress(idx))
+		assert.True(t, node.Equal(vss.Get(i)))
+	}
+	for _, idx := range []int{1, 6} {
+		ps := es.State.GetPRepStatusByOwner(newDummyAddress(idx), false)
+		assert.Equal(t, icstate.GradeSub, ps.Grade())
+	}
+
+	// a sub P-Rep over the limit can unregister without changing validators
+	cc.SetBlockHeight(cc.BlockHeight() + 1)
+	cc.SetFrom(newDummyAddress(1))
+	assert.NoError(t, es.UnregisterPRep(cc))
+	assert.True(t, vss.Equal(es.State.GetValidatorsSnapshot()))
+
+	// a disqualified validator is replaced with a sub P-Rep
+	cc.SetFrom(nil)
+	assert.NoError(t, es.DisqualifyPRep(cc, newDummyAddress(2)))
+	vss = es.State.GetValidatorsSnapshot()
+	assert.Equal(t, 4, vss.Len())
+	assert.True(t, es.State.GetNodeByOwner(newDummyAddress(6)).Equal(vss.Get(2)))
+	assert.True(t, vss.IndexOf(es.State.GetNodeByOwner(newDummyAddress(2))) < 0)
+	ps := es.State.GetPRepStatusByOwner(newDummyAddress(6), false)
+	assert.Equal(t, icstate.GradeMain, ps.Grade())
+
+	size, err := es.State.LimitValidators(4, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, size)
+	_, err = es.State.LimitValidators(5, false)
+	assert.Error(t, err)
+	size, err = es.State.LimitValidators(5, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, size)
+}
+
+func TestExtensionStateImpl_GetRewardCalcStatus(t *testing.T) {
+	es := newDummyExtensionState(t)
+
+	jso, err := es.GetRewardCalcStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), jso["lastCalcHeight"])
+	assert.Equal(t, false, jso["inProgress"])
+	assert.Nil(t, jso["calcResultHash"])
+
+	// calculation of the term started at 100 is done and the next one started at 200
+	hash := []byte("calculation-result")
+	rcInfo, err := es.State.GetRewardCalcInfo()
+	assert.NoError(t, err)
+	rcInfo = rcInfo.Clone()
+	rcInfo.Update(100, big.NewInt(1000), nil)
+	rcInfo.Update(200, big.NewInt(2000), hash)
+	assert.NoError(t, es.State.SetRewardCalcInfo(rcInfo))
+	rf := icstate.NewRewardFund(icstate.RFVersion2)
+	assert.NoError(t, es.Back2.AddGlobalV3(100, icmodule.RevisionIISS4R1, 100, 22, icmodule.ToRate(5), rf, big.NewInt(0)))
+
+	jso, err = es.GetRewardCalcStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(200), jso["lastCalcHeight"])
+	assert.Equal(t, true, jso["inProgress"])
+	assert.Equal(t, hash, jso["calcResultHash"])
+}
+
+func TestExtensionStateImpl_RegisterPRepWithRegistrationBond(t *testing.T) {
+	rev := icmodule.RevisionRegistrationBond
+	p1 := newDummyAddress(1)
+	p2 := newDummyAddress(2)
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+		CallCtxOptionBlockHeight: int64(1000),
+	})
+	es := newDummyExtensionState(t)
+	assert.NoError(t, es.State.SetTermPeriod(100))
+	assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+	assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+	assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+	assert.NoError(t, es.SetRegistrationBond(cc, big.NewInt(1000)))
+	assert.Equal(t, []byte(EventRegistrationBondSet), cc.GetCall("OnEvent", 0).Params()[1].([][]byte)[0])
+	assert.Error(t, es.SetRegistrationBond(cc, big.NewInt(-1)))
+
+	// not affected by the minimum bond
+	assert.NoError(t, es.State.SetMinimumBond(big.NewInt(10000)))
+
+	// no stake
+	cc.SetFrom(p1)
+	err := es.RegisterPRep(cc, newDummyPRepInfo(1))
+	assert.True(t, icmodule.NotEnoughBondError.Equals(err))
+	assert.Nil(t, es.State.GetPRepBaseByOwner(p1, false))
+
+	// stake is used by delegation
+	assert.NoError(t, es.SetStake(cc, big.NewInt(1500)))
+	ds := icstate.Delegations{icstate.NewDelegation(common.AddressToPtr(p2), big.NewInt(600))}
+	assert.NoError(t, es.SetDelegation(cc, ds))
+	err = es.RegisterPRep(cc, newDummyPRepInfo(1))
+	assert.True(t, icmodule.NotEnoughBondError.Equals(err))
+
+	// enough voting power to bond the registration bond to itself
+	assert.NoError(t, es.SetDelegation(cc, nil))
+	assert.NoError(t, es.RegisterPRep(cc, newDummyPRepInfo(1)))
+	assert.NotNil(t, es.State.GetPRepBaseByOwner(p1, false))
+
+	// no check without the registration bond
+	assert.NoError(t, es.SetRegistrationBond(cc, new(big.Int)))
+	cc.SetFrom(p2)
+	assert.NoError(t, es.RegisterPRep(cc, newDummyPRepInfo(2)))
+
+	// no check before the revision
+	cc = newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionFrom:        newDummyAddress(3),
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev - 1),
+		CallCtxOptionBlockHeight: int64(1000),
+	})
+	assert.NoError(t, es.State.SetRegistrationBond(big.NewInt(1000)))
+	assert.NoError(t, es.RegisterPRep(cc, newDummyPRepInfo(3)))
+}
+
+func TestExtensionStateImpl_UnregisterPRepWithSelfBond(t *testing.T) {
+	rev := icmodule.RevisionUnbondOnUnregister
+	p1 := newDummyAddress(1)
+	p2 := newDummyAddress(2)
+	user := newDummyAddress(100)
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+		CallCtxOptionBlockHeight: int64(1000),
+	})
+	es := newDummyExtensionState(t)
+	assert.NoError(t, es.State.SetTermPeriod(100))
+	assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+	assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+	assert.NoError(t, es.State.SetUnbondingMax(10))
+	assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+
+	selfBond := func(i int, amount int64) {
+		owner := newDummyAddress(i)
+		cc.SetFrom(owner)
+		assert.NoError(t, es.SetStake(cc, big.NewInt(amount)))
+		assert.NoError(t, es.RegisterPRep(cc, newDummyPRepInfo(i)))
+		es.State.GetPRepBaseByOwner(owner, false).SetBonderList(
+			icstate.BonderList{common.AddressToPtr(owner), common.AddressToPtr(user)})
+		assert.NoError(t, es.SetBond(cc, icstate.Bonds{icstate.NewBond(common.AddressToPtr(owner), big.NewInt(amount))}))
+	}
+	selfBond(1, 1000)
+	selfBond(2, 2000)
+	assert.Zero(t, big.NewInt(3000).Cmp(es.State.GetTotalBond()))
+
+	// self-bond moves to unbonding
+	cc.SetFrom(p1)
+	assert.NoError(t, es.UnregisterPRep(cc))
+	ps := es.State.GetPRepStatusByOwner(p1, false)
+	assert.Equal(t, icstate.Unregistered, ps.Status())
+	assert.Zero(t, ps.Bonded().Sign())
+	assert.Zero(t, big.NewInt(2000).Cmp(es.State.GetTotalBond()))
+	ia := es.State.GetAccountState(p1)
+	assert.Zero(t, ia.Bond().Sign())
+	unbonds := ia.Unbonds()
+	assert.Len(t, unbonds, 1)
+	assert.True(t, unbonds[0].Address().Equal(p1))
+	assert.Zero(t, big.NewInt(1000).Cmp(unbonds[0].Value()))
+	expire := es.State.GetUnbondingPeriodMultiplier()*es.State.GetTermPeriod() + cc.BlockHeight()
+	assert.Equal(t, expire, unbonds[0].Expire())
+
+	// bonded by others, nothing changes
+	cc.SetFrom(user)
+	assert.NoError(t, es.SetStake(cc, big.NewInt(500)))
+	assert.NoError(t, es.SetBond(cc, icstate.Bonds{icstate.NewBond(common.AddressToPtr(p2), big.NewInt(500))}))
+	cc.SetFrom(p2)
+	assert.Error(t, es.UnregisterPRep(cc))
+	ps = es.State.GetPRepStatusByOwner(p2, false)
+	assert.Equal(t, icstate.Active, ps.Status())
+	assert.Zero(t, big.NewInt(2500).Cmp(ps.Bonded()))
+	ia = es.State.GetAccountState(p2)
+	assert.Zero(t, big.NewInt(2000).Cmp(ia.Bond()))
+	assert.Len(t, ia.Unbonds(), 0)
+
+	// self-bonded P-Rep can't unregister before the revision
+	cc = newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionFrom:        newDummyAddress(3),
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev - 1),
+		CallCtxOptionBlockHeight: int64(1000),
+	})
+	selfBond(3, 1000)
+	assert.Error(t, es.UnregisterPRep(cc))
+}
+
+func TestExtensionStateImpl_SetBond_UnbondSlots(t *testing.T) {
+	for _, rev := range []int{icmodule.RevisionUnbondSlotMax - 1, icmodule.RevisionUnbondSlotMax} {
+		t.Run(fmt.Sprintf("Rev%d", rev), func(t *testing.T) {
+			user := newDummyAddress(100)
+			cc := newMockCallContext(map[CallCtxOption]interface{}{
+				CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+				CallCtxOptionBlockHeight: int64(1000),
+			})
+			es := newDummyExtensionState(t)
+			assert.NoError(t, es.State.SetTermPeriod(100))
+			assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+			assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+			assert.NoError(t, es.State.SetUnbondingMax(2))
+			assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+
+			bonds := make(icstate.Bonds, 0, 3)
+			for i := 1; i <= 3; i++ {
+				owner := newDummyAddress(i)
+				cc.SetFrom(owner)
+				assert.NoError(t, es.RegisterPRep(cc, newDummyPRepInfo(i)))
+				es.State.GetPRepBaseByOwner(owner, false).SetBonderList(
+					icstate.BonderList{common.AddressToPtr(user)})
+				bonds = append(bonds, icstate.NewBond(common.AddressToPtr(owner), big.NewInt(100)))
+			}
+			cc.SetFrom(user)
+			assert.NoError(t, es.SetStake(cc, big.NewInt(300)))
+			assert.NoError(t, es.SetBond(cc, bonds))
+
+			// unbond one in each block
+			for i := 0; i < 2; i++ {
+				cc.SetBlockHeight(cc.BlockHeight() + 1)
+				bonds = bonds[1:]
+				assert.NoError(t, es.SetBond(cc, bonds))
+			}
+			ia := es.State.GetAccountState(user)
+			assert.Len(t, ia.Unbonds(), 2)
+
+			cc.SetBlockHeight(cc.BlockHeight() + 1)
+			err := es.SetBond(cc, icstate.Bonds{})
+			if rev < icmodule.RevisionUnbondSlotMax {
+				assert.Equal(t, icmodule.IllegalArgumentError, errors.CodeOf(err))
+			} else {
+				assert.True(t, icmodule.TooManyUnbondSlotsError.Equals(err))
+			}
+		})
+	}
+}
+
+func TestExtensionStateImpl_NegativeVoting(t *testing.T) {
+	user := newDummyAddress(100)
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(icmodule.RevisionNegativeVotingCheck - 1),
+		CallCtxOptionBlockHeight: int64(10),
+		CallCtxOptionFrom:        user,
+	})
+	es := newDummyExtensionState(t)
+	assert.NoError(t, es.State.SetTermPeriod(100))
+	assert.NoError(t, es.State.SetLockVariables(big.NewInt(5), big.NewInt(20)))
+	assert.NoError(t, es.State.SetUnstakeSlotMax(10))
+	assert.NoError(t, es.SetStake(cc, big.NewInt(100)))
+
+	// artificially broken delegation reduces the voting
+	ia := es.State.GetAccountState(user)
+	ia.SetDelegation(icstate.Delegations{
+		icstate.NewDelegation(common.AddressToPtr(newDummyAddress(1)), big.NewInt(-50)),
+	})
+	assert.Equal(t, int64(-50), ia.UsingStake().Int64())
+
+	// it lets the account use more stake than it has before the revision
+	assert.NoError(t, es.SetStake(cc, big.NewInt(20)))
+
+	cc = newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(icmodule.RevisionNegativeVotingCheck),
+		CallCtxOptionBlockHeight: int64(10),
+		CallCtxOptionFrom:        user,
+	})
+	err := es.SetStake(cc, big.NewInt(10))
+	assert.True(t, scoreresult.UnknownFailureError.Equals(err), "err=%+v", err)
+	assert.Equal(t, int64(20), ia.Stake().Int64())
+
+	err = es.SetDelegation(cc, icstate.Delegations{})
+	assert.True(t, scoreresult.UnknownFailureError.Equals(err), "err=%+v", err)
+
+	err = es.SetBond(cc, icstate.Bonds{})
+	assert.True(t, scoreresult.UnknownFailureError.Equals(err), "err=%+v", err)
+}
+
+func TestExtensionSnapshotImpl_GetStake(t *testing.T) {
+	es := newDummyExtensionState(t)
+	staker := newDummyAddress(1)
+	assert.NoError(t, es.State.GetAccountState(staker).SetStake(big.NewInt(50)))
+
+	ess := es.GetSnapshot().(*ExtensionSnapshotImpl)
+	assert.Zero(t, big.NewInt(50).Cmp(ess.GetStake(staker)))
+	assert.Zero(t, ess.GetStake(newDummyAddress(2)).Sign())
+}
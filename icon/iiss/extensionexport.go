@@ -0,0 +1,136 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iiss
+
+import (
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/trie"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+	"github.com/icon-project/goloop/icon/iiss/icobject"
+	"github.com/icon-project/goloop/icon/iiss/icreward"
+	"github.com/icon-project/goloop/icon/iiss/icstage"
+	"github.com/icon-project/goloop/icon/iiss/icstate"
+)
+
+const (
+	extensionExportVersion1 = iota + 1
+	extensionExportVersion  = extensionExportVersion1
+)
+
+type extensionExportEntry struct {
+	Key   []byte
+	Value []byte
+}
+
+// extensionExport holds all entries of the stores in the order of
+// state, front, back1, back2 and reward.
+type extensionExport struct {
+	Version int
+	Stores  [5][]extensionExportEntry
+}
+
+func exportStore(itr trie.IteratorForObject) ([]extensionExportEntry, error) {
+	var entries []extensionExportEntry
+	for ; itr.Has(); itr.Next() {
+		o, k, err := itr.Get()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, extensionExportEntry{k, o.Bytes()})
+	}
+	return entries, nil
+}
+
+// Export returns all entries of the iiss stores (accounts, PReps, terms,
+// stages and rewards) in a portable form, which can be restored into
+// another database with ImportExtensionSnapshot.
+func (s *ExtensionSnapshotImpl) Export() ([]byte, error) {
+	exp := &extensionExport{Version: extensionExportVersion}
+	for i, itr := range []trie.IteratorForObject{
+		s.state.Filter(nil),
+		s.front.Filter(nil),
+		s.back1.Filter(nil),
+		s.back2.Filter(nil),
+		s.reward.Filter(nil),
+	} {
+		entries, err := exportStore(itr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "FailToExportStore(idx=%d)", i)
+		}
+		exp.Stores[i] = entries
+	}
+	return codec.BC.MarshalToBytes(exp)
+}
+
+func importStore(
+	database db.Database, factory icobject.ImplFactory, entries []extensionExportEntry,
+) ([]byte, error) {
+	database = icobject.AttachObjectFactory(database, factory)
+	t := trie_manager.NewMutableForObject(database, nil, icobject.ObjectType)
+	for _, e := range entries {
+		o := new(icobject.Object)
+		if err := o.Reset(database, e.Value); err != nil {
+			return nil, err
+		}
+		if _, err := t.Set(e.Key, o); err != nil {
+			return nil, err
+		}
+	}
+	ss := t.GetSnapshot()
+	if err := ss.Flush(); err != nil {
+		return nil, err
+	}
+	return ss.Hash(), nil
+}
+
+// ImportExtensionSnapshot restores the iiss stores written by
+// ExtensionSnapshotImpl.Export into the database.
+func ImportExtensionSnapshot(database db.Database, bs []byte) (*ExtensionSnapshotImpl, error) {
+	var exp extensionExport
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &exp); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidExport")
+	}
+	if exp.Version != extensionExportVersion {
+		return nil, errors.UnsupportedError.Errorf(
+			"UnsupportedVersion(version=%d)", exp.Version)
+	}
+	factories := [5]icobject.ImplFactory{
+		icstate.NewObjectImpl,
+		icstage.NewObjectImpl,
+		icstage.NewObjectImpl,
+		icstage.NewObjectImpl,
+		icreward.NewObjectImpl,
+	}
+	var hashes [5][]byte
+	for i, entries := range exp.Stores {
+		h, err := importStore(database, factories[i], entries)
+		if err != nil {
+			return nil, errors.Wrapf(err, "FailToImportStore(idx=%d)", i)
+		}
+		hashes[i] = h
+	}
+	return &ExtensionSnapshotImpl{
+		database: database,
+		state:    icstate.NewSnapshot(database, hashes[0]),
+		front:    icstage.NewSnapshot(database, hashes[1]),
+		back1:    icstage.NewSnapshot(database, hashes[2]),
+		back2:    icstage.NewSnapshot(database, hashes[3]),
+		reward:   icreward.NewSnapshot(database, hashes[4]),
+	}, nil
+}
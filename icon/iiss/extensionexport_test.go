@@ -0,0 +1,76 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iiss
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss/icreward"
+)
+
+func TestExtensionSnapshotImpl_Export(t *testing.T) {
+	rev := icmodule.RevisionIISS
+	cc := newMockCallContext(map[CallCtxOption]interface{}{
+		CallCtxOptionRevision:    icmodule.ValueToRevision(rev),
+		CallCtxOptionBlockHeight: int64(0),
+	})
+	es := newDummyExtensionState(t)
+	assert.NoError(t, es.GenesisTerm(cc.BlockHeight(), rev))
+	for i := 0; i < 2; i++ {
+		cc.SetFrom(newDummyAddress(i + 1))
+		err := es.RegisterGenesisPRep(cc, newDummyPRepInfo(i+1), big.NewInt(int64(100*(i+1))))
+		assert.NoError(t, err)
+	}
+	user := newDummyAddress(100)
+	_, err := es.Front.AddIScoreClaim(user, big.NewInt(10))
+	assert.NoError(t, err)
+	assert.NoError(t, es.Reward.SetIScore(user, icreward.NewIScore(big.NewInt(30))))
+
+	ess := es.GetSnapshot().(*ExtensionSnapshotImpl)
+	assert.NoError(t, ess.Flush())
+	bs, err := ess.Export()
+	assert.NoError(t, err)
+
+	ess2, err := ImportExtensionSnapshot(db.NewMapDB(), bs)
+	assert.NoError(t, err)
+	assert.True(t, bytes.Equal(ess.Bytes(), ess2.Bytes()))
+
+	es2 := ess2.NewState(true).(*ExtensionStateImpl)
+	assert.Zero(t, es.State.GetTotalStake().Cmp(es2.State.GetTotalStake()))
+	assert.True(t, es.State.GetTermSnapshot().Equal(es2.State.GetTermSnapshot()))
+	for i := 0; i < 2; i++ {
+		owner := newDummyAddress(i + 1)
+		assert.True(t, es.State.GetAccountSnapshot(owner).Equal(es2.State.GetAccountSnapshot(owner)))
+		assert.NotNil(t, es2.State.GetPRepBaseByOwner(owner, false))
+	}
+	claim, err := es2.Front.GetIScoreClaim(user)
+	assert.NoError(t, err)
+	assert.Zero(t, big.NewInt(10).Cmp(claim.Value()))
+	is, err := es2.Reward.GetIScore(user)
+	assert.NoError(t, err)
+	assert.Zero(t, big.NewInt(30).Cmp(is.Value()))
+
+	// broken export
+	_, err = ImportExtensionSnapshot(db.NewMapDB(), bs[:len(bs)/2])
+	assert.Error(t, err)
+}
@@ -84,16 +84,17 @@ func (d *Delegating) ApplyVotes(deltas icstage.VoteList) error {
 	// apply deltas
 	delegationMap := d.Delegations.ToMap()
 	for _, vote := range deltas {
-		dg, ok := delegationMap[icutils.ToKey(vote.To())]
+		var dg *icstate.Delegation
+		amount, ok := delegationMap[icutils.ToKey(vote.To())]
 		if ok {
-			value := new(big.Int).Add(dg.Amount(), vote.Amount())
+			value := new(big.Int).Add(amount, vote.Amount())
 			switch value.Sign() {
 			case -1:
-				return errors.Errorf("Negative delegation to %s, value %d = %d - %d", vote.To(), value, dg.Amount(), vote.Amount())
+				return errors.Errorf("Negative delegation to %s, value %d = %d - %d", vote.To(), value, amount, vote.Amount())
 			case 0:
 				continue
 			case 1:
-				dg = icstate.NewDelegation(common.AddressToPtr(dg.To()), value)
+				dg = icstate.NewDelegation(common.AddressToPtr(vote.To()), value)
 			}
 		} else {
 			switch vote.Amount().Sign() {
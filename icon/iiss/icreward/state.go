@@ -35,6 +35,7 @@ var (
 	IScoreKey          = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x40})
 	BugDisabledPRepKey = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x50})
 	PubKeyKey          = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x60})
+	CommissionKey      = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x70})
 	HashKey            = containerdb.ToKey(containerdb.PrefixedHashBuilder, []byte{0x80})
 	DSAKey             = containerdb.ToKey(containerdb.RawBuilder, HashKey.Append(btpDSAKey).Build()).Build()
 )
@@ -73,6 +74,27 @@ func (s *State) SetIScore(addr module.Address, iScore *IScore) error {
 	}
 }
 
+// GetCommission returns accumulated commission of the PRep, which is not claimed yet.
+func (s *State) GetCommission(addr module.Address) (*IScore, error) {
+	key := CommissionKey.Append(addr).Build()
+	obj, err := s.store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return ToIScore(obj), nil
+}
+
+func (s *State) SetCommission(addr module.Address, commission *IScore) error {
+	key := CommissionKey.Append(addr).Build()
+	if commission.IsEmpty() {
+		_, err := s.store.Delete(key)
+		return err
+	} else {
+		_, err := s.store.Set(key, icobject.New(TypeIScore, commission))
+		return err
+	}
+}
+
 func (s *State) GetVoted(addr module.Address) (*Voted, error) {
 	key := VotedKey.Append(addr).Build()
 	obj, err := s.store.Get(key)
@@ -37,16 +37,17 @@ const (
 )
 
 var (
-	IScoreClaimKey    = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x10})
-	EventKey          = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x20})
-	BlockProduceKey   = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x30})
-	ValidatorKey      = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x40})
-	BTPKey            = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x50})
-	CommissionRateKey = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x60})
-	HashKey           = containerdb.ToKey(containerdb.PrefixedHashBuilder, []byte{0x70})
-	GlobalKey         = containerdb.ToKey(containerdb.RawBuilder, HashKey.Append(globalKey).Build()).Build()
-	EventSizeKey      = containerdb.ToKey(containerdb.RawBuilder, HashKey.Append(eventsKey).Build())
-	ValidatorsKey     = containerdb.ToKey(containerdb.RawBuilder, HashKey.Append(validatorsKey).Build())
+	IScoreClaimKey     = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x10})
+	EventKey           = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x20})
+	BlockProduceKey    = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x30})
+	ValidatorKey       = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x40})
+	BTPKey             = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x50})
+	CommissionRateKey  = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x60})
+	CommissionClaimKey = containerdb.ToKey(containerdb.RLPBuilder, []byte{0x80})
+	HashKey            = containerdb.ToKey(containerdb.PrefixedHashBuilder, []byte{0x70})
+	GlobalKey          = containerdb.ToKey(containerdb.RawBuilder, HashKey.Append(globalKey).Build()).Build()
+	EventSizeKey       = containerdb.ToKey(containerdb.RawBuilder, HashKey.Append(eventsKey).Build())
+	ValidatorsKey      = containerdb.ToKey(containerdb.RawBuilder, HashKey.Append(validatorsKey).Build())
 )
 
 type State struct {
@@ -84,6 +85,27 @@ func (s *State) AddIScoreClaim(addr module.Address, amount *big.Int) (*IScoreCla
 	return claim, err
 }
 
+func (s *State) GetCommissionClaim(addr module.Address) (*IScoreClaim, error) {
+	key := CommissionClaimKey.Append(addr).Build()
+	obj, err := s.store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return ToIScoreClaim(obj), nil
+}
+
+func (s *State) AddCommissionClaim(addr module.Address, amount *big.Int) (*IScoreClaim, error) {
+	key := CommissionClaimKey.Append(addr).Build()
+	obj, err := s.store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	claim := ToIScoreClaim(obj)
+	claim = claim.Added(amount)
+	_, err = s.store.Set(key, icobject.New(TypeIScoreClaim, claim))
+	return claim, err
+}
+
 func (s *State) GetEvent(offset int, index int64) (*icobject.Object, error) {
 	key := EventKey.Append(offset, index).Build()
 	obj, err := s.store.Get(key)
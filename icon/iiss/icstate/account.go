@@ -18,6 +18,7 @@ package icstate
 
 import (
 	"fmt"
+	"io"
 	"math/big"
 	"sort"
 
@@ -27,13 +28,17 @@ import (
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/icon/icmodule"
 	"github.com/icon-project/goloop/icon/iiss/icobject"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/scoredb"
 )
 
 const (
-	accountVersion1 = iota + 1
-	accountVersion  = accountVersion1
+	// accountVersion0 is for accounts stored with a single unstake instead
+	// of the list of unstakes.
+	accountVersion0 = iota
+	accountVersion1
+	accountVersion = accountVersion1
 )
 
 var AccountDictPrefix = containerdb.ToKey(
@@ -53,6 +58,10 @@ type accountData struct {
 	totalDelegation *big.Int
 	totalBond       *big.Int
 	totalUnbond     *big.Int
+
+	// unstakeLockPeriod is the lock period applied to the last unstake.
+	// It's zero if it's not recorded.
+	unstakeLockPeriod int64
 }
 
 func (a *accountData) equal(other *accountData) bool {
@@ -67,7 +76,8 @@ func (a *accountData) equal(other *accountData) bool {
 		a.totalBond.Cmp(other.totalBond) == 0 &&
 		a.totalUnbond.Cmp(other.totalUnbond) == 0 &&
 		a.bonds.Equal(other.bonds) &&
-		a.unbonds.Equal(other.unbonds)
+		a.unbonds.Equal(other.unbonds) &&
+		a.unstakeLockPeriod == other.unstakeLockPeriod
 }
 
 func (a accountData) clone() accountData {
@@ -82,6 +92,8 @@ func (a accountData) clone() accountData {
 		totalDelegation: a.totalDelegation,
 		totalBond:       a.totalBond,
 		totalUnbond:     a.totalUnbond,
+
+		unstakeLockPeriod: a.unstakeLockPeriod,
 	}
 }
 
@@ -89,6 +101,13 @@ func (a accountData) IsEmpty() bool {
 	return (a.stake == nil || a.stake.Sign() == 0) && len(a.unstakes) == 0
 }
 
+// Exists returns true if the account has stake or unstakes. Empty accounts
+// are removed on flush, so an account staking zero without unstakes can't be
+// told apart from one never staked, and neither of them exists.
+func (a accountData) Exists() bool {
+	return !a.IsEmpty()
+}
+
 func (a accountData) Stake() *big.Int {
 	return a.stake
 }
@@ -97,10 +116,27 @@ func (a accountData) UnStakes() Unstakes {
 	return a.unstakes
 }
 
+// UnstakeLockPeriod returns the lock period applied to the last unstake, or
+// zero if it's not recorded.
+func (a accountData) UnstakeLockPeriod() int64 {
+	return a.unstakeLockPeriod
+}
+
 func (a accountData) GetUnstakeAmount() *big.Int {
 	return a.unstakes.GetUnstakeAmount()
 }
 
+// UnstakeAt returns the amount of unstakes expiring at the height.
+func (a accountData) UnstakeAt(height int64) *big.Int {
+	amount := new(big.Int)
+	for _, u := range a.unstakes {
+		if u.GetExpire() == height {
+			amount.Add(amount, u.GetValue())
+		}
+	}
+	return amount
+}
+
 func (a accountData) GetTotalStake() *big.Int {
 	return new(big.Int).Add(a.stake, a.unstakes.GetUnstakeAmount())
 }
@@ -113,21 +149,72 @@ func (a *accountData) Delegations() Delegations {
 	return a.delegations
 }
 
+// GetStakeInJSON returns the stake information of the account.
+// Amounts in the returned map are copies, so that they can be modified
+// without affecting the account.
 func (a accountData) GetStakeInJSON(blockHeight int64) map[string]interface{} {
 	jso := make(map[string]interface{})
-	jso["stake"] = a.stake
+	jso["stake"] = new(big.Int).Set(a.stake)
 	jso["unstakes"] = a.unstakes.ToJSON(module.JSONVersion3, blockHeight)
+	jso["totalStake"] = a.GetTotalStake()
+	if len(a.unstakes) > 0 && a.unstakeLockPeriod > 0 {
+		jso["unstakeLockPeriod"] = a.unstakeLockPeriod
+	}
 	return jso
 }
 
+// GetLegacyUnstakeInJSON returns the fields of getStake used before multiple
+// unstakes are introduced. They are filled with the unstake expiring first.
+// It returns nil if there is no unstake.
+func (a accountData) GetLegacyUnstakeInJSON(blockHeight int64) map[string]interface{} {
+	u := a.unstakes.Earliest()
+	if u == nil {
+		return nil
+	}
+	return u.ToJSON(module.JSONVersion3, blockHeight).(map[string]interface{})
+}
+
 func (a accountData) GetDelegationInJSON() map[string]interface{} {
+	return a.delegationInJSON(a.delegations)
+}
+
+// GetSortedDelegationInJSON returns GetDelegationInJSON with delegations
+// sorted by address, so it doesn't depend on the order of submission.
+func (a accountData) GetSortedDelegationInJSON() map[string]interface{} {
+	return a.delegationInJSON(a.delegations.Sorted())
+}
+
+func (a accountData) delegationInJSON(ds Delegations) map[string]interface{} {
 	jso := make(map[string]interface{})
-	jso["totalDelegated"] = a.totalDelegation
+	jso["totalDelegated"] = new(big.Int).Set(a.totalDelegation)
 	jso["votingPower"] = a.GetVotingPower()
-	jso["delegations"] = a.delegations.ToJSON(module.JSONVersion3)
+	jso["delegations"] = ds.ToJSON(module.JSONVersion3)
 	return jso
 }
 
+// addICXAmounts adds amounts of keys formatted in ICX with precision to jso
+// as "<key>InICX", alongside the raw amounts.
+func addICXAmounts(jso map[string]interface{}, precision int, keys ...string) map[string]interface{} {
+	for _, key := range keys {
+		if v, ok := jso[key].(*big.Int); ok {
+			jso[key+"InICX"] = icutils.FormatICX(v, precision)
+		}
+	}
+	return jso
+}
+
+// AddStakeInICX adds the amounts of GetStakeInJSON formatted in ICX with
+// precision digits after the decimal point to jso.
+func AddStakeInICX(jso map[string]interface{}, precision int) map[string]interface{} {
+	return addICXAmounts(jso, precision, "stake", "totalStake")
+}
+
+// AddDelegationInICX adds the amounts of GetDelegationInJSON formatted in ICX
+// with precision digits after the decimal point to jso.
+func AddDelegationInICX(jso map[string]interface{}, precision int) map[string]interface{} {
+	return addICXAmounts(jso, precision, "totalDelegated", "votingPower")
+}
+
 func (a *accountData) GetVotingPower() *big.Int {
 	return new(big.Int).Sub(a.stake, a.UsingStake())
 }
@@ -136,11 +223,64 @@ func (a *accountData) GetVoting() *big.Int {
 	return new(big.Int).Add(a.Bond(), a.Delegating())
 }
 
+// CheckVoting returns an error if any component of voting is negative.
+// Voting can't be negative, so it means that the account is broken, and
+// a negative one would reduce the sum of voting to let the account use
+// more stake.
+func (a *accountData) CheckVoting() error {
+	for _, c := range []struct {
+		name  string
+		value *big.Int
+	}{
+		{"bond", a.Bond()},
+		{"delegating", a.Delegating()},
+		{"unbond", a.Unbond()},
+	} {
+		if c.value.Sign() < 0 {
+			return errors.InvalidStateError.Errorf("NegativeVoting(name=%s,value=%v)", c.name, c.value)
+		}
+	}
+	return nil
+}
+
+// ExcessVoting returns the amount of voting exceeding the stake.
+// Unbonding amount is also counted as voting. It returns zero if the
+// stake covers all of them.
+func (a *accountData) ExcessVoting() *big.Int {
+	excess := new(big.Int).Sub(a.UsingStake(), a.stake)
+	if excess.Sign() < 0 {
+		excess.SetInt64(0)
+	}
+	return excess
+}
+
+// EffectiveVotingPower returns the amount of voting eligible for reward
+// under the bond requirement. Delegation is counted only as much as the bond
+// supports, like the power of a PRep at the end of a term.
+func (a *accountData) EffectiveVotingPower(bondRequirement icmodule.Rate) *big.Int {
+	if !bondRequirement.IsValid() {
+		return new(big.Int)
+	}
+	return icutils.CalcPower(bondRequirement, a.Bond(), a.GetVoting())
+}
+
 func (a *accountData) UsingStake() *big.Int {
 	using := a.GetVoting()
 	return using.Add(using, a.totalUnbond)
 }
 
+// VotingBreakdown returns the amounts of stake committed to bonds, delegations
+// and unbonds with their total and the remaining voting power.
+func (a *accountData) VotingBreakdown() map[string]interface{} {
+	jso := make(map[string]interface{})
+	jso["bond"] = new(big.Int).Set(a.Bond())
+	jso["delegation"] = new(big.Int).Set(a.Delegating())
+	jso["unbond"] = new(big.Int).Set(a.Unbond())
+	jso["total"] = a.UsingStake()
+	jso["votingPower"] = a.GetVotingPower()
+	return jso
+}
+
 func (a *accountData) Bond() *big.Int {
 	return a.totalBond
 }
@@ -157,15 +297,38 @@ func (a *accountData) Unbond() *big.Int {
 	return a.totalUnbond
 }
 
+// NextExpiry returns the earliest expire height among unstakes and unbonds.
+// It returns false if there is neither unstake nor unbond.
+func (a *accountData) NextExpiry() (int64, bool) {
+	var expire int64
+	found := false
+	if u := a.unstakes.Earliest(); u != nil {
+		expire, found = u.GetExpire(), true
+	}
+	for _, ub := range a.unbonds {
+		if !found || ub.Expire() < expire {
+			expire, found = ub.Expire(), true
+		}
+	}
+	return expire, found
+}
+
 func (a *accountData) GetBondInJSON() map[string]interface{} {
 	jso := make(map[string]interface{})
 	jso["bonds"] = a.bonds.ToJSON(module.JSONVersion3)
 	jso["unbonds"] = a.unbonds.ToJSON(module.JSONVersion3)
-	jso["totalBonded"] = a.totalBond
+	jso["totalBonded"] = new(big.Int).Set(a.totalBond)
 	jso["votingPower"] = a.GetVotingPower()
 	return jso
 }
 
+func (a *accountData) GetBondsInJSON() map[string]interface{} {
+	jso := make(map[string]interface{})
+	jso["bonds"] = a.bonds.ToJSON(module.JSONVersion3)
+	jso["totalBonded"] = new(big.Int).Set(a.totalBond)
+	return jso
+}
+
 func (a *accountData) GetUnbondsInJSON() []interface{} {
 	return a.unbonds.ToJSON(module.JSONVersion3)
 }
@@ -195,6 +358,9 @@ func (a *accountData) Format(f fmt.State, c rune) {
 type AccountSnapshot struct {
 	icobject.NoDatabase
 	accountData
+
+	// legacyUnstake is true if it's stored with accountVersion0
+	legacyUnstake bool
 }
 
 func (a *AccountSnapshot) Equal(object icobject.Impl) bool {
@@ -212,21 +378,72 @@ func (a *AccountSnapshot) Version() int {
 	return accountVersion
 }
 
+const (
+	accountFieldsWithoutBond = 6
+	accountFieldsWithoutLock = 8
+	accountFields            = 9
+)
+
+// legacyUnstakes decodes unstakes of accounts stored with a single unstake
+// instead of the list of unstakes, as well as ones with the list.
+type legacyUnstakes struct {
+	unstakes *Unstakes
+}
+
+func (l *legacyUnstakes) UnmarshalRLP(bs []byte) error {
+	if _, err := codec.BC.UnmarshalFromBytes(bs, l.unstakes); err == nil {
+		return nil
+	}
+	unstake := new(Unstake)
+	if _, err := codec.BC.UnmarshalFromBytes(bs, unstake); err != nil {
+		return err
+	}
+	if unstake.Value == nil || unstake.Value.Sign() == 0 {
+		*l.unstakes = nil
+	} else {
+		*l.unstakes = Unstakes{unstake}
+	}
+	return nil
+}
+
 func (a *AccountSnapshot) RLPDecodeFields(decoder codec.Decoder) error {
-	return decoder.DecodeAll(
+	var unstakes interface{} = &a.unstakes
+	if a.legacyUnstake {
+		unstakes = &legacyUnstakes{&a.unstakes}
+	}
+	n, err := decoder.DecodeMulti(
 		&a.stake,
-		&a.unstakes,
+		unstakes,
 		&a.totalDelegation,
 		&a.delegations,
 		&a.totalBond,
 		&a.totalUnbond,
 		&a.bonds,
 		&a.unbonds,
+		&a.unstakeLockPeriod,
 	)
+	if err == io.EOF {
+		switch n {
+		case accountFieldsWithoutBond:
+			// accounts stored before bond was introduced have no bonds
+			// and unbonds, and may have null for their totals.
+			if a.totalBond == nil {
+				a.totalBond = new(big.Int)
+			}
+			if a.totalUnbond == nil {
+				a.totalUnbond = new(big.Int)
+			}
+		case accountFieldsWithoutLock:
+		default:
+			return icmodule.InvalidStateError.Errorf("InvalidFormat(n=%d)", n)
+		}
+		err = nil
+	}
+	return err
 }
 
 func (a *AccountSnapshot) RLPEncodeFields(encoder codec.Encoder) error {
-	return encoder.EncodeMulti(
+	if err := encoder.EncodeMulti(
 		a.stake,
 		a.unstakes,
 		a.totalDelegation,
@@ -235,7 +452,14 @@ func (a *AccountSnapshot) RLPEncodeFields(encoder codec.Encoder) error {
 		a.totalUnbond,
 		a.bonds,
 		a.unbonds,
-	)
+	); err != nil {
+		return err
+	}
+	// keep the encoding of accounts without the lock period
+	if a.unstakeLockPeriod != 0 {
+		return encoder.Encode(a.unstakeLockPeriod)
+	}
+	return nil
 }
 
 var emptyAccountData = accountData{
@@ -249,14 +473,28 @@ var emptyAccountSnapshot = &AccountSnapshot{
 	accountData: emptyAccountData,
 }
 
-func newAccountWithTag(_ icobject.Tag) *AccountSnapshot {
-	// versioning with tag.Version() if necessary
-	return new(AccountSnapshot)
+func newAccountWithTag(tag icobject.Tag) *AccountSnapshot {
+	return &AccountSnapshot{legacyUnstake: tag.Version() == accountVersion0}
 }
 
+// Names of account fields passed to AccountObserver
+const (
+	AccountFieldStake           = "stake"
+	AccountFieldTotalDelegation = "totalDelegation"
+	AccountFieldTotalBond       = "totalBond"
+	AccountFieldTotalUnbond     = "totalUnbond"
+)
+
+// AccountObserver is called whenever a total of an account is changed
+// by the setters of AccountState.
+type AccountObserver func(owner module.Address, field string, oldValue, newValue *big.Int)
+
 type AccountState struct {
 	snapshot *AccountSnapshot
 	accountData
+
+	owner    module.Address
+	observer AccountObserver
 }
 
 func (a *AccountState) Reset(s *AccountSnapshot) {
@@ -287,12 +525,66 @@ func (a *AccountState) setDirty() {
 	}
 }
 
+func (a *AccountState) setObserver(owner module.Address, observer AccountObserver) {
+	a.owner = owner
+	a.observer = observer
+}
+
+// SelfDelegation returns the amount that the account delegates to itself.
+// It returns zero if the owner of the account is unknown.
+func (a *AccountState) SelfDelegation() *big.Int {
+	if a.owner == nil {
+		return new(big.Int)
+	}
+	return a.delegations.AmountOf(a.owner)
+}
+
+// SelfBond returns the amount that the account bonds to itself.
+// It returns zero if the owner of the account is unknown.
+func (a *AccountState) SelfBond() *big.Int {
+	if a.owner == nil {
+		return new(big.Int)
+	}
+	return a.bonds.AmountOf(a.owner)
+}
+
+// SnapshotStore provides state snapshots as of past block heights.
+type SnapshotStore interface {
+	SnapshotAt(height int64) (*Snapshot, error)
+}
+
+// DelegationSnapshotAt returns delegations of the account as they were at
+// the height, read from the snapshot of the height in the store.
+// It returns empty delegations if the account didn't exist at the height.
+func (a *AccountState) DelegationSnapshotAt(store SnapshotStore, height int64) (Delegations, error) {
+	if a.owner == nil {
+		return nil, errors.InvalidStateError.New("UnknownOwner")
+	}
+	ss, err := store.SnapshotAt(height)
+	if err != nil {
+		return nil, err
+	}
+	as := ss.GetAccountSnapshot(a.owner)
+	if as == nil {
+		return nil, nil
+	}
+	return as.Delegations().Clone(), nil
+}
+
+func (a *AccountState) notify(field string, oldValue, newValue *big.Int) {
+	if a.observer != nil && oldValue.Cmp(newValue) != 0 {
+		a.observer(a.owner, field, oldValue, newValue)
+	}
+}
+
 func (a *AccountState) SetStake(v *big.Int) error {
 	if v.Sign() == -1 {
 		return errors.Errorf("negative stake is not allowed")
 	}
+	old := a.stake
 	a.stake = v
 	a.setDirty()
+	a.notify(AccountFieldStake, old, v)
 	return nil
 }
 
@@ -300,6 +592,7 @@ func (a *AccountState) DecreaseUnstake(stakeInc *big.Int, expireHeight int64, re
 	if tj, err := a.unstakes.decreaseUnstake(stakeInc, expireHeight, revision); err != nil {
 		return nil, err
 	} else {
+		a.clearUnstakeLockPeriod()
 		a.setDirty()
 		return tj, nil
 	}
@@ -314,19 +607,87 @@ func (a *AccountState) IncreaseUnstake(stakeInc *big.Int, expireHeight int64, sl
 	}
 }
 
+// SetUnstakes replaces unstakes of the account for importing states.
+// Unstakes expiring at the same height are merged into one, and ones without
+// positive value are ignored. It returns timer jobs removing old expire
+// heights not used any more, followed by ones adding each distinct expire
+// height in ascending order.
+func (a *AccountState) SetUnstakes(unstakes Unstakes) []TimerJobInfo {
+	values := make(map[int64]*big.Int)
+	for _, u := range unstakes {
+		if u == nil || u.GetValue() == nil || u.GetValue().Sign() <= 0 {
+			continue
+		}
+		if v, ok := values[u.GetExpire()]; ok {
+			v.Add(v, u.GetValue())
+		} else {
+			values[u.GetExpire()] = new(big.Int).Set(u.GetValue())
+		}
+	}
+	heights := make([]int64, 0, len(values))
+	for h := range values {
+		heights = append(heights, h)
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	var tl []TimerJobInfo
+	removed := make(map[int64]bool)
+	for _, u := range a.unstakes {
+		h := u.GetExpire()
+		if _, ok := values[h]; !ok && !removed[h] {
+			removed[h] = true
+			tl = append(tl, TimerJobInfo{Type: JobTypeRemove, Height: h})
+		}
+	}
+	var us Unstakes
+	for _, h := range heights {
+		us = append(us, NewUnstake(values[h], h))
+		tl = append(tl, TimerJobInfo{Type: JobTypeAdd, Height: h})
+	}
+	a.unstakes = us
+	a.clearUnstakeLockPeriod()
+	a.setDirty()
+	return tl
+}
+
+// SetUnstakeLockPeriod records the lock period applied to the last unstake.
+func (a *AccountState) SetUnstakeLockPeriod(period int64) {
+	if a.unstakeLockPeriod != period {
+		a.unstakeLockPeriod = period
+		a.setDirty()
+	}
+}
+
+// clearUnstakeLockPeriod clears the lock period if there are no unstakes.
+func (a *AccountState) clearUnstakeLockPeriod() {
+	if len(a.unstakes) == 0 {
+		a.unstakeLockPeriod = 0
+	}
+}
+
 func (a *AccountState) SetDelegation(ds Delegations) {
+	old := a.totalDelegation
 	a.delegations = ds
 	a.totalDelegation = a.delegations.GetDelegationAmount()
 	a.setDirty()
+	a.notify(AccountFieldTotalDelegation, old, a.totalDelegation)
 }
 
 func (a *AccountState) SetBonds(bonds Bonds) {
+	old := a.totalBond
 	a.bonds = bonds
 	a.totalBond = a.bonds.GetBondAmount()
 	a.setDirty()
+	a.notify(AccountFieldTotalBond, old, a.totalBond)
 }
 
-func (a *AccountState) UpdateUnbonds(bondDelta map[string]*big.Int, expireHeight int64) ([]TimerJobInfo, error) {
+// UpdateUnbonds applies bondDelta to unbonds. Increased unbonds expire at
+// expireHeight. If slotMax is positive, it fails with TooManyUnbondSlotsError
+// when the number of distinct expire heights of unbonds is increased over
+// slotMax.
+func (a *AccountState) UpdateUnbonds(bondDelta map[string]*big.Int, expireHeight int64, slotMax int) ([]TimerJobInfo, error) {
 	var tl []TimerJobInfo
 
 	// sort key of bondDelta
@@ -340,6 +701,7 @@ func (a *AccountState) UpdateUnbonds(bondDelta map[string]*big.Int, expireHeight
 	ubs := a.unbonds.Clone()
 	unbondsMapByAddr := ubs.MapByAddr()
 	expireRefCount := ubs.ExpireRefCount() // Get ExpireRefCount
+	oldSlots := len(expireRefCount)
 
 	for _, key := range keys {
 		value := bondDelta[key]
@@ -403,12 +765,73 @@ func (a *AccountState) UpdateUnbonds(bondDelta map[string]*big.Int, expireHeight
 			}
 		}
 	}
+	if slotMax > 0 {
+		slots := 0
+		for _, count := range expireRefCount {
+			if count > 0 {
+				slots++
+			}
+		}
+		if slots > slotMax && slots > oldSlots {
+			return nil, icmodule.TooManyUnbondSlotsError.Errorf(
+				"TooManyUnbondSlots(slots=%d,max=%d)", slots, slotMax)
+		}
+	}
+	old := a.totalUnbond
 	a.unbonds = ubs
 	a.totalUnbond = a.unbonds.GetUnbondAmount()
 	a.setDirty()
+	a.notify(AccountFieldTotalUnbond, old, a.totalUnbond)
 	return tl, nil
 }
 
+// AccountDiscrepancy is a mismatch between a total stored in an account and
+// the sum of the corresponding list.
+type AccountDiscrepancy struct {
+	Field    string
+	Stored   *big.Int
+	Computed *big.Int
+}
+
+func (d AccountDiscrepancy) String() string {
+	return fmt.Sprintf("%s(stored=%s,computed=%s)", d.Field, d.Stored, d.Computed)
+}
+
+// Discrepancies returns totals of delegations, bonds and unbonds which don't
+// match the sums of their lists.
+func (a *accountData) Discrepancies() []AccountDiscrepancy {
+	var ds []AccountDiscrepancy
+	for _, f := range []AccountDiscrepancy{
+		{AccountFieldTotalDelegation, a.totalDelegation, a.delegations.GetDelegationAmount()},
+		{AccountFieldTotalBond, a.totalBond, a.bonds.GetBondAmount()},
+		{AccountFieldTotalUnbond, a.totalUnbond, a.unbonds.GetUnbondAmount()},
+	} {
+		if f.Stored.Cmp(f.Computed) != 0 {
+			ds = append(ds, f)
+		}
+	}
+	return ds
+}
+
+// Reconcile corrects totals of delegations, bonds and unbonds with the sums of
+// their lists. It returns the discrepancies corrected.
+func (a *AccountState) Reconcile() []AccountDiscrepancy {
+	ds := a.Discrepancies()
+	for _, d := range ds {
+		switch d.Field {
+		case AccountFieldTotalDelegation:
+			a.totalDelegation = d.Computed
+		case AccountFieldTotalBond:
+			a.totalBond = d.Computed
+		case AccountFieldTotalUnbond:
+			a.totalUnbond = d.Computed
+		}
+		a.setDirty()
+		a.notify(d.Field, d.Stored, d.Computed)
+	}
+	return ds
+}
+
 func (a *AccountState) RemoveUnbond(height int64) error {
 	var tmp Unbonds
 	removed := new(big.Int)
@@ -423,12 +846,42 @@ func (a *AccountState) RemoveUnbond(height int64) error {
 	if len(tmp) == len(a.unbonds) {
 		return errors.Errorf("Unbond timer not found at %d", height)
 	}
+	old := a.totalUnbond
 	a.unbonds = tmp
 	a.totalUnbond = new(big.Int).Sub(a.Unbond(), removed)
 	a.setDirty()
+	a.notify(AccountFieldTotalUnbond, old, a.totalUnbond)
 	return nil
 }
 
+// RemoveUnbondingHeights removes unbonds expiring at any of heights and
+// returns the total amount of them. Heights without unbonds are skipped,
+// so it can be used to catch up timer jobs missed for several heights.
+func (a *AccountState) RemoveUnbondingHeights(heights []int64) (*big.Int, error) {
+	hs := make(map[int64]struct{}, len(heights))
+	for _, h := range heights {
+		hs[h] = struct{}{}
+	}
+	var tmp Unbonds
+	removed := new(big.Int)
+	for _, u := range a.unbonds {
+		if _, ok := hs[u.Expire()]; ok {
+			removed.Add(removed, u.Value())
+		} else {
+			tmp = append(tmp, u)
+		}
+	}
+	if len(tmp) == len(a.unbonds) {
+		return removed, nil
+	}
+	old := a.totalUnbond
+	a.unbonds = tmp
+	a.totalUnbond = new(big.Int).Sub(a.Unbond(), removed)
+	a.setDirty()
+	a.notify(AccountFieldTotalUnbond, old, a.totalUnbond)
+	return removed, nil
+}
+
 func (a *AccountState) RemoveUnstake(height int64) (ra *big.Int, err error) {
 	var tmp Unstakes
 	ra = new(big.Int)
@@ -443,29 +896,58 @@ func (a *AccountState) RemoveUnstake(height int64) (ra *big.Int, err error) {
 		return nil, errors.Errorf("Unstaking timer not found at %d", height)
 	}
 	a.unstakes = tmp
+	a.clearUnstakeLockPeriod()
 	a.setDirty()
 	return
 }
 
-func (a *AccountState) SlashStake(amount *big.Int) error {
+// SlashStake reduces the stake by amount and returns the amount actually slashed.
+// If amount is larger than the stake, the stake becomes zero.
+func (a *AccountState) SlashStake(amount *big.Int) *big.Int {
 	stake := new(big.Int).Set(a.Stake())
-	stake.Sub(stake, amount)
-	return a.SetStake(stake)
+	if stake.Cmp(amount) < 0 {
+		amount = stake
+	}
+	old := a.stake
+	a.stake = new(big.Int).Sub(stake, amount)
+	a.setDirty()
+	a.notify(AccountFieldStake, old, a.stake)
+	return amount
 }
 
 func (a *AccountState) SlashBond(address module.Address, rate icmodule.Rate) *big.Int {
 	newBonds, amount := a.bonds.Slash(address, rate)
+	old := a.totalBond
 	a.bonds = newBonds
 	a.totalBond = new(big.Int).Sub(a.totalBond, amount)
 	a.setDirty()
+	a.notify(AccountFieldTotalBond, old, a.totalBond)
 	return amount
 }
 
 func (a *AccountState) SlashUnbond(address module.Address, rate icmodule.Rate) (*big.Int, int64) {
 	newUnbonds, amount, expire := a.unbonds.Slash(address, rate)
+	old := a.totalUnbond
+	a.unbonds = newUnbonds
+	a.totalUnbond = new(big.Int).Sub(a.totalUnbond, amount)
+	a.setDirty()
+	a.notify(AccountFieldTotalUnbond, old, a.totalUnbond)
+	return amount, expire
+}
+
+// SlashUnbondAtHeight slashes unbonds expiring at height with the rate.
+// It returns slashed amount and height if the unbonds are removed
+// (otherwise, -1).
+func (a *AccountState) SlashUnbondAtHeight(height int64, rate icmodule.Rate) (*big.Int, int64) {
+	newUnbonds, amount, expire := a.unbonds.SlashAtHeight(height, rate)
+	if amount.Sign() == 0 && expire < 0 {
+		return amount, expire
+	}
+	old := a.totalUnbond
 	a.unbonds = newUnbonds
 	a.totalUnbond = new(big.Int).Sub(a.totalUnbond, amount)
 	a.setDirty()
+	a.notify(AccountFieldTotalUnbond, old, a.totalUnbond)
 	return amount, expire
 }
 
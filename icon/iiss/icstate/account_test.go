@@ -17,16 +17,22 @@
 package icstate
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
 	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/icon/icmodule"
 	"github.com/icon-project/goloop/icon/iiss/icobject"
 	"github.com/icon-project/goloop/icon/iiss/icutils"
+	"github.com/icon-project/goloop/module"
 )
 
 func getTestAccount() *AccountState {
@@ -78,6 +84,232 @@ func TestAccount_Bytes(t *testing.T) {
 	assert.Equal(t, true, assTest.GetSnapshot().Equal(ass2))
 }
 
+func TestAccount_RLPDecodeFieldsWithoutBond(t *testing.T) {
+	ass := getTestAccount()
+
+	buf := bytes.NewBuffer(nil)
+	e := codec.BC.NewEncoder(buf)
+	assert.NoError(t, e.EncodeMulti(
+		ass.stake,
+		ass.unstakes,
+		ass.totalDelegation,
+		ass.delegations,
+		nil,
+		nil,
+	))
+	assert.NoError(t, e.Close())
+
+	snapshot := &AccountSnapshot{}
+	d := codec.BC.NewDecoder(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, snapshot.RLPDecodeFields(d))
+	assert.NoError(t, d.Close())
+
+	assert.Equal(t, 0, ass.stake.Cmp(snapshot.Stake()))
+	assert.True(t, ass.unstakes.Equal(snapshot.UnStakes()))
+	assert.Equal(t, 0, ass.totalDelegation.Cmp(snapshot.Delegating()))
+	assert.True(t, ass.delegations.Equal(snapshot.Delegations()))
+	assert.Zero(t, snapshot.Bond().Sign())
+	assert.Zero(t, snapshot.Unbond().Sign())
+	assert.Zero(t, len(snapshot.Bonds()))
+	assert.Zero(t, len(snapshot.Unbonds()))
+
+	// re-encoded with the current layout
+	o1 := icobject.New(TypeAccount, snapshot)
+	database := icobject.AttachObjectFactory(db.NewMapDB(), NewObjectImpl)
+	o2 := new(icobject.Object)
+	assert.NoError(t, o2.Reset(database, o1.Bytes()))
+	assert.True(t, snapshot.Equal(ToAccount(o2)))
+
+	// unknown layout
+	buf.Reset()
+	e = codec.BC.NewEncoder(buf)
+	assert.NoError(t, e.EncodeMulti(ass.stake, ass.unstakes, ass.totalDelegation))
+	assert.NoError(t, e.Close())
+	d = codec.BC.NewDecoder(bytes.NewReader(buf.Bytes()))
+	assert.Error(t, (&AccountSnapshot{}).RLPDecodeFields(d))
+}
+
+func TestAccount_RLPDecodeFieldsWithLegacyUnstake(t *testing.T) {
+	ass := getTestAccount()
+	encode := func(unstakes interface{}) []byte {
+		buf := bytes.NewBuffer(nil)
+		e := codec.BC.NewEncoder(buf)
+		assert.NoError(t, e.EncodeMulti(
+			ass.stake,
+			unstakes,
+			ass.totalDelegation,
+			ass.delegations,
+			ass.totalBond,
+			ass.totalUnbond,
+			ass.bonds,
+			ass.unbonds,
+		))
+		assert.NoError(t, e.Close())
+		return buf.Bytes()
+	}
+	decode := func(legacy bool, bs []byte) (*AccountSnapshot, error) {
+		snapshot := &AccountSnapshot{legacyUnstake: legacy}
+		d := codec.BC.NewDecoder(bytes.NewReader(bs))
+		if err := snapshot.RLPDecodeFields(d); err != nil {
+			return nil, err
+		}
+		return snapshot, d.Close()
+	}
+
+	// single unstake of the older encoding is promoted to the list
+	unstake := NewUnstake(big.NewInt(5), 10)
+	legacy := encode(unstake)
+	snapshot, err := decode(true, legacy)
+	assert.NoError(t, err)
+	assert.True(t, Unstakes{unstake}.Equal(snapshot.UnStakes()))
+	assert.Equal(t, 0, ass.stake.Cmp(snapshot.Stake()))
+	assert.True(t, ass.delegations.Equal(snapshot.Delegations()))
+	assert.True(t, ass.bonds.Equal(snapshot.Bonds()))
+	assert.True(t, ass.unbonds.Equal(snapshot.Unbonds()))
+
+	// equivalent to the one stored with the list
+	current, err := decode(false, encode(Unstakes{unstake}))
+	assert.NoError(t, err)
+	assert.True(t, current.Equal(snapshot))
+
+	// empty single unstake means no unstakes
+	snapshot, err = decode(true, encode(NewUnstake(new(big.Int), 0)))
+	assert.NoError(t, err)
+	assert.Zero(t, len(snapshot.UnStakes()))
+
+	// the list of unstakes and null are decoded as before
+	for _, legacyUnstake := range []bool{true, false} {
+		snapshot, err = decode(legacyUnstake, encode(ass.unstakes))
+		assert.NoError(t, err)
+		assert.True(t, ass.unstakes.Equal(snapshot.UnStakes()))
+		snapshot, err = decode(legacyUnstake, encode(nil))
+		assert.NoError(t, err)
+		assert.Zero(t, len(snapshot.UnStakes()))
+	}
+
+	// the older encoding is allowed only for version0
+	_, err = decode(false, legacy)
+	assert.Error(t, err)
+
+	// stored objects with the tag of each version
+	bs := codec.BC.MustMarshalToBytes([]interface{}{
+		icobject.MakeTag(TypeAccount, accountVersion0),
+		ass.stake,
+		unstake,
+		ass.totalDelegation,
+		ass.delegations,
+		ass.totalBond,
+		ass.totalUnbond,
+		ass.bonds,
+		ass.unbonds,
+	})
+	database := icobject.AttachObjectFactory(db.NewMapDB(), NewObjectImpl)
+	o := new(icobject.Object)
+	assert.NoError(t, o.Reset(database, bs))
+	assert.True(t, current.Equal(ToAccount(o)))
+	assert.False(t, newAccountWithTag(icobject.MakeTag(TypeAccount, accountVersion1)).legacyUnstake)
+}
+
+func TestAccount_SetUnstakes(t *testing.T) {
+	a := newAccountStateWithSnapshot(nil)
+
+	tl := a.SetUnstakes(Unstakes{
+		NewUnstake(big.NewInt(10), 30),
+		NewUnstake(big.NewInt(5), 10),
+		NewUnstake(big.NewInt(0), 40),
+		NewUnstake(big.NewInt(7), 30),
+		NewUnstake(big.NewInt(3), 20),
+	})
+	assert.Equal(t, []TimerJobInfo{
+		{JobTypeAdd, 10},
+		{JobTypeAdd, 20},
+		{JobTypeAdd, 30},
+	}, tl)
+	assert.True(t, Unstakes{
+		NewUnstake(big.NewInt(5), 10),
+		NewUnstake(big.NewInt(3), 20),
+		NewUnstake(big.NewInt(17), 30),
+	}.Equal(a.UnStakes()))
+	assert.Equal(t, int64(25), a.GetUnstakeAmount().Int64())
+
+	// old expire heights not used any more are removed
+	tl = a.SetUnstakes(Unstakes{
+		NewUnstake(big.NewInt(1), 20),
+		NewUnstake(big.NewInt(2), 50),
+	})
+	assert.Equal(t, []TimerJobInfo{
+		{JobTypeRemove, 10},
+		{JobTypeRemove, 30},
+		{JobTypeAdd, 20},
+		{JobTypeAdd, 50},
+	}, tl)
+	assert.True(t, Unstakes{
+		NewUnstake(big.NewInt(1), 20),
+		NewUnstake(big.NewInt(2), 50),
+	}.Equal(a.UnStakes()))
+
+	tl = a.SetUnstakes(nil)
+	assert.Equal(t, []TimerJobInfo{
+		{JobTypeRemove, 20},
+		{JobTypeRemove, 50},
+	}, tl)
+	assert.Zero(t, len(a.UnStakes()))
+}
+
+func TestAccount_UnstakeLockPeriod(t *testing.T) {
+	ass := getTestAccount()
+	database := icobject.AttachObjectFactory(db.NewMapDB(), NewObjectImpl)
+
+	// encoding isn't changed without the lock period
+	bs := icobject.New(TypeAccount, ass.GetSnapshot()).Bytes()
+	legacy := codec.BC.MustMarshalToBytes([]interface{}{
+		icobject.MakeTag(TypeAccount, accountVersion1),
+		ass.stake,
+		ass.unstakes,
+		ass.totalDelegation,
+		ass.delegations,
+		ass.totalBond,
+		ass.totalUnbond,
+		ass.bonds,
+		ass.unbonds,
+	})
+	assert.Equal(t, legacy, bs)
+	_, ok := ass.GetStakeInJSON(0)["unstakeLockPeriod"]
+	assert.False(t, ok)
+
+	ass.SetUnstakeLockPeriod(100)
+	assert.Equal(t, int64(100), ass.UnstakeLockPeriod())
+	assert.Equal(t, int64(100), ass.GetStakeInJSON(0)["unstakeLockPeriod"])
+
+	o := new(icobject.Object)
+	assert.NoError(t, o.Reset(database, icobject.New(TypeAccount, ass.GetSnapshot()).Bytes()))
+	snapshot := ToAccount(o)
+	assert.Equal(t, int64(100), snapshot.UnstakeLockPeriod())
+	assert.True(t, ass.GetSnapshot().Equal(snapshot))
+
+	// cleared when all unstakes are gone
+	ass.SetUnstakes(nil)
+	_, ok = ass.GetStakeInJSON(0)["unstakeLockPeriod"]
+	assert.False(t, ok)
+	assert.Zero(t, ass.UnstakeLockPeriod())
+
+	// on expiration of the last unstake
+	_, err := ass.IncreaseUnstake(big.NewInt(10), 1000, 10, icmodule.RevisionMultipleUnstakes)
+	assert.NoError(t, err)
+	ass.SetUnstakeLockPeriod(100)
+	_, err = ass.RemoveUnstake(1000)
+	assert.NoError(t, err)
+	assert.Zero(t, ass.UnstakeLockPeriod())
+
+	// on consuming all unstakes by staking again
+	_, err = ass.IncreaseUnstake(big.NewInt(10), 1000, 10, icmodule.RevisionMultipleUnstakes)
+	assert.NoError(t, err)
+	ass.SetUnstakeLockPeriod(100)
+	_, err = ass.DecreaseUnstake(big.NewInt(10), 1000, icmodule.RevisionMultipleUnstakes)
+	assert.NoError(t, err)
+	assert.Zero(t, ass.UnstakeLockPeriod())
+}
+
 func TestAccount_SetStake(t *testing.T) {
 	account := newAccountStateWithSnapshot(nil)
 
@@ -93,6 +325,82 @@ func TestAccount_SetStake(t *testing.T) {
 	assert.Equal(t, 0, s.Cmp(account.Stake()))
 }
 
+func TestAccount_GetStakeInJSON(t *testing.T) {
+	account := getTestAccount()
+
+	jso := account.GetStakeInJSON(5)
+	assert.Equal(t, 0, big.NewInt(100).Cmp(jso["stake"].(*big.Int)))
+	assert.Len(t, jso["unstakes"], 2)
+	assert.Equal(t, 0, big.NewInt(115).Cmp(jso["totalStake"].(*big.Int)))
+	assert.Equal(t, 0, account.GetTotalStake().Cmp(jso["totalStake"].(*big.Int)))
+
+	account = newAccountStateWithSnapshot(nil)
+	jso = account.GetStakeInJSON(5)
+	assert.Equal(t, 0, jso["totalStake"].(*big.Int).Sign())
+}
+
+func TestAccount_Exists(t *testing.T) {
+	account := getTestAccount()
+	assert.True(t, account.Exists())
+	assert.True(t, account.GetSnapshot().Exists())
+
+	// unstakes only
+	assert.NoError(t, account.SetStake(new(big.Int)))
+	assert.True(t, account.Exists())
+
+	account.Clear()
+	assert.False(t, account.Exists())
+	assert.Zero(t, account.Stake().Sign())
+
+	// zero stake without unstakes is the same as no account
+	account = newAccountStateWithSnapshot(nil)
+	assert.False(t, account.Exists())
+	assert.NoError(t, account.SetStake(new(big.Int)))
+	assert.False(t, account.Exists())
+
+	assert.False(t, GetEmptyAccountSnapshot().Exists())
+}
+
+func TestAccount_GetLegacyUnstakeInJSON(t *testing.T) {
+	account := getTestAccount() // unstakes : [{value:5, bh: 10}, {10, 20}]
+
+	// getStake of ICON1 mainnet in the single-unstake era
+	//	{
+	//	  "stake": "0x64",
+	//	  "unstake": "0x5",
+	//	  "unstakeBlockHeight": "0xa",
+	//	  "remainingBlocks": "0x5"
+	//	}
+	jso := account.GetLegacyUnstakeInJSON(5)
+	for k, v := range account.GetStakeInJSON(5) {
+		jso[k] = v
+	}
+	value, err := common.DecodeAnyForJSON(common.MustEncodeAny(jso))
+	assert.NoError(t, err)
+	bs, err := json.Marshal(value)
+	assert.NoError(t, err)
+	var legacy map[string]interface{}
+	assert.NoError(t, json.Unmarshal(bs, &legacy))
+	assert.Equal(t, "0x64", legacy["stake"])
+	assert.Equal(t, "0x5", legacy["unstake"])
+	assert.Equal(t, "0xa", legacy["unstakeBlockHeight"])
+	assert.Equal(t, "0x5", legacy["remainingBlocks"])
+	assert.Len(t, legacy["unstakes"], 2)
+
+	// the earliest-maturing slot is used regardless of the order
+	account.unstakes = Unstakes{
+		NewUnstake(big.NewInt(10), 20),
+		NewUnstake(big.NewInt(5), 10),
+	}
+	jso = account.GetLegacyUnstakeInJSON(5)
+	assert.Equal(t, 0, big.NewInt(5).Cmp(jso["unstake"].(*big.Int)))
+	assert.Equal(t, int64(10), jso["unstakeBlockHeight"])
+
+	// no unstake
+	account = newAccountStateWithSnapshot(nil)
+	assert.Nil(t, account.GetLegacyUnstakeInJSON(5))
+}
+
 func TestAccount_UpdateUnbonds(t *testing.T) {
 	a := getTestAccount() // unbonds : [{address: hx5, value:10, bh: 20}, {hx6, 10, 30}]
 
@@ -116,7 +424,7 @@ func TestAccount_UpdateUnbonds(t *testing.T) {
 	}
 	expectedTL := []TimerJobInfo{{JobTypeAdd, expireHeight}, {JobTypeRemove, 20}}
 
-	tl, err := a.UpdateUnbonds(delta, expireHeight)
+	tl, err := a.UpdateUnbonds(delta, expireHeight, 0)
 	assert.NoError(t, err)
 	assert.True(t, equalTimerJobSlice(expectedTL, tl))
 	assert.True(t, a.unbonds.Equal(expectedUnbonds))
@@ -140,7 +448,7 @@ func TestAccount_UpdateUnbonds(t *testing.T) {
 		{JobTypeAdd, expireHeight},
 	}
 
-	tl, err = a.UpdateUnbonds(delta, expireHeight)
+	tl, err = a.UpdateUnbonds(delta, expireHeight, 0)
 	assert.NoError(t, err)
 	assert.True(t, equalTimerJobSlice(expectedTL, tl))
 	assert.True(t, a.unbonds.Equal(expectedUnbonds))
@@ -161,7 +469,7 @@ func TestAccount_UpdateUnbonds(t *testing.T) {
 		{JobTypeRemove, 100},
 	}
 
-	tl, err = a.UpdateUnbonds(delta, expireHeight)
+	tl, err = a.UpdateUnbonds(delta, expireHeight, 0)
 	assert.NoError(t, err)
 	assert.True(t, equalTimerJobSlice(expectedTL, tl))
 	assert.True(t, a.unbonds.Equal(expectedUnbonds))
@@ -204,6 +512,32 @@ func TestAccount_RemoveUnbonding(t *testing.T) {
 	assert.Equal(t, 0, a.totalUnbond.Cmp(a.unbonds.GetUnbondAmount()))
 }
 
+func TestAccount_RemoveUnbondingHeights(t *testing.T) {
+	a := getTestAccount() // unbonds : [{address: hx5, value:10, bh: 20}, {hx6, 10, 30}]
+	ub2 := NewUnbond(common.MustNewAddressFromString("hx6"), big.NewInt(10), 30)
+
+	// no unbonds at the heights
+	removed, err := a.RemoveUnbondingHeights([]int64{1, 2})
+	assert.NoError(t, err)
+	assert.Zero(t, removed.Sign())
+	assert.Equal(t, 2, len(a.unbonds))
+	assert.Equal(t, 0, big.NewInt(20).Cmp(a.Unbond()))
+
+	// skip heights without unbonds
+	removed, err = a.RemoveUnbondingHeights([]int64{10, 20, 25})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, big.NewInt(10).Cmp(removed))
+	assert.Equal(t, 1, len(a.unbonds))
+	assert.Contains(t, a.unbonds, ub2)
+	assert.Equal(t, 0, a.totalUnbond.Cmp(a.unbonds.GetUnbondAmount()))
+
+	removed, err = a.RemoveUnbondingHeights([]int64{20, 30})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, big.NewInt(10).Cmp(removed))
+	assert.Zero(t, len(a.unbonds))
+	assert.Zero(t, a.totalUnbond.Sign())
+}
+
 func TestAccount_RemoveUnstaking(t *testing.T) {
 	a := getTestAccount() // unstakes : [{value:5, bh: 10}, {10, 20}]
 	us1 := NewUnstake(big.NewInt(5), 10)
@@ -238,20 +572,197 @@ func TestAccount_RemoveUnstaking(t *testing.T) {
 	assert.Equal(t, 0, big.NewInt(5).Cmp(ra))
 }
 
+func TestAccount_UnstakeAt(t *testing.T) {
+	a := getTestAccount() // unstakes : [{value:5, bh: 10}, {10, 20}]
+
+	assert.Equal(t, 0, big.NewInt(5).Cmp(a.UnstakeAt(10)))
+	assert.Equal(t, 0, big.NewInt(10).Cmp(a.UnstakeAt(20)))
+	assert.Zero(t, a.UnstakeAt(15).Sign())
+
+	// read only
+	assert.Equal(t, 0, big.NewInt(15).Cmp(a.GetUnstakeAmount()))
+	assert.Equal(t, 0, big.NewInt(10).Cmp(a.GetSnapshot().UnstakeAt(20)))
+}
+
+func TestAccount_ExcessVoting(t *testing.T) {
+	a := getTestAccount() // stake: 100, delegation: 20, bond: 20, unbond: 20
+
+	assert.Zero(t, a.ExcessVoting().Sign())
+
+	assert.NoError(t, a.SetStake(big.NewInt(60)))
+	assert.Zero(t, a.ExcessVoting().Sign())
+
+	assert.NoError(t, a.SetStake(big.NewInt(50)))
+	assert.Equal(t, 0, big.NewInt(10).Cmp(a.ExcessVoting()))
+
+	assert.NoError(t, a.SetStake(big.NewInt(0)))
+	assert.Equal(t, 0, big.NewInt(60).Cmp(a.ExcessVoting()))
+}
+
+func TestAccount_CheckVoting(t *testing.T) {
+	a := getTestAccount() // stake: 100, delegation: 20, bond: 20, unbond: 20
+	assert.NoError(t, a.CheckVoting())
+
+	a.totalDelegation = big.NewInt(-30)
+	assert.True(t, errors.InvalidStateError.Equals(a.CheckVoting()))
+	a.totalDelegation = big.NewInt(20)
+
+	a.totalBond = big.NewInt(-1)
+	assert.True(t, errors.InvalidStateError.Equals(a.CheckVoting()))
+	a.totalBond = big.NewInt(20)
+
+	a.totalUnbond = big.NewInt(-50)
+	assert.True(t, errors.InvalidStateError.Equals(a.CheckVoting()))
+}
+
+func TestAccount_EffectiveVotingPower(t *testing.T) {
+	a := getTestAccount() // stake: 100, delegation: 20, bond: 20, unbond: 20
+
+	tests := []struct {
+		name string
+		br   icmodule.Rate
+		want int64
+	}{
+		{"NoRequirement", icmodule.ToRate(0), 40},
+		{"MeetRequirement", icmodule.ToRate(30), 40},
+		{"MeetRequirementExactly", icmodule.ToRate(50), 40},
+		{"NotMeetRequirement", icmodule.ToRate(80), 25},
+		{"FullRequirement", icmodule.ToRate(100), 20},
+		{"InvalidRequirement", icmodule.Rate(-1), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, a.EffectiveVotingPower(tt.br).Int64())
+		})
+	}
+
+	// same result with snapshot
+	assert.Equal(t, int64(25), a.GetSnapshot().EffectiveVotingPower(icmodule.ToRate(80)).Int64())
+}
+
+func TestAccount_VotingBreakdown(t *testing.T) {
+	a := getTestAccount() // stake: 100, delegation: 20, bond: 20, unbond: 20
+
+	vb := a.VotingBreakdown()
+	assert.Equal(t, int64(20), vb["bond"].(*big.Int).Int64())
+	assert.Equal(t, int64(20), vb["delegation"].(*big.Int).Int64())
+	assert.Equal(t, int64(20), vb["unbond"].(*big.Int).Int64())
+	assert.Equal(t, int64(40), vb["votingPower"].(*big.Int).Int64())
+
+	total := new(big.Int)
+	for _, key := range []string{"bond", "delegation", "unbond"} {
+		total.Add(total, vb[key].(*big.Int))
+	}
+	assert.Equal(t, 0, total.Cmp(vb["total"].(*big.Int)))
+	assert.Equal(t, 0, a.Stake().Cmp(new(big.Int).Add(total, vb["votingPower"].(*big.Int))))
+}
+
+func TestAccount_GetSortedDelegationInJSON(t *testing.T) {
+	d1 := NewDelegation(common.MustNewAddressFromString("hx1"), big.NewInt(10))
+	d2 := NewDelegation(common.MustNewAddressFromString("hx2"), big.NewInt(20))
+	d3 := NewDelegation(common.MustNewAddressFromString("hx3"), big.NewInt(30))
+
+	a1 := newAccountStateWithSnapshot(nil)
+	assert.NoError(t, a1.SetStake(big.NewInt(100)))
+	a1.SetDelegation(Delegations{d3, d1, d2})
+	a2 := newAccountStateWithSnapshot(nil)
+	assert.NoError(t, a2.SetStake(big.NewInt(100)))
+	a2.SetDelegation(Delegations{d2, d3, d1})
+
+	jso1 := a1.GetSortedDelegationInJSON()
+	assert.Equal(t, jso1, a2.GetSortedDelegationInJSON())
+	assert.Equal(t, Delegations{d1, d2, d3}.ToJSON(module.JSONVersion3), jso1["delegations"])
+
+	// order of submission is kept
+	assert.NotEqual(t, a1.GetDelegationInJSON(), a2.GetDelegationInJSON())
+	assert.True(t, Delegations{d3, d1, d2}.Equal(a1.Delegations()))
+}
+
+func TestAccount_JSONCopies(t *testing.T) {
+	a := getTestAccount() // stake: 100, delegation: 20, bond: 20, unbond: 20
+
+	a.GetStakeInJSON(0)["stake"].(*big.Int).SetInt64(1)
+	a.GetDelegationInJSON()["totalDelegated"].(*big.Int).SetInt64(1)
+	a.GetBondInJSON()["totalBonded"].(*big.Int).SetInt64(1)
+	a.GetBondsInJSON()["totalBonded"].(*big.Int).SetInt64(1)
+	vb := a.VotingBreakdown()
+	for _, key := range []string{"bond", "delegation", "unbond"} {
+		vb[key].(*big.Int).SetInt64(1)
+	}
+
+	assert.Equal(t, int64(100), a.Stake().Int64())
+	assert.Equal(t, int64(20), a.Delegating().Int64())
+	assert.Equal(t, int64(20), a.Bond().Int64())
+	assert.Equal(t, int64(20), a.Unbond().Int64())
+}
+
+func TestAccount_NextExpiry(t *testing.T) {
+	unstakes := Unstakes{NewUnstake(big.NewInt(5), 30), NewUnstake(big.NewInt(10), 20)}
+	unbonds := Unbonds{
+		NewUnbond(common.MustNewAddressFromString("hx3"), big.NewInt(10), 25),
+		NewUnbond(common.MustNewAddressFromString("hx4"), big.NewInt(10), 15),
+	}
+	cases := []struct {
+		name     string
+		unstakes Unstakes
+		unbonds  Unbonds
+		expire   int64
+		found    bool
+	}{
+		{"OnlyUnstakes", unstakes, nil, 20, true},
+		{"OnlyUnbonds", nil, unbonds, 15, true},
+		{"Both", unstakes, unbonds[:1], 20, true},
+		{"BothUnbondFirst", unstakes, unbonds, 15, true},
+		{"Neither", nil, nil, 0, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a := &AccountState{
+				accountData: accountData{unstakes: c.unstakes, unbonds: c.unbonds},
+			}
+			expire, found := a.NextExpiry()
+			assert.Equal(t, c.found, found)
+			assert.Equal(t, c.expire, expire)
+		})
+	}
+}
+
+func TestAccount_AddInICX(t *testing.T) {
+	a := &AccountState{
+		accountData: accountData{
+			stake:           icutils.ToLoop(100),
+			unstakes:        Unstakes{NewUnstake(big.NewInt(500_000_000_000_000_000), 10)},
+			totalDelegation: new(big.Int).Div(icutils.ToLoop(200), big.NewInt(3)),
+			totalBond:       new(big.Int),
+			totalUnbond:     new(big.Int),
+		},
+	}
+
+	jso := AddStakeInICX(a.GetStakeInJSON(0), 2)
+	assert.Equal(t, 0, icutils.ToLoop(100).Cmp(jso["stake"].(*big.Int)))
+	assert.Equal(t, "100.00", jso["stakeInICX"])
+	assert.Equal(t, "100.50", jso["totalStakeInICX"])
+
+	jso = AddDelegationInICX(a.GetDelegationInJSON(), 4)
+	assert.Equal(t, "66.6666", jso["totalDelegatedInICX"])
+	assert.Equal(t, "33.3333", jso["votingPowerInICX"])
+}
+
 func TestAccount_SlashStake(t *testing.T) {
 	a := getTestAccount() // a.stake = 100
 
-	err := a.SlashStake(big.NewInt(10))
-	assert.NoError(t, err)
+	slashed := a.SlashStake(big.NewInt(10))
+	assert.Equal(t, 0, slashed.Cmp(big.NewInt(10)))
 	assert.Equal(t, 0, a.Stake().Cmp(big.NewInt(90)))
 
-	err = a.SlashStake(big.NewInt(100))
-	assert.Error(t, err)
-	assert.Equal(t, 0, a.Stake().Cmp(big.NewInt(90)))
+	// slashing more than the stake zeroes it
+	slashed = a.SlashStake(big.NewInt(100))
+	assert.Equal(t, 0, slashed.Cmp(big.NewInt(90)))
+	assert.Equal(t, 0, a.Stake().Sign())
 
-	err = a.SlashStake(big.NewInt(90))
-	assert.NoError(t, err)
-	assert.Equal(t, 0, a.Stake().Cmp(big.NewInt(0)))
+	slashed = a.SlashStake(big.NewInt(10))
+	assert.Equal(t, 0, slashed.Sign())
+	assert.Equal(t, 0, a.Stake().Sign())
 }
 
 func TestAccount_SlashBond(t *testing.T) {
@@ -287,6 +798,41 @@ func TestAccount_SlashUnbond(t *testing.T) {
 	assert.Equal(t, 1, ul)
 }
 
+func TestAccount_SlashUnbondAtHeight(t *testing.T) {
+	a := getTestAccount()
+	a.unbonds = Unbonds{
+		NewUnbond(common.MustNewAddressFromString("hx5"), big.NewInt(10), 20),
+		NewUnbond(common.MustNewAddressFromString("hx6"), big.NewInt(20), 20),
+		NewUnbond(common.MustNewAddressFromString("hx7"), big.NewInt(30), 30),
+	}
+	a.totalUnbond = big.NewInt(60)
+
+	// no unbonds expiring at the height
+	amount, eh := a.SlashUnbondAtHeight(25, icmodule.ToRate(10))
+	assert.Zero(t, amount.Sign())
+	assert.Equal(t, int64(-1), eh)
+	assert.Equal(t, 3, len(a.Unbonds()))
+
+	// unbonds of multiple addresses expiring at the height
+	amount, eh = a.SlashUnbondAtHeight(20, icmodule.ToRate(10))
+	assert.Equal(t, 0, amount.Cmp(big.NewInt(3)))
+	assert.Equal(t, int64(-1), eh)
+	assert.Equal(t, 3, len(a.Unbonds()))
+	assert.Equal(t, 0, a.Unbonds()[0].Value().Cmp(big.NewInt(9)))
+	assert.Equal(t, 0, a.Unbonds()[1].Value().Cmp(big.NewInt(18)))
+	assert.Equal(t, 0, a.Unbonds()[2].Value().Cmp(big.NewInt(30)))
+	assert.Equal(t, 0, a.Unbond().Cmp(big.NewInt(57)))
+	assert.Equal(t, 0, a.Unbond().Cmp(a.Unbonds().GetUnbondAmount()))
+
+	// remove all unbonds expiring at the height
+	amount, eh = a.SlashUnbondAtHeight(20, icmodule.ToRate(100))
+	assert.Equal(t, 0, amount.Cmp(big.NewInt(27)))
+	assert.Equal(t, int64(20), eh)
+	assert.Equal(t, 1, len(a.Unbonds()))
+	assert.Equal(t, int64(30), a.Unbonds()[0].Expire())
+	assert.Equal(t, 0, a.Unbond().Cmp(big.NewInt(30)))
+}
+
 func equalTimerJobSlice(expected []TimerJobInfo, actual []TimerJobInfo) bool {
 	if len(expected) != len(actual) {
 		return false
@@ -388,3 +934,192 @@ func TestAccountState_OverlappedExpireHeight(t *testing.T) {
 	assert.Equal(t, v1, unstakes[0].Value)
 	assert.Equal(t, eh1, unstakes[0].Expire)
 }
+
+type accountChange struct {
+	owner    module.Address
+	field    string
+	oldValue int64
+	newValue int64
+}
+
+func TestAccountState_Observer(t *testing.T) {
+	var changes []accountChange
+	observer := func(owner module.Address, field string, oldValue, newValue *big.Int) {
+		changes = append(changes, accountChange{owner, field, oldValue.Int64(), newValue.Int64()})
+	}
+	owner := common.MustNewAddressFromString("hx1")
+	a := getTestAccount()
+
+	// no observer
+	assert.NoError(t, a.SetStake(big.NewInt(200)))
+	assert.Len(t, changes, 0)
+
+	a.setObserver(owner, observer)
+	assert.NoError(t, a.SetStake(big.NewInt(300)))
+	assert.NoError(t, a.SetStake(big.NewInt(300))) // no change
+	a.SetDelegation(Delegations{NewDelegation(common.MustNewAddressFromString("hx2"), big.NewInt(30))})
+	a.SetBonds(Bonds{NewBond(common.MustNewAddressFromString("hx3"), big.NewInt(40))})
+	_, err := a.UpdateUnbonds(map[string]*big.Int{
+		icutils.ToKey(common.MustNewAddressFromString("hx7")): big.NewInt(-5),
+	}, 50, 0)
+	assert.NoError(t, err)
+	assert.NoError(t, a.RemoveUnbond(20))
+	a.SlashBond(common.MustNewAddressFromString("hx3"), icmodule.ToRate(50))
+	a.SlashUnbond(common.MustNewAddressFromString("hx6"), icmodule.ToRate(100))
+
+	expected := []accountChange{
+		{owner, AccountFieldStake, 200, 300},
+		{owner, AccountFieldTotalDelegation, 20, 30},
+		{owner, AccountFieldTotalBond, 20, 40},
+		{owner, AccountFieldTotalUnbond, 20, 25},
+		{owner, AccountFieldTotalUnbond, 25, 15},
+		{owner, AccountFieldTotalBond, 40, 20},
+		{owner, AccountFieldTotalUnbond, 15, 5},
+	}
+	assert.Equal(t, expected, changes)
+}
+
+func TestAccount_Reconcile(t *testing.T) {
+	var changes []accountChange
+	observer := func(owner module.Address, field string, oldValue, newValue *big.Int) {
+		changes = append(changes, accountChange{owner, field, oldValue.Int64(), newValue.Int64()})
+	}
+	owner := common.MustNewAddressFromString("hx1")
+	a := getTestAccount()
+	a.setObserver(owner, observer)
+
+	// consistent account
+	assert.Len(t, a.Discrepancies(), 0)
+	assert.Len(t, a.Reconcile(), 0)
+
+	// drifted totals
+	a.totalBond = big.NewInt(25)
+	a.totalUnbond = big.NewInt(10)
+	ds := a.Discrepancies()
+	assert.Equal(t, []AccountDiscrepancy{
+		{AccountFieldTotalBond, big.NewInt(25), big.NewInt(20)},
+		{AccountFieldTotalUnbond, big.NewInt(10), big.NewInt(20)},
+	}, ds)
+	assert.Len(t, changes, 0)
+
+	assert.Equal(t, ds, a.Reconcile())
+	assert.Equal(t, int64(20), a.Bond().Int64())
+	assert.Equal(t, int64(20), a.Unbond().Int64())
+	assert.Len(t, a.Discrepancies(), 0)
+	assert.Equal(t, []accountChange{
+		{owner, AccountFieldTotalBond, 25, 20},
+		{owner, AccountFieldTotalUnbond, 10, 20},
+	}, changes)
+}
+
+func TestState_SetAccountObserver(t *testing.T) {
+	var changes []accountChange
+	observer := func(owner module.Address, field string, oldValue, newValue *big.Int) {
+		changes = append(changes, accountChange{owner, field, oldValue.Int64(), newValue.Int64()})
+	}
+	addr1 := common.MustNewAddressFromString("hx1")
+	addr2 := common.MustNewAddressFromString("hx2")
+	s := newDummyState(false)
+
+	// cached before the observer is set
+	a1 := s.GetAccountState(addr1)
+	s.SetAccountObserver(observer)
+	assert.NoError(t, a1.SetStake(big.NewInt(10)))
+	assert.NoError(t, s.GetAccountState(addr2).SetStake(big.NewInt(20)))
+	assert.Equal(t, []accountChange{
+		{addr1, AccountFieldStake, 0, 10},
+		{addr2, AccountFieldStake, 0, 20},
+	}, changes)
+
+	s.SetAccountObserver(nil)
+	assert.NoError(t, a1.SetStake(big.NewInt(30)))
+	assert.Len(t, changes, 2)
+}
+
+func TestAccount_UpdateUnbondsWithSlotMax(t *testing.T) {
+	a := getTestAccount() // unbonds : [{address: hx5, value:10, bh: 20}, {hx6, 10, 30}]
+	slotMax := 4
+
+	// fill unbond slots
+	for i, height := range []int64{40, 50} {
+		key := icutils.ToKey(common.MustNewAddressFromString(fmt.Sprintf("hx%d", 10+i)))
+		_, err := a.UpdateUnbonds(map[string]*big.Int{key: big.NewInt(-10)}, height, slotMax)
+		assert.NoError(t, err)
+	}
+	assert.Len(t, a.Unbonds().ExpireRefCount(), slotMax)
+
+	// merging at the existing height doesn't need a new slot
+	key := icutils.ToKey(common.MustNewAddressFromString("hx20"))
+	_, err := a.UpdateUnbonds(map[string]*big.Int{key: big.NewInt(-10)}, 50, slotMax)
+	assert.NoError(t, err)
+	assert.Len(t, a.Unbonds(), 5)
+
+	// one more slot
+	unbonds := a.Unbonds()
+	key = icutils.ToKey(common.MustNewAddressFromString("hx21"))
+	_, err = a.UpdateUnbonds(map[string]*big.Int{key: big.NewInt(-10)}, 60, slotMax)
+	assert.True(t, icmodule.TooManyUnbondSlotsError.Equals(err))
+	assert.True(t, unbonds.Equal(a.Unbonds()))
+
+	// moving an unbond to the new height doesn't increase slots
+	key = icutils.ToKey(common.MustNewAddressFromString("hx5"))
+	_, err = a.UpdateUnbonds(map[string]*big.Int{key: big.NewInt(-10)}, 60, slotMax)
+	assert.NoError(t, err)
+	assert.Len(t, a.Unbonds().ExpireRefCount(), slotMax)
+
+	// no limit
+	key = icutils.ToKey(common.MustNewAddressFromString("hx21"))
+	_, err = a.UpdateUnbonds(map[string]*big.Int{key: big.NewInt(-10)}, 70, 0)
+	assert.NoError(t, err)
+	assert.Len(t, a.Unbonds().ExpireRefCount(), slotMax+1)
+}
+
+type testSnapshotStore map[int64]*Snapshot
+
+func (s testSnapshotStore) SnapshotAt(height int64) (*Snapshot, error) {
+	if ss, ok := s[height]; ok {
+		return ss, nil
+	}
+	return nil, errors.NotFoundError.Errorf("NoSnapshot(height=%d)", height)
+}
+
+func TestAccount_DelegationSnapshotAt(t *testing.T) {
+	owner := common.MustNewAddressFromString("hx1")
+	p1 := common.MustNewAddressFromString("hx11")
+	p2 := common.MustNewAddressFromString("hx12")
+	s := newDummyState(false)
+	store := make(testSnapshotStore)
+
+	// no account at height 10
+	store[10] = s.GetSnapshot()
+
+	ds1 := Delegations{NewDelegation(p1, big.NewInt(10))}
+	a := s.GetAccountState(owner)
+	assert.NoError(t, a.SetStake(big.NewInt(100)))
+	a.SetDelegation(ds1)
+	store[20] = s.GetSnapshot()
+
+	ds2 := Delegations{NewDelegation(p1, big.NewInt(5)), NewDelegation(p2, big.NewInt(20))}
+	a = s.GetAccountState(owner)
+	a.SetDelegation(ds2)
+	store[30] = s.GetSnapshot()
+
+	ds, err := a.DelegationSnapshotAt(store, 10)
+	assert.NoError(t, err)
+	assert.Len(t, ds, 0)
+
+	ds, err = a.DelegationSnapshotAt(store, 20)
+	assert.NoError(t, err)
+	assert.True(t, ds1.Equal(ds))
+
+	ds, err = a.DelegationSnapshotAt(store, 30)
+	assert.NoError(t, err)
+	assert.True(t, ds2.Equal(ds))
+
+	_, err = a.DelegationSnapshotAt(store, 40)
+	assert.True(t, errors.NotFoundError.Equals(err))
+
+	// owner is unknown
+	_, err = getTestAccount().DelegationSnapshotAt(store, 20)
+	assert.Error(t, err)
+}
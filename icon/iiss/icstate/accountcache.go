@@ -26,9 +26,14 @@ import (
 	"github.com/icon-project/goloop/module"
 )
 
+// AccountCache keeps AccountStates loaded in a State, so that accesses to
+// an account share the same AccountState until the State is dropped.
+// Changes are written to the store on Flush. Like the other caches in
+// State, it's not safe for concurrent use.
 type AccountCache struct {
 	dict     *containerdb.DictDB
 	accounts map[string]*AccountState
+	observer AccountObserver
 }
 
 func (c *AccountCache) Get(owner module.Address, createIfNotExist bool) *AccountState {
@@ -48,10 +53,24 @@ func (c *AccountCache) Get(owner module.Address, createIfNotExist bool) *Account
 	} else {
 		account = newAccountStateWithSnapshot(ToAccount(o.Object()))
 	}
+	account.setObserver(owner, c.observer)
 	c.accounts[key] = account
 	return account
 }
 
+// SetObserver sets the observer for accounts in the cache.
+// nil observer disables notifications.
+func (c *AccountCache) SetObserver(observer AccountObserver) {
+	c.observer = observer
+	for key, account := range c.accounts {
+		owner, err := common.NewAddress([]byte(key))
+		if err != nil {
+			panic(errors.Errorf("AccountCache is broken: %x", key))
+		}
+		account.setObserver(owner, observer)
+	}
+}
+
 func (c *AccountCache) Clear() {
 	c.Flush()
 	c.accounts = make(map[string]*AccountState)
@@ -75,3 +75,63 @@ func TestAccountCache(t *testing.T) {
 	assert.Equal(t, 0, ac1.Stake().Cmp(big.NewInt(50)))
 	assert.True(t, ac2.IsEmpty())
 }
+
+func TestAccountCache_SelfStake(t *testing.T) {
+	database := icobject.AttachObjectFactory(db.NewMapDB(), NewObjectImpl)
+	mutable := trie_manager.NewMutableForObject(database, nil, icobject.ObjectType)
+	oss := icobject.NewObjectStoreState(mutable)
+	cache := newAccountCache(oss)
+
+	owner := common.MustNewAddressFromString("hx1")
+	other := common.MustNewAddressFromString("hx2")
+
+	// without self entries
+	account := cache.Get(owner, true)
+	assert.NoError(t, account.SetStake(big.NewInt(100)))
+	account.SetDelegation(Delegations{NewDelegation(other, big.NewInt(10))})
+	account.SetBonds(Bonds{NewBond(other, big.NewInt(20))})
+	assert.Zero(t, account.SelfDelegation().Sign())
+	assert.Zero(t, account.SelfBond().Sign())
+
+	// with self entries
+	account.SetDelegation(Delegations{
+		NewDelegation(other, big.NewInt(10)),
+		NewDelegation(owner, big.NewInt(30)),
+	})
+	account.SetBonds(Bonds{
+		NewBond(owner, big.NewInt(40)),
+		NewBond(other, big.NewInt(20)),
+	})
+	assert.Equal(t, int64(30), account.SelfDelegation().Int64())
+	assert.Equal(t, int64(40), account.SelfBond().Int64())
+
+	// owner is kept for the account loaded again
+	cache.Clear()
+	account = cache.Get(owner, false)
+	assert.Equal(t, int64(30), account.SelfDelegation().Int64())
+	assert.Equal(t, int64(40), account.SelfBond().Int64())
+
+	// owner is unknown
+	a := newAccountStateWithSnapshot(account.GetSnapshot())
+	assert.Zero(t, a.SelfDelegation().Sign())
+	assert.Zero(t, a.SelfBond().Sign())
+}
+
+func TestAccountCache_SharedInState(t *testing.T) {
+	s := newDummyState(false)
+	owner := common.MustNewAddressFromString("hx1")
+
+	// accesses in a state share the same instance
+	account := s.GetAccountState(owner)
+	assert.NoError(t, account.SetStake(big.NewInt(100)))
+	assert.True(t, account == s.GetAccountState(owner))
+	assert.Zero(t, big.NewInt(100).Cmp(s.GetAccountSnapshot(owner).Stake()))
+
+	// but not across states
+	s2 := flushAndNewState(s, false)
+	account2 := s2.GetAccountState(owner)
+	assert.False(t, account == account2)
+	assert.Zero(t, big.NewInt(100).Cmp(account2.Stake()))
+	assert.NoError(t, account2.SetStake(big.NewInt(200)))
+	assert.Zero(t, big.NewInt(100).Cmp(account.Stake()))
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icstate
+
+import (
+	"io"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	accountExportVersion1 = iota + 1
+	accountExportVersion  = accountExportVersion1
+)
+
+// accountExportPreAlloc limits the capacity allocated by the count in the
+// header, so that a broken header can't make ImportAccounts allocate a huge
+// slice before reading any account.
+const accountExportPreAlloc = 1024
+
+type accountExportHeader struct {
+	Version int
+	Count   int
+}
+
+// ExportAccounts writes accounts to w.
+// The header (version and count) is followed by RLP-encoded accounts,
+// and accounts are written to w one by one without building the whole
+// stream in memory.
+func ExportAccounts(w io.Writer, accounts []*AccountSnapshot) error {
+	e := codec.BC.NewEncoder(w)
+	defer e.Close()
+
+	header := &accountExportHeader{
+		Version: accountExportVersion,
+		Count:   len(accounts),
+	}
+	if err := e.Encode(header); err != nil {
+		return errors.Wrap(err, "FailToEncodeHeader")
+	}
+	for i, a := range accounts {
+		if a == nil {
+			return errors.IllegalArgumentError.Errorf("NilAccount(idx=%d)", i)
+		}
+		if err := encodeAccount(e, a); err != nil {
+			return errors.Wrapf(err, "FailToEncodeAccount(idx=%d)", i)
+		}
+	}
+	return e.Close()
+}
+
+func encodeAccount(e codec.Encoder, a *AccountSnapshot) error {
+	e2, err := e.EncodeList()
+	if err != nil {
+		return err
+	}
+	return a.RLPEncodeFields(e2)
+}
+
+// ImportAccounts reads accounts written by ExportAccounts from r.
+func ImportAccounts(r io.Reader) ([]*AccountSnapshot, error) {
+	d := codec.BC.NewDecoder(r)
+	defer d.Close()
+
+	var header accountExportHeader
+	if err := d.Decode(&header); err != nil {
+		return nil, errors.Wrap(err, "FailToDecodeHeader")
+	}
+	if header.Version != accountExportVersion {
+		return nil, errors.UnsupportedError.Errorf(
+			"UnsupportedVersion(version=%d)", header.Version)
+	}
+	if header.Count < 0 {
+		return nil, errors.InvalidStateError.Errorf(
+			"InvalidCount(count=%d)", header.Count)
+	}
+	size := header.Count
+	if size > accountExportPreAlloc {
+		size = accountExportPreAlloc
+	}
+	accounts := make([]*AccountSnapshot, 0, size)
+	for i := 0; i < header.Count; i++ {
+		a, err := decodeAccount(d)
+		if err != nil {
+			return nil, errors.Wrapf(err, "FailToDecodeAccount(idx=%d)", i)
+		}
+		accounts = append(accounts, a)
+	}
+	return accounts, nil
+}
+
+func decodeAccount(d codec.Decoder) (*AccountSnapshot, error) {
+	d2, err := d.DecodeList()
+	if err != nil {
+		return nil, err
+	}
+	a := new(AccountSnapshot)
+	if err = a.RLPDecodeFields(d2); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
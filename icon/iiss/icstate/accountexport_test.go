@@ -0,0 +1,91 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icstate
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+)
+
+func TestExportAccounts(t *testing.T) {
+	const size = 3000
+	accounts := make([]*AccountSnapshot, 0, size)
+	for i := 0; i < size; i++ {
+		as := newAccountStateWithSnapshot(nil)
+		assert.NoError(t, as.SetStake(big.NewInt(int64(1000+i))))
+		if i%2 == 0 {
+			as.SetDelegation(Delegations{
+				NewDelegation(common.AddressToPtr(newDummyAddress(i)), big.NewInt(10)),
+			})
+		}
+		if i%3 == 0 {
+			as.SetBonds(Bonds{
+				NewBond(common.AddressToPtr(newDummyAddress(i+1)), big.NewInt(20)),
+			})
+		}
+		accounts = append(accounts, as.GetSnapshot())
+	}
+	accounts = append(accounts, getTestAccount().GetSnapshot())
+
+	buf := bytes.NewBuffer(nil)
+	assert.NoError(t, ExportAccounts(buf, accounts))
+
+	imported, err := ImportAccounts(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Len(t, imported, len(accounts))
+	for i, a := range accounts {
+		assert.True(t, a.Equal(imported[i]), "account %d", i)
+	}
+
+	// empty set
+	buf.Reset()
+	assert.NoError(t, ExportAccounts(buf, nil))
+	imported, err = ImportAccounts(buf)
+	assert.NoError(t, err)
+	assert.Len(t, imported, 0)
+
+	// nil account
+	assert.Error(t, ExportAccounts(bytes.NewBuffer(nil), []*AccountSnapshot{nil}))
+}
+
+func TestImportAccounts_InvalidHeader(t *testing.T) {
+	// unsupported version
+	bs := codec.BC.MustMarshalToBytes(&accountExportHeader{
+		Version: accountExportVersion + 1,
+		Count:   0,
+	})
+	_, err := ImportAccounts(bytes.NewReader(bs))
+	assert.Error(t, err)
+
+	// less accounts than the count
+	bs = codec.BC.MustMarshalToBytes(&accountExportHeader{
+		Version: accountExportVersion,
+		Count:   1,
+	})
+	_, err = ImportAccounts(bytes.NewReader(bs))
+	assert.Error(t, err)
+
+	// broken header
+	_, err = ImportAccounts(bytes.NewReader([]byte{0x01}))
+	assert.Error(t, err)
+}
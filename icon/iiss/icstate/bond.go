@@ -137,6 +137,16 @@ func (bs Bonds) Contains(addr module.Address) bool {
 	return false
 }
 
+// AmountOf returns the amount bonded to addr. It returns zero if there is no bond to addr.
+func (bs Bonds) AmountOf(addr module.Address) *big.Int {
+	for _, b := range bs {
+		if b.Address.Equal(addr) {
+			return b.Amount()
+		}
+	}
+	return new(big.Int)
+}
+
 func (bs Bonds) Delta(bs2 Bonds) map[string]*big.Int {
 	delta := make(map[string]*big.Int)
 
@@ -17,9 +17,11 @@
 package icstate
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/codec"
@@ -150,6 +152,16 @@ func (ds Delegations) GetDelegationAmount() *big.Int {
 	return total
 }
 
+// AmountOf returns the amount delegated to addr. It returns zero if there is no delegation to addr.
+func (ds Delegations) AmountOf(addr module.Address) *big.Int {
+	for _, d := range ds {
+		if d.To().Equal(addr) {
+			return d.Amount()
+		}
+	}
+	return new(big.Int)
+}
+
 func (ds *Delegations) Delete(i int) error {
 	if i < 0 || i >= len(*ds) {
 		return errors.Errorf("Invalid index")
@@ -169,14 +181,36 @@ func (ds Delegations) ToJSON(_ module.JSONVersion) []interface{} {
 	return jso
 }
 
-func (ds *Delegations) ToMap() map[string]*Delegation {
+// Sorted returns a copy of ds sorted by target address. Order of ds is kept,
+// as it's the order submitted by the delegator.
+func (ds Delegations) Sorted() Delegations {
+	if ds == nil {
+		return nil
+	}
+	ns := make(Delegations, len(ds))
+	copy(ns, ds)
+	sort.SliceStable(ns, func(i, j int) bool {
+		return bytes.Compare(ns[i].Address.Bytes(), ns[j].Address.Bytes()) < 0
+	})
+	return ns
+}
+
+// ToMap returns a map from the key of target address to the amount delegated.
+// Amounts of duplicated delegations to the same address are summed up.
+// Map and its values are newly allocated, so mutating them doesn't affect ds.
+func (ds *Delegations) ToMap() map[string]*big.Int {
 	if !ds.Has() {
 		return nil
 	}
-	m := make(map[string]*Delegation, len(*ds))
+	m := make(map[string]*big.Int, len(*ds))
 
 	for _, d := range *ds {
-		m[icutils.ToKey(d.To())] = d
+		key := icutils.ToKey(d.To())
+		if amount, ok := m[key]; ok {
+			amount.Add(amount, d.Amount())
+		} else {
+			m[key] = new(big.Int).Set(d.Amount())
+		}
 	}
 	return m
 }
@@ -200,6 +234,45 @@ func (ds *Delegations) Iterator() VotingIterator {
 	return NewVotingIterator(ds.getVotings())
 }
 
+// Move returns new delegations which moves value of delegation from one P-Rep
+// to another. Total amount of delegation is not changed.
+func (ds Delegations) Move(from, to module.Address, value *big.Int, max int) (Delegations, error) {
+	if value == nil || value.Sign() <= 0 {
+		return nil, scoreresult.InvalidParameterError.Errorf("Invalid value %v", value)
+	}
+	if from.Equal(to) {
+		return nil, scoreresult.InvalidParameterError.Errorf("Same delegation address")
+	}
+	moved, hasTo := false, false
+	delegations := make(Delegations, 0, len(ds)+1)
+	for _, d := range ds {
+		amount := d.Amount()
+		if d.To().Equal(from) {
+			if amount.Cmp(value) < 0 {
+				break
+			}
+			amount = new(big.Int).Sub(amount, value)
+			moved = true
+		} else if d.To().Equal(to) {
+			amount = new(big.Int).Add(amount, value)
+			hasTo = true
+		}
+		if amount.Sign() > 0 {
+			delegations = append(delegations, NewDelegation(d.Address, amount))
+		}
+	}
+	if !moved {
+		return nil, scoreresult.InvalidParameterError.Errorf("Not enough delegation to %s", from)
+	}
+	if hasTo {
+		return delegations, nil
+	}
+	if len(delegations) >= max {
+		return nil, scoreresult.InvalidParameterError.Errorf("Too many delegations %d", len(delegations)+1)
+	}
+	return append(delegations, NewDelegation(common.AddressToPtr(to), value)), nil
+}
+
 func NewDelegations(param []interface{}, max int) (Delegations, error) {
 	count := len(param)
 	if count > max {
@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
 	"github.com/icon-project/goloop/module"
 )
 
@@ -126,6 +127,52 @@ func TestDelegations(t *testing.T) {
 	assert.Equal(t, v1+v2, ds2.GetDelegationAmount().Int64())
 }
 
+func TestDelegations_ToMap(t *testing.T) {
+	addr1 := common.MustNewAddressFromString("hx1")
+	addr2 := common.MustNewAddressFromString("hx2")
+	ds := Delegations{
+		NewDelegation(addr1, big.NewInt(1)),
+		NewDelegation(addr2, big.NewInt(2)),
+		NewDelegation(addr1, big.NewInt(3)),
+	}
+
+	m := ds.ToMap()
+	assert.Equal(t, 2, len(m))
+	assert.Equal(t, int64(4), m[icutils.ToKey(addr1)].Int64())
+	assert.Equal(t, int64(2), m[icutils.ToKey(addr2)].Int64())
+
+	// mutating the map doesn't affect delegations
+	m[icutils.ToKey(addr2)].SetInt64(10)
+	assert.Equal(t, int64(2), ds[1].Amount().Int64())
+	assert.Equal(t, int64(1), ds[0].Amount().Int64())
+
+	ds = Delegations{}
+	assert.Zero(t, len(ds.ToMap()))
+}
+
+func TestDelegations_Sorted(t *testing.T) {
+	addr1 := common.MustNewAddressFromString("hx1")
+	addr2 := common.MustNewAddressFromString("hx2")
+	addr3 := common.MustNewAddressFromString("cx3")
+	ds := Delegations{
+		NewDelegation(addr2, big.NewInt(2)),
+		NewDelegation(addr3, big.NewInt(3)),
+		NewDelegation(addr1, big.NewInt(1)),
+	}
+
+	sorted := ds.Sorted()
+	assert.Equal(t, 3, len(sorted))
+	for i, addr := range []module.Address{addr1, addr2, addr3} {
+		assert.True(t, addr.Equal(sorted[i].To()))
+	}
+	// submitted order is kept
+	assert.True(t, addr2.Equal(ds[0].To()))
+	assert.True(t, addr3.Equal(ds[1].To()))
+	assert.True(t, addr1.Equal(ds[2].To()))
+
+	assert.Nil(t, Delegations(nil).Sorted())
+}
+
 func TestDelegations_Delete(t *testing.T) {
 	addr1 := "hx1"
 	addr2 := "hx2"
@@ -292,3 +339,47 @@ func TestNewDelegations(t *testing.T) {
 		})
 	}
 }
+
+func TestDelegations_Move(t *testing.T) {
+	addr1 := common.MustNewAddressFromString("hx1")
+	addr2 := common.MustNewAddressFromString("hx2")
+	addr3 := common.MustNewAddressFromString("hx3")
+	ds := Delegations{
+		NewDelegation(addr1, big.NewInt(100)),
+		NewDelegation(addr2, big.NewInt(50)),
+	}
+	total := ds.GetDelegationAmount()
+
+	// move to existing delegation
+	nds, err := ds.Move(addr1, addr2, big.NewInt(30), 10)
+	assert.NoError(t, err)
+	assert.True(t, nds.Equal(Delegations{
+		NewDelegation(addr1, big.NewInt(70)),
+		NewDelegation(addr2, big.NewInt(80)),
+	}))
+	assert.Equal(t, 0, total.Cmp(nds.GetDelegationAmount()))
+	assert.Equal(t, 0, ds[0].Amount().Cmp(big.NewInt(100)), "original is changed")
+
+	// move all to new delegation
+	nds, err = ds.Move(addr1, addr3, big.NewInt(100), 10)
+	assert.NoError(t, err)
+	assert.True(t, nds.Equal(Delegations{
+		NewDelegation(addr2, big.NewInt(50)),
+		NewDelegation(addr3, big.NewInt(100)),
+	}))
+	assert.Equal(t, 0, total.Cmp(nds.GetDelegationAmount()))
+
+	// over-move
+	_, err = ds.Move(addr2, addr1, big.NewInt(51), 10)
+	assert.Error(t, err)
+	_, err = ds.Move(addr3, addr1, big.NewInt(1), 10)
+	assert.Error(t, err)
+
+	// invalid parameters
+	_, err = ds.Move(addr1, addr1, big.NewInt(1), 10)
+	assert.Error(t, err)
+	_, err = ds.Move(addr1, addr2, big.NewInt(0), 10)
+	assert.Error(t, err)
+	_, err = ds.Move(addr1, addr3, big.NewInt(1), 2)
+	assert.Error(t, err)
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icstate
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/containerdb"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoredb"
+)
+
+var (
+	delegatorListPrefix = containerdb.ToKey(
+		containerdb.HashBuilder, scoredb.ArrayDBPrefix, "prep_delegators",
+	)
+	delegatorAmountPrefix = containerdb.ToKey(
+		containerdb.HashBuilder, scoredb.DictDBPrefix, "prep_delegator_amount",
+	)
+	delegatorPositionPrefix = containerdb.ToKey(
+		containerdb.HashBuilder, scoredb.DictDBPrefix, "prep_delegator_position",
+	)
+)
+
+// Delegator is a delegator of a PRep with the amount delegated to the PRep.
+type Delegator struct {
+	Address module.Address
+	Amount  *big.Int
+}
+
+func (d *Delegator) ToJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"address": d.Address,
+		"value":   d.Amount,
+	}
+}
+
+// delegatorIndex is the reverse index of delegations. It keeps delegators of
+// each PRep in a list, and amounts and positions in the list by PRep and
+// delegator.
+type delegatorIndex struct {
+	store     containerdb.ObjectStoreState
+	amounts   *containerdb.DictDB
+	positions *containerdb.DictDB
+}
+
+func (di *delegatorIndex) listOf(prep module.Address) *containerdb.ArrayDB {
+	return containerdb.NewArrayDB(di.store, delegatorListPrefix.Append(prep))
+}
+
+func (di *delegatorIndex) set(prep, delegator module.Address, amount *big.Int) error {
+	if amount.Sign() > 0 {
+		if di.amounts.Get(prep, delegator) == nil {
+			list := di.listOf(prep)
+			if err := di.positions.Set(prep, delegator, list.Size()); err != nil {
+				return err
+			}
+			if err := list.Put(delegator); err != nil {
+				return err
+			}
+		}
+		return di.amounts.Set(prep, delegator, amount)
+	}
+
+	pv := di.positions.Get(prep, delegator)
+	if pv == nil {
+		return nil
+	}
+	// move the last one to the position of the removed one
+	list := di.listOf(prep)
+	pos := int(pv.Int64())
+	last := list.Pop().Address()
+	if !last.Equal(delegator) {
+		if err := list.Set(pos, last); err != nil {
+			return err
+		}
+		if err := di.positions.Set(prep, last, pos); err != nil {
+			return err
+		}
+	}
+	if err := di.positions.Delete(prep, delegator); err != nil {
+		return err
+	}
+	return di.amounts.Delete(prep, delegator)
+}
+
+func newDelegatorIndex(store containerdb.ObjectStoreState) *delegatorIndex {
+	return &delegatorIndex{
+		store:     store,
+		amounts:   containerdb.NewDictDB(store, 2, delegatorAmountPrefix),
+		positions: containerdb.NewDictDB(store, 2, delegatorPositionPrefix),
+	}
+}
+
+// UpdateDelegatorIndex updates delegators of PReps on changing delegations of
+// delegator from old to ds.
+func (s *State) UpdateDelegatorIndex(delegator module.Address, old, ds Delegations) error {
+	oMap := old.ToMap()
+	nMap := ds.ToMap()
+	keys := make([]string, 0, len(oMap)+len(nMap))
+	for key := range oMap {
+		keys = append(keys, key)
+	}
+	for key := range nMap {
+		if _, ok := oMap[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	// positions in the list depend on the order of updates
+	sort.Strings(keys)
+
+	di := newDelegatorIndex(s.store)
+	for _, key := range keys {
+		prep, err := common.NewAddress([]byte(key))
+		if err != nil {
+			return errors.Wrapf(err, "InvalidDelegationKey(%x)", key)
+		}
+		amount, ok := nMap[key]
+		if !ok {
+			amount = new(big.Int)
+		}
+		if err = di.set(prep, delegator, amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetDelegatorsOf returns delegators of prep sorted by amount in descending
+// order, and the number of all delegators. Only limit delegators are returned
+// if limit is positive.
+func (s *State) GetDelegatorsOf(prep module.Address, limit int) ([]*Delegator, int) {
+	di := newDelegatorIndex(s.store)
+	list := di.listOf(prep)
+	size := list.Size()
+	delegators := make([]*Delegator, 0, size)
+	for i := 0; i < size; i++ {
+		addr := list.Get(i).Address()
+		delegators = append(delegators, &Delegator{
+			Address: addr,
+			Amount:  di.amounts.Get(prep, addr).BigInt(),
+		})
+	}
+	sort.Slice(delegators, func(i, j int) bool {
+		if c := delegators[i].Amount.Cmp(delegators[j].Amount); c != 0 {
+			return c > 0
+		}
+		return bytes.Compare(delegators[i].Address.Bytes(), delegators[j].Address.Bytes()) < 0
+	})
+	if limit > 0 && len(delegators) > limit {
+		delegators = delegators[:limit]
+	}
+	return delegators, size
+}
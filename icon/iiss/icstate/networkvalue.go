@@ -52,6 +52,11 @@ const (
 	VarNonVotePenaltySlashRate              = "nonvote_penalty_slashRatio"
 	DictSlashingRate                        = "slashing_rate"
 	VarMinBond                              = "minimum_bond"
+	VarIScoreICXRatio                       = "iscore_icx_ratio"
+	VarStakeReductionPolicy                 = "stake_reduction_policy"
+	VarMinDelegation                        = "minimum_delegation"
+	VarMaxValidators                        = "max_validators"
+	VarRegistrationBond                     = "registration_bond"
 )
 
 const (
@@ -499,6 +504,143 @@ func (s *State) SetMinimumBond(bond *big.Int) error {
 	return setValue(s.store, VarMinBond, bond)
 }
 
+// GetRegistrationBond returns the voting power required to register a P-Rep
+func (s *State) GetRegistrationBond() *big.Int {
+	ret := getValue(s.store, VarRegistrationBond).BigInt()
+	if ret == nil {
+		ret = icmodule.BigIntZero
+	}
+	return ret
+}
+
+func (s *State) SetRegistrationBond(bond *big.Int) error {
+	if bond == nil {
+		return scoreresult.InvalidParameterError.Errorf("RegistrationBondIsNil")
+	}
+	if bond.Sign() < 0 {
+		return scoreresult.InvalidParameterError.Errorf("NegativeRegistrationBond")
+	}
+	return setValue(s.store, VarRegistrationBond, bond)
+}
+
+// GetIScoreICXRatio returns the amount of I-Score converted to 1 loop.
+// It returns icmodule.BigIntIScoreICXRatio if it's not set.
+func (s *State) GetIScoreICXRatio() *big.Int {
+	ret := getValue(s.store, VarIScoreICXRatio).BigInt()
+	if ret == nil {
+		ret = icmodule.BigIntIScoreICXRatio
+	}
+	return ret
+}
+
+func (s *State) SetIScoreICXRatio(ratio *big.Int) error {
+	if ratio == nil || ratio.Sign() <= 0 {
+		return scoreresult.InvalidParameterError.Errorf("InvalidIScoreICXRatio(%v)", ratio)
+	}
+	return setValue(s.store, VarIScoreICXRatio, ratio)
+}
+
+// IScoreToICX converts iScore to loop with the ratio in the state.
+// It rounds down and returns the remainder of I-Score.
+func (s *State) IScoreToICX(iScore *big.Int) (*big.Int, *big.Int) {
+	return new(big.Int).DivMod(iScore, s.GetIScoreICXRatio(), new(big.Int))
+}
+
+// StakeReductionPolicy decides how to handle delegations exceeding the stake
+// when the stake is reduced.
+type StakeReductionPolicy int
+
+const (
+	// StakeReductionReject rejects the stake reduction
+	StakeReductionReject StakeReductionPolicy = iota
+	// StakeReductionTrimDelegation reduces delegations proportionally
+	StakeReductionTrimDelegation
+)
+
+func (p StakeReductionPolicy) IsValid() bool {
+	return p == StakeReductionReject || p == StakeReductionTrimDelegation
+}
+
+// GetStakeReductionPolicy returns the policy for stake reduction.
+// It returns StakeReductionReject if it's not set.
+func (s *State) GetStakeReductionPolicy() StakeReductionPolicy {
+	return StakeReductionPolicy(getValue(s.store, VarStakeReductionPolicy).Int64())
+}
+
+func (s *State) SetStakeReductionPolicy(p StakeReductionPolicy) error {
+	if !p.IsValid() {
+		return scoreresult.InvalidParameterError.Errorf("InvalidStakeReductionPolicy(%d)", p)
+	}
+	return setValue(s.store, VarStakeReductionPolicy, int64(p))
+}
+
+// GetMinimumDelegation returns the minimum amount of a delegation to a target.
+// It returns zero if it's not set, which means that there is no minimum.
+func (s *State) GetMinimumDelegation() *big.Int {
+	ret := getValue(s.store, VarMinDelegation).BigInt()
+	if ret == nil {
+		ret = icmodule.BigIntZero
+	}
+	return ret
+}
+
+func (s *State) SetMinimumDelegation(amount *big.Int) error {
+	if amount == nil || amount.Sign() < 0 {
+		return scoreresult.InvalidParameterError.Errorf("InvalidMinimumDelegation(%v)", amount)
+	}
+	return setValue(s.store, VarMinDelegation, amount)
+}
+
+// GetMaxValidators returns the maximum number of validators.
+// It returns zero if it's not set, which means that there is no limit.
+func (s *State) GetMaxValidators() int64 {
+	return getValue(s.store, VarMaxValidators).Int64()
+}
+
+// MinMaxValidators is the lower bound of the maximum number of validators
+// except zero, for the network to tolerate a faulty validator.
+const MinMaxValidators = 4
+
+func (s *State) SetMaxValidators(value int64) error {
+	if value < 0 || (value > 0 && value < MinMaxValidators) {
+		return scoreresult.InvalidParameterError.Errorf("InvalidMaxValidators(%d)", value)
+	}
+	return setValue(s.store, VarMaxValidators, value)
+}
+
+// LimitValidators returns the number of validators out of size candidates
+// ordered by power under the maximum number of validators. Candidates over
+// the limit are dropped if truncate is true. Otherwise, it returns an error.
+func (s *State) LimitValidators(size int, truncate bool) (int, error) {
+	maxValidators := s.GetMaxValidators()
+	if maxValidators <= 0 || int64(size) <= maxValidators {
+		return size, nil
+	}
+	if !truncate {
+		return 0, icmodule.IllegalArgumentError.Errorf(
+			"TooManyValidators(size=%d,max=%d)", size, maxValidators)
+	}
+	return int(maxValidators), nil
+}
+
+// LimitPRepCountConfig returns cfg with main P-Reps including extra main P-Reps
+// limited by the maximum number of validators. Main P-Reps over the limit are
+// counted as sub P-Reps, so the number of elected P-Reps isn't changed.
+func (s *State) LimitPRepCountConfig(cfg PRepCountConfig) PRepCountConfig {
+	mains := cfg.MainPReps() + cfg.ExtraMainPReps()
+	size, _ := s.LimitValidators(mains, true)
+	if size == mains {
+		return cfg
+	}
+	main := cfg.MainPReps()
+	extra := cfg.ExtraMainPReps() - (mains - size)
+	if extra < 0 {
+		main += extra
+		extra = 0
+	}
+	return NewPRepCountConfig(main, cfg.SubPReps()+mains-size, extra)
+}
+
 func (s *State) GetNetworkInfoInJSON(revision int) (map[string]interface{}, error) {
 	br := s.GetBondRequirement(revision)
 	jso := make(map[string]interface{})
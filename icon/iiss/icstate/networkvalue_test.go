@@ -522,6 +522,36 @@ func TestState_SetDelegationSlotMax(t *testing.T) {
 	}
 }
 
+func TestState_SetIScoreICXRatio(t *testing.T) {
+	state := newDummyState(false)
+	assert.Zero(t, state.GetIScoreICXRatio().Cmp(icmodule.BigIntIScoreICXRatio))
+
+	for _, ratio := range []*big.Int{nil, big.NewInt(0), big.NewInt(-1)} {
+		err := state.SetIScoreICXRatio(ratio)
+		assert.Error(t, err)
+		assert.Zero(t, state.GetIScoreICXRatio().Cmp(icmodule.BigIntIScoreICXRatio))
+	}
+
+	iScore := big.NewInt(12_345)
+	for _, tc := range []struct {
+		ratio, icx, remainder int64
+	}{
+		{1_000, 12, 345},
+		{100, 123, 45},
+	} {
+		err := state.SetIScoreICXRatio(big.NewInt(tc.ratio))
+		assert.NoError(t, err)
+
+		assert.NoError(t, state.Flush())
+		state.ClearCache()
+		assert.Equal(t, tc.ratio, state.GetIScoreICXRatio().Int64())
+
+		icx, remainder := state.IScoreToICX(iScore)
+		assert.Equal(t, tc.icx, icx.Int64())
+		assert.Equal(t, tc.remainder, remainder.Int64())
+	}
+}
+
 func TestState_SetNonVotePenaltySlashRate(t *testing.T) {
 	var slashingRate icmodule.Rate
 	state := newDummyState(false)
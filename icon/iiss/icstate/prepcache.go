@@ -110,6 +110,7 @@ type PRepStatusCache struct {
 	lasts    map[string]*PRepStatusSnapshot
 	dict     *containerdb.DictDB
 	illegal  *containerdb.DictDB
+	observer PRepStatusObserver
 }
 
 func (c *PRepStatusCache) Get(owner module.Address, createIfNotExist bool) *PRepStatusState {
@@ -123,6 +124,7 @@ func (c *PRepStatusCache) Get(owner module.Address, createIfNotExist bool) *PRep
 	if o == nil {
 		if createIfNotExist {
 			status = NewPRepStatus(owner)
+			status.setObserver(c.observer)
 			c.statuses[key] = status
 		}
 	} else {
@@ -132,6 +134,7 @@ func (c *PRepStatusCache) Get(owner module.Address, createIfNotExist bool) *PRep
 			if value := c.illegal.Get(owner); value != nil {
 				status.SetEffectiveDelegated(new(big.Int).Add(status.Delegated(), value.BigInt()))
 			}
+			status.setObserver(c.observer)
 			c.statuses[key] = status
 			c.lasts[key] = snapshot
 		}
@@ -139,6 +142,15 @@ func (c *PRepStatusCache) Get(owner module.Address, createIfNotExist bool) *PRep
 	return status
 }
 
+// SetObserver sets the observer for statuses in the cache.
+// nil observer disables notifications.
+func (c *PRepStatusCache) SetObserver(observer PRepStatusObserver) {
+	c.observer = observer
+	for _, status := range c.statuses {
+		status.setObserver(observer)
+	}
+}
+
 func (c *PRepStatusCache) Clear() {
 	c.Flush()
 	c.statuses = make(map[string]*PRepStatusState)
@@ -0,0 +1,197 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icstate
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
+	"github.com/icon-project/goloop/module"
+)
+
+// prepIndexEntry holds the values used for ranking at the time of indexing,
+// so that the entry can be located even after its PRepStatus is changed.
+type prepIndexEntry struct {
+	owner     module.Address
+	hasPubKey bool
+	electable bool
+	power     *big.Int
+	delegated *big.Int
+}
+
+type prepIndexParams struct {
+	checkElectable bool
+	br             icmodule.Rate
+	dsaMask        int64
+}
+
+func newPRepIndexParams(sc icmodule.StateContext) prepIndexParams {
+	return prepIndexParams{
+		checkElectable: sc.RevisionValue() >= icmodule.RevisionBTP2,
+		br:             sc.GetBondRequirement(),
+		dsaMask:        sc.GetActiveDSAMask(),
+	}
+}
+
+// less returns true if e0 is ranked higher than e1. It follows lessByPower.
+func (p *prepIndexParams) less(e0, e1 *prepIndexEntry) bool {
+	if p.checkElectable {
+		if e0.hasPubKey != e1.hasPubKey {
+			return e0.hasPubKey
+		}
+		if e0.electable != e1.electable {
+			return e0.electable
+		}
+	}
+	if ret := e0.power.Cmp(e1.power); ret != 0 {
+		return ret > 0
+	}
+	if ret := e0.delegated.Cmp(e1.delegated); ret != 0 {
+		return ret > 0
+	}
+	return bytes.Compare(e0.owner.Bytes(), e1.owner.Bytes()) > 0
+}
+
+func (p *prepIndexParams) newEntry(ps *PRepStatusState) *prepIndexEntry {
+	return &prepIndexEntry{
+		owner:     ps.Owner(),
+		hasPubKey: ps.GetDSAMask()&p.dsaMask == p.dsaMask,
+		electable: ps.IsJailInfoElectable(),
+		power:     ps.GetPower(p.br),
+		delegated: new(big.Int).Set(ps.Delegated()),
+	}
+}
+
+// prepIndex keeps active PReps ordered by power.
+// Owners of changed PRepStatus are collected and re-positioned with binary
+// search on the next query, instead of sorting all PReps again.
+// The whole index is rebuilt if ranking parameters are changed.
+type prepIndex struct {
+	valid   bool
+	params  prepIndexParams
+	entries []*prepIndexEntry
+	keys    map[string]*prepIndexEntry
+	dirty   map[string]module.Address
+}
+
+func (idx *prepIndex) onChange(owner module.Address) {
+	if idx.valid {
+		idx.dirty[icutils.ToKey(owner)] = owner
+	}
+}
+
+func (idx *prepIndex) invalidate() {
+	idx.valid = false
+	idx.entries = nil
+	idx.keys = make(map[string]*prepIndexEntry)
+	idx.dirty = make(map[string]module.Address)
+}
+
+func (idx *prepIndex) search(e *prepIndexEntry) int {
+	return sort.Search(len(idx.entries), func(i int) bool {
+		return !idx.params.less(idx.entries[i], e)
+	})
+}
+
+func (idx *prepIndex) remove(key string) {
+	e, ok := idx.keys[key]
+	if !ok {
+		return
+	}
+	i := idx.search(e)
+	copy(idx.entries[i:], idx.entries[i+1:])
+	idx.entries[len(idx.entries)-1] = nil
+	idx.entries = idx.entries[:len(idx.entries)-1]
+	delete(idx.keys, key)
+}
+
+func (idx *prepIndex) insert(key string, e *prepIndexEntry) {
+	i := idx.search(e)
+	idx.entries = append(idx.entries, nil)
+	copy(idx.entries[i+1:], idx.entries[i:])
+	idx.entries[i] = e
+	idx.keys[key] = e
+}
+
+func (idx *prepIndex) rebuild(s *State, params prepIndexParams) {
+	idx.invalidate()
+	idx.params = params
+	size := s.allPRepCache.Size()
+	for i := 0; i < size; i++ {
+		owner := s.allPRepCache.Get(i)
+		if ps := s.GetPRepStatusByOwner(owner, false); ps != nil && ps.IsActive() {
+			e := params.newEntry(ps)
+			idx.entries = append(idx.entries, e)
+			idx.keys[icutils.ToKey(owner)] = e
+		}
+	}
+	sort.Slice(idx.entries, func(i, j int) bool {
+		return params.less(idx.entries[i], idx.entries[j])
+	})
+	idx.valid = true
+}
+
+func (idx *prepIndex) sync(s *State, sc icmodule.StateContext) {
+	params := newPRepIndexParams(sc)
+	if !idx.valid || idx.params != params {
+		idx.rebuild(s, params)
+		return
+	}
+	for key, owner := range idx.dirty {
+		idx.remove(key)
+		if ps := s.GetPRepStatusByOwner(owner, false); ps != nil && ps.IsActive() {
+			idx.insert(key, params.newEntry(ps))
+		}
+	}
+	idx.dirty = make(map[string]module.Address)
+}
+
+// owners returns owners of active PReps ordered by power.
+func (idx *prepIndex) owners(s *State, sc icmodule.StateContext) []module.Address {
+	idx.sync(s, sc)
+	owners := make([]module.Address, len(idx.entries))
+	for i, e := range idx.entries {
+		owners[i] = e.owner
+	}
+	return owners
+}
+
+func (idx *prepIndex) clone() *prepIndex {
+	n := newPRepIndex()
+	if !idx.valid {
+		return n
+	}
+	n.valid = true
+	n.params = idx.params
+	n.entries = append(make([]*prepIndexEntry, 0, len(idx.entries)), idx.entries...)
+	for k, v := range idx.keys {
+		n.keys[k] = v
+	}
+	for k, v := range idx.dirty {
+		n.dirty[k] = v
+	}
+	return n
+}
+
+func newPRepIndex() *prepIndex {
+	idx := new(prepIndex)
+	idx.invalidate()
+	return idx
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icstate
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
+)
+
+func newStateWithPReps(t testing.TB, size int) *State {
+	s := newDummyState(false)
+	for i := 0; i < size; i++ {
+		owner := newDummyAddress(i + 1)
+		err := s.RegisterPRep(owner, newDummyPRepInfo(i+1), icmodule.BigIntInitialIRep, 0)
+		assert.NoError(t, err)
+		ps := s.GetPRepStatusByOwner(owner, false)
+		ps.SetDelegated(big.NewInt(rand.Int63n(1000)))
+		ps.SetBonded(big.NewInt(rand.Int63n(100)))
+	}
+	return s
+}
+
+func assertPRepIndexOrder(t *testing.T, sc icmodule.StateContext, s *State) {
+	expected := s.GetPReps(true)
+	SortByPower(sc, expected)
+	preps := s.GetOrderedPReps(sc)
+	assert.Equal(t, len(expected), len(preps))
+	for i := range expected {
+		assert.True(t, expected[i].Owner().Equal(preps[i].Owner()), "index=%d", i)
+	}
+}
+
+func TestState_GetOrderedPReps(t *testing.T) {
+	size := 100
+	sc := newMockStateContext(map[string]interface{}{
+		"blockHeight":   int64(1000),
+		"revision":      icmodule.RevisionIISS4R1,
+		"activeDSAMask": int64(1),
+	})
+	s := newStateWithPReps(t, size)
+	assertPRepIndexOrder(t, sc, s)
+
+	// delegation and bond changes
+	for i := 0; i < size; i += 3 {
+		ps := s.GetPRepStatusByOwner(newDummyAddress(i+1), false)
+		ps.SetDelegated(big.NewInt(rand.Int63n(1000)))
+		ps.SetBonded(big.NewInt(rand.Int63n(100)))
+	}
+	assertPRepIndexOrder(t, sc, s)
+
+	// public key
+	for i := 0; i < size; i += 5 {
+		s.GetPRepStatusByOwner(newDummyAddress(i+1), false).SetDSAMask(1)
+	}
+	assertPRepIndexOrder(t, sc, s)
+
+	// slashing
+	ps := s.GetPRepStatusByOwner(newDummyAddress(7), false)
+	ps.SetBonded(new(big.Int))
+	assertPRepIndexOrder(t, sc, s)
+
+	// unregistration
+	for i := 0; i < size; i += 10 {
+		_, err := s.GetPRepStatusByOwner(newDummyAddress(i+1), false).DisableAs(Unregistered)
+		assert.NoError(t, err)
+	}
+	preps := s.GetOrderedPReps(sc)
+	assert.Equal(t, size-size/10, len(preps))
+	assertPRepIndexOrder(t, sc, s)
+
+	// registration
+	owner := newDummyAddress(size + 1)
+	err := s.RegisterPRep(owner, newDummyPRepInfo(size+1), icmodule.BigIntInitialIRep, 0)
+	assert.NoError(t, err)
+	s.GetPRepStatusByOwner(owner, false).SetDelegated(big.NewInt(500))
+	assertPRepIndexOrder(t, sc, s)
+
+	// bond requirement change
+	sc2 := newMockStateContext(map[string]interface{}{
+		"blockHeight":     int64(1000),
+		"revision":        icmodule.RevisionIISS4R1,
+		"activeDSAMask":   int64(1),
+		"bondRequirement": icmodule.ToRate(10),
+	})
+	assertPRepIndexOrder(t, sc2, s)
+
+	// read-only view has the same order
+	view := s.newReadOnlyView()
+	assertPRepIndexOrder(t, sc2, view)
+}
+
+func BenchmarkState_GetOrderedPReps(b *testing.B) {
+	size := 5000
+	sc := newMockStateContext(map[string]interface{}{
+		"blockHeight": int64(1000),
+		"revision":    icmodule.RevisionIISS4R1,
+	})
+	s := newStateWithPReps(b, size)
+	s.GetOrderedPReps(sc)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ps := s.GetPRepStatusByOwner(newDummyAddress(i%size+1), false)
+		ps.SetDelegated(big.NewInt(rand.Int63n(1000)))
+		s.GetOrderedPReps(sc)
+	}
+}
+
+func TestState_VerifyPRepConsistency(t *testing.T) {
+	size := 10
+	sc := newMockStateContext(map[string]interface{}{
+		"blockHeight": int64(1000),
+		"revision":    icmodule.RevisionIISS4R1,
+	})
+	s := newDummyState(false)
+	totalDelegated := new(big.Int)
+	for i := 0; i < size; i++ {
+		owner := newDummyAddress(i + 1)
+		info := newDummyPRepInfo(i + 1)
+		info.Node = newDummyAddress(i + 101)
+		err := s.RegisterPRep(owner, info, icmodule.BigIntInitialIRep, 0)
+		assert.NoError(t, err)
+		delegated := big.NewInt(int64(100 * (i + 1)))
+		s.GetPRepStatusByOwner(owner, false).SetDelegated(delegated)
+		totalDelegated.Add(totalDelegated, delegated)
+	}
+	assert.NoError(t, s.SetTotalDelegation(totalDelegated))
+	assert.Empty(t, s.VerifyPRepConsistency(sc))
+
+	// corrupt node-owner index
+	s.nodeOwnerCache.nodeToOwner[icutils.ToKey(newDummyAddress(101))] = newDummyAddress(2)
+
+	// corrupt total delegation
+	assert.NoError(t, s.SetTotalDelegation(new(big.Int).Add(totalDelegated, big.NewInt(1))))
+
+	// corrupt ranking index by changing delegated without notification
+	s.GetOrderedPReps(sc)
+	s.GetPRepStatusByOwner(newDummyAddress(1), false).delegated = big.NewInt(10_000)
+
+	errs := s.VerifyPRepConsistency(sc)
+	assert.Equal(t, 3, len(errs))
+	for _, err := range errs {
+		assert.Error(t, err)
+	}
+	assert.Contains(t, errs[0].Error(), "NodeOwnerMismatch")
+	assert.Contains(t, errs[1].Error(), "TotalDelegationMismatch")
+	assert.Contains(t, errs[2].Error(), "RankingMismatch")
+}
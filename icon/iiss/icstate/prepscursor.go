@@ -0,0 +1,98 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icstate
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/icon/icmodule"
+)
+
+const (
+	DefaultPRepsPageLimit = 100
+	MaxPRepsPageLimit     = 1000
+)
+
+// prepsCursor holds the sort key of the last prep returned in a page.
+// Next page starts with the first prep ranked after the key,
+// so it is not affected by preps inserted or removed between pages.
+type prepsCursor struct {
+	HasPubKey     bool
+	JailElectable bool
+	Power         *big.Int
+	Delegated     *big.Int
+	Owner         *common.Address
+}
+
+func (c *prepsCursor) Bytes() []byte {
+	return codec.BC.MustMarshalToBytes(c)
+}
+
+// rankedBefore returns true if c is ranked before o.
+// It follows the same priority as SortByPower.
+func (c *prepsCursor) rankedBefore(o *prepsCursor, rev int) bool {
+	if rev >= icmodule.RevisionBTP2 {
+		if c.HasPubKey != o.HasPubKey {
+			return c.HasPubKey
+		}
+		if c.JailElectable != o.JailElectable {
+			return c.JailElectable
+		}
+	}
+	if ret := c.Power.Cmp(o.Power); ret != 0 {
+		return ret > 0
+	}
+	if ret := c.Delegated.Cmp(o.Delegated); ret != 0 {
+		return ret > 0
+	}
+	return bytes.Compare(c.Owner.Bytes(), o.Owner.Bytes()) > 0
+}
+
+func newPRepsCursor(sc icmodule.StateContext, prep *PRep) *prepsCursor {
+	return &prepsCursor{
+		HasPubKey:     prep.HasPubKey(sc.GetActiveDSAMask()),
+		JailElectable: prep.IsJailInfoElectable(),
+		Power:         prep.GetPower(sc.GetBondRequirement()),
+		Delegated:     prep.Delegated(),
+		Owner:         common.AddressToPtr(prep.Owner()),
+	}
+}
+
+func newPRepsCursorFromBytes(bs []byte) (*prepsCursor, error) {
+	c := new(prepsCursor)
+	if _, err := codec.BC.UnmarshalFromBytes(bs, c); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidCursor")
+	}
+	if c.Power == nil || c.Delegated == nil || c.Owner == nil {
+		return nil, errors.IllegalArgumentError.New("InvalidCursor")
+	}
+	return c, nil
+}
+
+// indexAfterCursor returns the index of the first prep ranked after the cursor.
+// preps should be sorted by SortByPower.
+func indexAfterCursor(sc icmodule.StateContext, preps []*PRep, c *prepsCursor) int {
+	rev := sc.RevisionValue()
+	return sort.Search(len(preps), func(i int) bool {
+		return c.rankedBefore(newPRepsCursor(sc, preps[i]), rev)
+	})
+}
@@ -194,6 +194,17 @@ func (ps *prepStatusData) GetBondedDelegation(br icmodule.Rate) *big.Int {
 	return icutils.CalcPower(br, ps.bonded, ps.getVoted())
 }
 
+// GetBondedRatio returns bonded / (bonded + delegated) in basis points.
+// It returns zero if both of them are zero.
+func (ps *prepStatusData) GetBondedRatio() *big.Int {
+	total := new(big.Int).Add(ps.bonded, ps.delegated)
+	if total.Sign() <= 0 {
+		return new(big.Int)
+	}
+	ratio := new(big.Int).Mul(ps.bonded, big.NewInt(icmodule.DenomInRate))
+	return ratio.Div(ratio, total)
+}
+
 // GetPower returns the power score of a PRep.
 // Power is the same as delegated of a given PRep before rev 14
 // and will be bondedDelegation since rev 14.
@@ -202,6 +213,16 @@ func (ps *prepStatusData) GetPower(bondRequirement icmodule.Rate) *big.Int {
 	return ps.GetBondedDelegation(bondRequirement)
 }
 
+// GetEffectiveDelegation returns the amount of delegation counted in the power
+// under bondRequirement, that is, power - bonded.
+func (ps *prepStatusData) GetEffectiveDelegation(bondRequirement icmodule.Rate) *big.Int {
+	ed := new(big.Int).Sub(ps.GetPower(bondRequirement), ps.bonded)
+	if ed.Sign() < 0 {
+		return new(big.Int)
+	}
+	return ed
+}
+
 func (ps *prepStatusData) VTotal() int64 {
 	return ps.vTotal
 }
@@ -297,6 +318,9 @@ func (ps *prepStatusData) ToJSON(sc icmodule.StateContext) map[string]interface{
 	jso["delegated"] = ps.delegated
 	jso["bonded"] = ps.bonded
 	jso["power"] = ps.GetPower(br)
+	if sc.RevisionValue() >= icmodule.RevisionEffectiveDelegatedJSON {
+		jso["effectiveDelegated"] = ps.GetEffectiveDelegation(br)
+	}
 	totalBlocks := ps.GetVTotal(blockHeight)
 	jso["totalBlocks"] = totalBlocks
 	jso["validatedBlocks"] = totalBlocks - ps.GetVFail(blockHeight)
@@ -549,10 +573,14 @@ var emptyPRepStatusSnapshot = &PRepStatusSnapshot{
 	},
 }
 
+// PRepStatusObserver is called whenever a PRepStatusState is changed.
+type PRepStatusObserver func(owner module.Address)
+
 type PRepStatusState struct {
 	owner module.Address
 	prepStatusData
-	last *PRepStatusSnapshot
+	last     *PRepStatusSnapshot
+	observer PRepStatusObserver
 }
 
 func (ps *PRepStatusState) Owner() module.Address {
@@ -573,6 +601,13 @@ func (ps *PRepStatusState) setDirty() {
 	if ps.last != nil {
 		ps.last = nil
 	}
+	if ps.observer != nil {
+		ps.observer(ps.owner)
+	}
+}
+
+func (ps *PRepStatusState) setObserver(observer PRepStatusObserver) {
+	ps.observer = observer
 }
 
 func (ps *PRepStatusState) Clear() {
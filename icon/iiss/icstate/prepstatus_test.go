@@ -88,6 +88,30 @@ func TestPRepStatus_GetBondedDelegation(t *testing.T) {
 	}
 }
 
+func TestPRepStatus_GetBondedRatio(t *testing.T) {
+	tests := []struct {
+		delegated int64
+		bonded    int64
+		ratio     int64
+	}{
+		{0, 0, 0},
+		{100, 0, 0},
+		{0, 100, 10000},
+		{50, 50, 5000},
+		{9900, 100, 100},
+		{2, 1, 3333},
+	}
+	for i, tt := range tests {
+		name := fmt.Sprintf("d=%d,b=%d", tt.delegated, tt.bonded)
+		t.Run(name, func(t *testing.T) {
+			ps := NewPRepStatus(newDummyAddress(i + 1))
+			ps.SetDelegated(big.NewInt(tt.delegated))
+			ps.SetBonded(big.NewInt(tt.bonded))
+			assert.Equal(t, tt.ratio, ps.GetBondedRatio().Int64())
+		})
+	}
+}
+
 func TestPRepStatus_GetVTotal(t *testing.T) {
 	type args struct {
 		vTotal      int64
@@ -837,6 +861,39 @@ func TestPRepStatusData_ToJSON(t *testing.T) {
 	assert.Zero(t, power.Sign())
 }
 
+func TestPRepStatusData_ToJSONEffectiveDelegated(t *testing.T) {
+	br := icmodule.ToRate(5)
+	ps := NewPRepStatus(newDummyAddress(1))
+	ps.SetDelegated(big.NewInt(1000))
+	ps.SetBonded(big.NewInt(10))
+
+	sc := newMockStateContext(map[string]interface{}{
+		"blockHeight": int64(100),
+		"revision":    icmodule.RevisionEffectiveDelegatedJSON - 1,
+		"br":          br,
+	})
+	jso := ps.ToJSON(sc)
+	_, ok := jso["effectiveDelegated"]
+	assert.False(t, ok)
+
+	// below the bond requirement, power = 10 * 100 / 5 = 200
+	sc = newMockStateContext(map[string]interface{}{
+		"blockHeight": int64(100),
+		"revision":    icmodule.RevisionEffectiveDelegatedJSON,
+		"br":          br,
+	})
+	jso = ps.ToJSON(sc)
+	ed := jso["effectiveDelegated"].(*big.Int)
+	assert.Equal(t, int64(190), ed.Int64())
+	assert.True(t, ed.Cmp(ps.Delegated()) < 0)
+	assert.Zero(t, new(big.Int).Add(ed, ps.Bonded()).Cmp(jso["power"].(*big.Int)))
+
+	// enough bond
+	ps.SetBonded(big.NewInt(100))
+	jso = ps.ToJSON(sc)
+	assert.Zero(t, ps.Delegated().Cmp(jso["effectiveDelegated"].(*big.Int)))
+}
+
 func TestNewPRepStatus(t *testing.T) {
 	owner := newDummyAddress(1)
 	ps := NewPRepStatus(owner)
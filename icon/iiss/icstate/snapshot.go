@@ -17,11 +17,13 @@
 package icstate
 
 import (
+	"github.com/icon-project/goloop/common/containerdb"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/merkle"
 	"github.com/icon-project/goloop/common/trie"
 	"github.com/icon-project/goloop/common/trie/trie_manager"
 	"github.com/icon-project/goloop/icon/iiss/icobject"
+	"github.com/icon-project/goloop/module"
 )
 
 type Snapshot struct {
@@ -39,6 +41,20 @@ func (ss *Snapshot) Flush() error {
 	return nil
 }
 
+func (ss *Snapshot) Filter(prefix []byte) trie.IteratorForObject {
+	return ss.store.Filter(prefix)
+}
+
+// GetAccountSnapshot returns the account of the owner in the snapshot.
+// It returns nil if there is no account.
+func (ss *Snapshot) GetAccountSnapshot(owner module.Address) *AccountSnapshot {
+	o := containerdb.NewDictDB(ss.store, 1, AccountDictPrefix).Get(owner)
+	if o == nil {
+		return nil
+	}
+	return ToAccount(o.Object())
+}
+
 func (ss *Snapshot) GetValue(key []byte) ([]byte, error) {
 	var value []byte
 	o, err := ss.store.Get(key)
@@ -53,7 +53,8 @@ var (
 	LastBlockVotersKey = containerdb.ToKey(
 		containerdb.HashBuilder, scoredb.VarDBPrefix, "lastBlockVoters",
 	)
-	termKey = containerdb.ToKey(containerdb.HashBuilder, scoredb.VarDBPrefix, "term")
+	termKey     = containerdb.ToKey(containerdb.HashBuilder, scoredb.VarDBPrefix, "term")
+	prevTermKey = containerdb.ToKey(containerdb.HashBuilder, scoredb.VarDBPrefix, "prev_term")
 
 	pRepIllegalDelegatedKey = containerdb.ToKey(containerdb.HashBuilder, scoredb.DictDBPrefix, "prep_illegal_delegated")
 )
@@ -68,6 +69,7 @@ type State struct {
 	unstakingTimerCache    *TimerCache
 	unbondingTimerCache    *TimerCache
 	networkScoreTimerCache *TimerCache
+	prepIndex              *prepIndex
 	logger                 log.Logger
 
 	store                *icobject.ObjectStoreState
@@ -76,6 +78,7 @@ type State struct {
 	validatorsVarDB      *containerdb.VarDB
 	lastBlockVotersVarDB *containerdb.VarDB
 	termVarDB            *containerdb.VarDB
+	prevTermVarDB        *containerdb.VarDB
 
 	pRepIllegalDelegatedDB *containerdb.DictDB
 }
@@ -89,6 +92,7 @@ func (s *State) Reset(ss *Snapshot) error {
 	s.unstakingTimerCache.Reset()
 	s.unbondingTimerCache.Reset()
 	s.networkScoreTimerCache.Reset()
+	s.prepIndex.invalidate()
 	return nil
 }
 
@@ -115,6 +119,12 @@ func (s *State) GetAccountState(addr module.Address) *AccountState {
 	return a
 }
 
+// SetAccountObserver sets the observer notified of changes on
+// account totals. nil observer disables notifications.
+func (s *State) SetAccountObserver(observer AccountObserver) {
+	s.accountCache.SetObserver(observer)
+}
+
 func (s *State) GetAccountSnapshot(addr module.Address) *AccountSnapshot {
 	return s.accountCache.GetSnapshot(addr)
 }
@@ -173,18 +183,24 @@ func NewStateFromTrie(t trie.MutableForObject, readonly bool, logger log.Logger)
 	validatorsVarDB := containerdb.NewVarDB(store, ValidatorsKey)
 	lastBlockVotersVarDB := containerdb.NewVarDB(store, LastBlockVotersKey)
 	termVarDB := containerdb.NewVarDB(store, termKey)
+	prevTermVarDB := containerdb.NewVarDB(store, prevTermKey)
 	pRepIllegalDelegatedDB := containerdb.NewDictDB(store, 1, pRepIllegalDelegatedKey)
 
+	prepIndex := newPRepIndex()
+	prepStatusCache := newPRepStatusCache(store)
+	prepStatusCache.SetObserver(prepIndex.onChange)
+
 	return &State{
 		readonly:               readonly,
 		accountCache:           newAccountCache(store),
 		allPRepCache:           NewAllPRepCache(store),
 		nodeOwnerCache:         newNodeOwnerCache(store),
 		prepBaseCache:          newPRepBaseCache(store),
-		prepStatusCache:        newPRepStatusCache(store),
+		prepStatusCache:        prepStatusCache,
 		unstakingTimerCache:    newTimerCache(store, unstakingTimerDictPrefix),
 		unbondingTimerCache:    newTimerCache(store, unbondingTimerDictPrefix),
 		networkScoreTimerCache: newTimerCache(store, networkScoreTimerDictPrefix),
+		prepIndex:              prepIndex,
 		logger:                 logger,
 
 		store:                store,
@@ -193,6 +209,7 @@ func NewStateFromTrie(t trie.MutableForObject, readonly bool, logger log.Logger)
 		validatorsVarDB:      validatorsVarDB,
 		lastBlockVotersVarDB: lastBlockVotersVarDB,
 		termVarDB:            termVarDB,
+		prevTermVarDB:        prevTermVarDB,
 
 		pRepIllegalDelegatedDB: pRepIllegalDelegatedDB,
 	}
@@ -240,6 +257,16 @@ func (s *State) SetTermSnapshot(term *TermSnapshot) error {
 	return s.termVarDB.Set(icobject.New(TypeTerm, term))
 }
 
+// GetPrevTermSnapshot returns the term snapshot which was replaced
+// by the current one at the last term end.
+func (s *State) GetPrevTermSnapshot() *TermSnapshot {
+	return ToTerm(s.prevTermVarDB.Object())
+}
+
+func (s *State) SetPrevTermSnapshot(term *TermSnapshot) error {
+	return s.prevTermVarDB.Set(icobject.New(TypeTerm, term))
+}
+
 func (s *State) SetRewardCalcInfo(rc *RewardCalcInfo) error {
 	_, err := s.store.Set(RewardCalcInfoKey, icobject.New(TypeRewardCalcInfo, rc))
 	if err != nil {
@@ -573,6 +600,76 @@ func (s *State) GetPReps(activeOnly bool) []*PRep {
 	return preps
 }
 
+// GetOrderedPReps returns active PReps ordered by power.
+// It uses the index maintained on changes of PRepStatus, so it doesn't
+// sort all PReps on every call. It's only for queries, so the selection of
+// main and sub PReps doesn't depend on it.
+func (s *State) GetOrderedPReps(sc icmodule.StateContext) []*PRep {
+	owners := s.prepIndex.owners(s, sc)
+	preps := make([]*PRep, len(owners))
+	for i, owner := range owners {
+		preps[i] = s.GetPRepByOwner(owner)
+	}
+	return preps
+}
+
+// VerifyPRepConsistency checks invariants of P-Rep related data and returns
+// all the inconsistencies found instead of failing at the first one.
+// It's for diagnosing state corruption.
+func (s *State) VerifyPRepConsistency(sc icmodule.StateContext) []error {
+	var errs []error
+	totalDelegated := new(big.Int)
+	totalBonded := new(big.Int)
+	size := s.allPRepCache.Size()
+	for i := 0; i < size; i++ {
+		owner := s.allPRepCache.Get(i)
+		ps := s.GetPRepStatusByOwner(owner, false)
+		if ps == nil {
+			errs = append(errs, errors.InvalidStateError.Errorf("PRepStatusNotFound(owner=%s)", owner))
+			continue
+		}
+		if !ps.IsActive() {
+			continue
+		}
+		totalDelegated.Add(totalDelegated, ps.Delegated())
+		totalBonded.Add(totalBonded, ps.Bonded())
+
+		node := s.GetNodeByOwner(owner)
+		if node == nil {
+			errs = append(errs, errors.InvalidStateError.Errorf("PRepBaseNotFound(owner=%s)", owner))
+		} else if nodeOwner := s.GetOwnerByNode(node); !owner.Equal(nodeOwner) {
+			errs = append(errs, errors.InvalidStateError.Errorf(
+				"NodeOwnerMismatch(owner=%s,node=%s,nodeOwner=%s)", owner, node, nodeOwner))
+		}
+	}
+	if td := s.GetTotalDelegation(); td.Cmp(totalDelegated) != 0 {
+		errs = append(errs, errors.InvalidStateError.Errorf(
+			"TotalDelegationMismatch(total=%s,sum=%s)", td, totalDelegated))
+	}
+	if tb := s.GetTotalBond(); tb.Cmp(totalBonded) != 0 {
+		errs = append(errs, errors.InvalidStateError.Errorf(
+			"TotalBondMismatch(total=%s,sum=%s)", tb, totalBonded))
+	}
+
+	expected := s.GetPReps(true)
+	SortByPower(sc, expected)
+	owners := s.prepIndex.owners(s, sc)
+	if len(owners) != len(expected) {
+		errs = append(errs, errors.InvalidStateError.Errorf(
+			"RankingSizeMismatch(index=%d,expected=%d)", len(owners), len(expected)))
+	} else {
+		// a misplaced P-Rep shifts the following ones, so report the first one
+		for i, prep := range expected {
+			if !prep.Owner().Equal(owners[i]) {
+				errs = append(errs, errors.InvalidStateError.Errorf(
+					"RankingMismatch(rank=%d,index=%s,expected=%s)", i+1, owners[i], prep.Owner()))
+				break
+			}
+		}
+	}
+	return errs
+}
+
 func (s *State) GetPRepStatsInJSON(sc icmodule.StateContext) (map[string]interface{}, error) {
 	// Gets the unsorted list of PRepStatus
 	pss, err := s.GetPRepStatuses()
@@ -627,9 +724,24 @@ func (s *State) GetPRepStatsOfInJSON(sc icmodule.StateContext, address module.Ad
 	}, nil
 }
 
+// newReadOnlyView returns a readonly State built from the current snapshot of s.
+// Changes made to s afterward are not visible through the returned State.
+func (s *State) newReadOnlyView() *State {
+	view := NewStateFromSnapshot(s.GetSnapshot(), true, s.logger)
+	view.prepIndex = s.prepIndex.clone()
+	view.prepStatusCache.SetObserver(view.prepIndex.onChange)
+	return view
+}
+
+// GetPRepsInJSON returns active preps in the given ranking range.
+// All the values are read from a single snapshot taken at the beginning of the call,
+// so rankings, entries and totals in the result are consistent with each other.
 func (s *State) GetPRepsInJSON(sc icmodule.StateContext, start, end int) (map[string]interface{}, error) {
-	activePReps := s.GetPReps(true)
-	SortByPower(sc, activePReps)
+	return s.newReadOnlyView().getPRepsInJSON(sc, start, end)
+}
+
+func (s *State) getPRepsInJSON(sc icmodule.StateContext, start, end int) (map[string]interface{}, error) {
+	activePReps := s.GetOrderedPReps(sc)
 
 	if start < 0 {
 		return nil, errors.IllegalArgumentError.Errorf("start(%d) < 0", start)
@@ -674,6 +786,54 @@ func (s *State) GetPRepsInJSON(sc icmodule.StateContext, start, end int) (map[st
 	return jso, nil
 }
 
+// GetPRepsPageInJSON returns up to limit active preps ranked after the given cursor.
+// If cursor is empty, it starts with the first ranked prep.
+// nextCursor is included in the result only if there are more preps to return.
+func (s *State) GetPRepsPageInJSON(sc icmodule.StateContext, cursor []byte, limit int) (map[string]interface{}, error) {
+	return s.newReadOnlyView().getPRepsPageInJSON(sc, cursor, limit)
+}
+
+func (s *State) getPRepsPageInJSON(sc icmodule.StateContext, cursor []byte, limit int) (map[string]interface{}, error) {
+	if limit < 0 {
+		return nil, errors.IllegalArgumentError.Errorf("limit(%d) < 0", limit)
+	}
+	if limit == 0 {
+		limit = DefaultPRepsPageLimit
+	} else if limit > MaxPRepsPageLimit {
+		limit = MaxPRepsPageLimit
+	}
+
+	activePReps := s.GetOrderedPReps(sc)
+
+	start := 0
+	if len(cursor) > 0 {
+		c, err := newPRepsCursorFromBytes(cursor)
+		if err != nil {
+			return nil, err
+		}
+		start = indexAfterCursor(sc, activePReps, c)
+	}
+	size := len(activePReps)
+	end := start + limit
+	if end > size {
+		end = size
+	}
+
+	prepList := make([]interface{}, 0, end-start)
+	for i := start; i < end; i++ {
+		prepList = append(prepList, activePReps[i].ToJSON(sc))
+	}
+
+	jso := make(map[string]interface{})
+	jso["startRanking"] = start + 1
+	jso["blockHeight"] = sc.BlockHeight()
+	jso["preps"] = prepList
+	if end < size {
+		jso["nextCursor"] = newPRepsCursor(sc, activePReps[end-1]).Bytes()
+	}
+	return jso, nil
+}
+
 func (s *State) CheckValidationPenalty(ps *PRepStatusState, blockHeight int64) bool {
 	condition := s.GetValidationPenaltyCondition()
 	return checkValidationPenalty(ps, blockHeight, condition)
@@ -697,8 +857,7 @@ func checkConsistentValidationPenalty(ps *PRepStatusState, condition int) bool {
 	return condition > 0 && ps.GetVPenaltyCount() >= condition
 }
 
-func (s *State) GetUnstakeLockPeriod(revision int, totalSupply *big.Int) int64 {
-	totalStake := s.GetTotalStake()
+func (s *State) getUnstakeLockPeriodRange(revision int) (*big.Int, *big.Int) {
 	termPeriod := new(big.Int)
 	if revision < icmodule.RevisionStopICON1Support {
 		termPeriod.SetInt64(icmodule.InitialTermPeriod)
@@ -707,9 +866,23 @@ func (s *State) GetUnstakeLockPeriod(revision int, totalSupply *big.Int) int64 {
 	}
 	lMin := new(big.Int).Mul(s.GetLockMinMultiplier(), termPeriod)
 	lMax := new(big.Int).Mul(s.GetLockMaxMultiplier(), termPeriod)
+	return lMin, lMax
+}
+
+func (s *State) GetUnstakeLockPeriod(revision int, totalSupply *big.Int) int64 {
+	totalStake := s.GetTotalStake()
+	lMin, lMax := s.getUnstakeLockPeriodRange(revision)
 	return CalcUnstakeLockPeriod(lMin, lMax, totalStake, totalSupply)
 }
 
+// GetStakeForUnstakeLockPeriod returns the minimum total stake for the
+// unstake lock period not greater than period, and the period clamped to
+// the valid range.
+func (s *State) GetStakeForUnstakeLockPeriod(revision int, totalSupply *big.Int, period int64) (*big.Int, int64) {
+	lMin, lMax := s.getUnstakeLockPeriodRange(revision)
+	return CalcStakeForUnstakeLockPeriod(lMin, lMax, period, totalSupply)
+}
+
 func (s *State) SetIllegalDelegation(id *IllegalDelegation) error {
 	dict := containerdb.NewDictDB(s.store, 1, IllegalDelegationPrefix)
 	o := icobject.New(TypeIllegalDelegation, id)
@@ -1069,3 +1069,131 @@ func TestState_InitCommissionInfo(t *testing.T) {
 	assert.Equal(t, int64(maxRate), jso["maxCommissionRate"])
 	assert.Equal(t, int64(maxChangeRate), jso["maxCommissionChangeRate"])
 }
+
+func TestState_GetPRepsInJSON(t *testing.T) {
+	var err error
+	size := 5
+	sc := newMockStateContext(map[string]interface{}{"blockHeight": int64(100)})
+	state := newDummyState(false)
+
+	owners := make([]module.Address, size)
+	for i := 0; i < size; i++ {
+		owners[i] = newDummyAddress(i)
+		err = state.RegisterPRep(owners[i], newDummyPRepInfo(i), nil, 0)
+		assert.NoError(t, err)
+		ps := state.GetPRepStatusByOwner(owners[i], false)
+		ps.SetDelegated(big.NewInt(int64(100 * (i + 1))))
+		ps.SetBonded(big.NewInt(int64(10 * (i + 1))))
+	}
+
+	sumOf := func(jso map[string]interface{}) *big.Int {
+		sum := new(big.Int)
+		for _, v := range jso["preps"].([]interface{}) {
+			sum.Add(sum, v.(map[string]interface{})["delegated"].(*big.Int))
+		}
+		return sum
+	}
+
+	jso, err := state.GetPRepsInJSON(sc, 0, 0)
+	assert.NoError(t, err)
+	assert.Len(t, jso["preps"], size)
+	assert.Zero(t, sumOf(jso).Cmp(jso["totalDelegated"].(*big.Int)))
+
+	// Simulate mutations happening while a listing is in progress
+	view := state.newReadOnlyView()
+	state.GetPRepStatusByOwner(owners[0], false).SetDelegated(big.NewInt(100_000))
+	state.GetPRepStatusByOwner(owners[1], false).SetStatus(Unregistered)
+
+	jso, err = view.getPRepsInJSON(sc, 0, 0)
+	assert.NoError(t, err)
+	assert.Len(t, jso["preps"], size)
+	assert.Zero(t, sumOf(jso).Cmp(jso["totalDelegated"].(*big.Int)))
+	assert.Zero(t, big.NewInt(1500).Cmp(jso["totalDelegated"].(*big.Int)))
+
+	// The first prep must still be ranked last in the snapshot
+	preps := jso["preps"].([]interface{})
+	assert.True(t, owners[0].Equal(preps[size-1].(map[string]interface{})["address"].(module.Address)))
+
+	// New listing reflects the mutations consistently
+	jso, err = state.GetPRepsInJSON(sc, 0, 0)
+	assert.NoError(t, err)
+	assert.Len(t, jso["preps"], size-1)
+	assert.Zero(t, sumOf(jso).Cmp(jso["totalDelegated"].(*big.Int)))
+	assert.Zero(t, big.NewInt(100_000+300+400+500).Cmp(jso["totalDelegated"].(*big.Int)))
+}
+
+func TestState_GetPRepsPageInJSON(t *testing.T) {
+	var err error
+	size := 7
+	sc := newMockStateContext(map[string]interface{}{"blockHeight": int64(100)})
+	state := newDummyState(false)
+
+	for i := 0; i < size; i++ {
+		owner := newDummyAddress(i)
+		err = state.RegisterPRep(owner, newDummyPRepInfo(i), nil, 0)
+		assert.NoError(t, err)
+		ps := state.GetPRepStatusByOwner(owner, false)
+		ps.SetDelegated(big.NewInt(int64(100 * (i + 1))))
+		ps.SetBonded(big.NewInt(int64(10 * (i + 1))))
+	}
+
+	addressesOf := func(jso map[string]interface{}) []string {
+		var addrs []string
+		for _, v := range jso["preps"].([]interface{}) {
+			addrs = append(addrs, v.(map[string]interface{})["address"].(module.Address).String())
+		}
+		return addrs
+	}
+
+	jso, err := state.GetPRepsInJSON(sc, 0, 0)
+	assert.NoError(t, err)
+	expected := addressesOf(jso)
+	assert.Len(t, expected, size)
+
+	for _, limit := range []int{1, 2, 3, size, size + 1} {
+		var cursor []byte
+		var addrs []string
+		for pages := 0; ; pages++ {
+			assert.True(t, pages <= size)
+			jso, err = state.GetPRepsPageInJSON(sc, cursor, limit)
+			assert.NoError(t, err)
+			assert.Equal(t, len(addrs)+1, jso["startRanking"])
+			addrs = append(addrs, addressesOf(jso)...)
+			next, ok := jso["nextCursor"]
+			if !ok {
+				break
+			}
+			cursor = next.([]byte)
+		}
+		assert.Equal(t, expected, addrs, "limit=%d", limit)
+	}
+
+	// Pages are stable against insertions and deletions between calls
+	jso, err = state.GetPRepsPageInJSON(sc, nil, 3)
+	assert.NoError(t, err)
+	addrs := addressesOf(jso)
+	cursor := jso["nextCursor"].([]byte)
+
+	// owner6 ranks first, so inserting above it doesn't affect the next page
+	top := newDummyAddress(size)
+	err = state.RegisterPRep(top, newDummyPRepInfo(size), nil, 0)
+	assert.NoError(t, err)
+	state.GetPRepStatusByOwner(top, false).SetDelegated(big.NewInt(100_000))
+	state.GetPRepStatusByOwner(top, false).SetBonded(big.NewInt(100_000))
+	// remove the last prep returned and one in the next page
+	state.GetPRepStatusByOwner(newDummyAddress(size-3), false).SetStatus(Unregistered)
+	state.GetPRepStatusByOwner(newDummyAddress(size-4), false).SetStatus(Unregistered)
+
+	jso, err = state.GetPRepsPageInJSON(sc, cursor, 0)
+	assert.NoError(t, err)
+	_, ok := jso["nextCursor"]
+	assert.False(t, ok)
+	addrs = append(addrs, addressesOf(jso)...)
+	assert.Equal(t, append(expected[:3:3], expected[4:]...), addrs)
+
+	// Invalid parameters
+	_, err = state.GetPRepsPageInJSON(sc, []byte{0x01, 0x02}, 0)
+	assert.Error(t, err)
+	_, err = state.GetPRepsPageInJSON(sc, nil, -1)
+	assert.Error(t, err)
+}
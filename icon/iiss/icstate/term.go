@@ -202,6 +202,10 @@ func (term *termDataCommon) TotalSupply() *big.Int {
 	return term.totalSupply
 }
 
+func (term *termDataCommon) TotalDelegated() *big.Int {
+	return term.totalDelegated
+}
+
 func (term *termDataCommon) IsDecentralized() bool {
 	return term.isDecentralized
 }
@@ -395,6 +399,19 @@ func newTermDataExtV2(minimumBond *big.Int) *termDataExtV2 {
 
 // ========================================================
 
+// TermInfo is a read-only view of a term used across APIs and reward calculation.
+type TermInfo struct {
+	Sequence       int
+	StartHeight    int64
+	EndHeight      int64
+	TotalSupply    *big.Int
+	TotalDelegated *big.Int
+	Irep           *big.Int
+	Rrep           *big.Int
+	MainPRepCount  int
+	SubPRepCount   int
+}
+
 type termData struct {
 	termDataCommon
 	*termDataExtV1
@@ -439,6 +456,26 @@ func (term *termData) ToJSON(sc icmodule.StateContext, state *State) map[string]
 	return jso
 }
 
+// Info returns the values of the term which are commonly used regardless of
+// its version. Irep and Rrep are zero since termVersion2.
+func (term *termData) Info() *TermInfo {
+	subPRepCount := term.GetElectedPRepCount() - term.mainPRepCount
+	if subPRepCount < 0 {
+		subPRepCount = 0
+	}
+	return &TermInfo{
+		Sequence:       term.sequence,
+		StartHeight:    term.startHeight,
+		EndHeight:      term.GetEndHeight(),
+		TotalSupply:    term.totalSupply,
+		TotalDelegated: term.totalDelegated,
+		Irep:           term.Irep(),
+		Rrep:           term.Rrep(),
+		MainPRepCount:  term.mainPRepCount,
+		SubPRepCount:   subPRepCount,
+	}
+}
+
 func (term *termData) String() string {
 	sb := strings.Builder{}
 	sb.WriteString("Term{")
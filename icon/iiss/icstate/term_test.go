@@ -751,3 +751,34 @@ func TestNewNextTerm(t *testing.T) {
 	assert.Nil(t, jso["rrep"])
 	assert.Zero(t, minBond.Cmp(jso["minimumBond"].(*big.Int)))
 }
+
+func TestTermSnapshot_Info(t *testing.T) {
+	term := newTermState(termVersion1, 10, 43200)
+	term.startHeight = 1000
+	term.totalSupply = big.NewInt(1_000_000)
+	term.totalDelegated = big.NewInt(300_000)
+	term.mainPRepCount = 22
+	term.prepSnapshots = newDummyPRepSnapshots(30)
+	term.SetIrep(big.NewInt(1234))
+	term.SetRrep(big.NewInt(5678))
+
+	info := term.GetSnapshot().Info()
+	assert.Equal(t, 10, info.Sequence)
+	assert.Equal(t, int64(1000), info.StartHeight)
+	assert.Equal(t, int64(1000+43200-1), info.EndHeight)
+	assert.Zero(t, big.NewInt(1_000_000).Cmp(info.TotalSupply))
+	assert.Zero(t, big.NewInt(300_000).Cmp(info.TotalDelegated))
+	assert.Zero(t, big.NewInt(1234).Cmp(info.Irep))
+	assert.Zero(t, big.NewInt(5678).Cmp(info.Rrep))
+	assert.Equal(t, 22, info.MainPRepCount)
+	assert.Equal(t, 8, info.SubPRepCount)
+
+	// irep and rrep are not used since termVersion2
+	term = newTermState(termVersion2, 11, 43200)
+	term.mainPRepCount = 22
+	term.prepSnapshots = newDummyPRepSnapshots(10)
+	info = term.GetSnapshot().Info()
+	assert.Zero(t, info.Irep.Sign())
+	assert.Zero(t, info.Rrep.Sign())
+	assert.Equal(t, 0, info.SubPRepCount)
+}
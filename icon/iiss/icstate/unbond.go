@@ -229,6 +229,32 @@ func (ul *Unbonds) Slash(address module.Address, rate icmodule.Rate) (Unbonds, *
 	return newUnbonds, amount, expire
 }
 
+// SlashAtHeight slashes unbonds expiring at height regardless of address.
+// It returns new unbonds, total slashed amount and height if slashed unbonds
+// are removed (otherwise, -1).
+func (ul *Unbonds) SlashAtHeight(height int64, rate icmodule.Rate) (Unbonds, *big.Int, int64) {
+	expire := int64(-1)
+	amount := big.NewInt(0)
+	newUnbonds := make(Unbonds, 0)
+
+	for _, u := range *ul {
+		if u.Expire() == height {
+			unbond := u.Clone()
+			amount.Add(amount, unbond.Slash(rate))
+
+			percent := rate.Percent()
+			if percent < 100 {
+				newUnbonds = append(newUnbonds, unbond)
+			} else if percent == 100 {
+				expire = height
+			}
+		} else {
+			newUnbonds = append(newUnbonds, u)
+		}
+	}
+	return newUnbonds, amount, expire
+}
+
 func (ul Unbonds) ToJSON(_ module.JSONVersion) []interface{} {
 	if ul.IsEmpty() {
 		return nil
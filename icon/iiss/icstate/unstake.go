@@ -22,6 +22,7 @@ import (
 
 	"github.com/icon-project/goloop/common/codec"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/icon/icmodule"
 	"github.com/icon-project/goloop/module"
 )
@@ -129,6 +130,17 @@ func (us Unstakes) IsEmpty() bool {
 	return len(us) == 0
 }
 
+// Earliest returns the unstake expiring first. It returns nil if there is no unstake.
+func (us Unstakes) Earliest() *Unstake {
+	var earliest *Unstake
+	for _, u := range us {
+		if earliest == nil || u.GetExpire() < earliest.GetExpire() {
+			earliest = u
+		}
+	}
+	return earliest
+}
+
 // GetUnstakeAmount return unstake Value
 func (us Unstakes) GetUnstakeAmount() *big.Int {
 	total := new(big.Int)
@@ -190,6 +202,9 @@ func (us Unstakes) findIndex(h int64) int64 {
 	return 0
 }
 
+// decreaseUnstake consumes unstake slots by v on stake increase. It consumes
+// the slot expiring last first, so the amounts to be unlocked soon stay
+// unchanged.
 func (us *Unstakes) decreaseUnstake(v *big.Int, expireHeight int64, revision int) ([]TimerJobInfo, error) {
 	if v.Sign() == -1 {
 		return nil, errors.Errorf("Invalid unstake Value %v", v)
@@ -248,3 +263,32 @@ func CalcUnstakeLockPeriod(lMin *big.Int, lMax *big.Int, totalStake *big.Int, to
 
 	return iResult.Add(iResult, lMin).Int64()
 }
+
+// CalcStakeForUnstakeLockPeriod returns the minimum total stake which makes
+// CalcUnstakeLockPeriod return a value not greater than period.
+// period is clamped to [lMin, lMax] and the clamped one is returned together.
+func CalcStakeForUnstakeLockPeriod(lMin *big.Int, lMax *big.Int, period int64, totalSupply *big.Int) (*big.Int, int64) {
+	if period < lMin.Int64() {
+		period = lMin.Int64()
+	} else if period > lMax.Int64() {
+		period = lMax.Int64()
+	}
+	if totalSupply.Sign() <= 0 {
+		return new(big.Int), period
+	}
+
+	// lock period decreases as total stake increases
+	low := new(big.Int)
+	high := new(big.Int).Set(totalSupply)
+	mid := new(big.Int)
+	for low.Cmp(high) < 0 {
+		mid.Add(low, high)
+		mid.Rsh(mid, 1)
+		if CalcUnstakeLockPeriod(lMin, lMax, mid, totalSupply) <= period {
+			high.Set(mid)
+		} else {
+			low.Add(mid, intconv.BigIntOne)
+		}
+	}
+	return low, period
+}
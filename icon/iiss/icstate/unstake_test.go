@@ -310,3 +310,43 @@ func TestCalcUnstakeLockPeriod(t *testing.T) {
 		prevPeriod = periodInBlock
 	}
 }
+
+func TestCalcStakeForUnstakeLockPeriod(t *testing.T) {
+	termPeriod := int64(icmodule.DayBlock)
+	lMin := big.NewInt(5 * termPeriod)
+	lMax := big.NewInt(20 * termPeriod)
+	totalSupply := new(big.Int).Exp(big.NewInt(10), big.NewInt(27), nil)
+	one := big.NewInt(1)
+
+	// max period needs no stake
+	stake, period := CalcStakeForUnstakeLockPeriod(lMin, lMax, lMax.Int64(), totalSupply)
+	assert.Zero(t, stake.Sign())
+	assert.Equal(t, lMax.Int64(), period)
+
+	// longer period is clamped to the max
+	stake, period = CalcStakeForUnstakeLockPeriod(lMin, lMax, lMax.Int64()+1, totalSupply)
+	assert.Zero(t, stake.Sign())
+	assert.Equal(t, lMax.Int64(), period)
+
+	// min period
+	minStake, period := CalcStakeForUnstakeLockPeriod(lMin, lMax, lMin.Int64(), totalSupply)
+	assert.Equal(t, lMin.Int64(), period)
+	assert.Equal(t, lMin.Int64(), CalcUnstakeLockPeriod(lMin, lMax, minStake, totalSupply))
+	assert.Less(t, lMin.Int64(),
+		CalcUnstakeLockPeriod(lMin, lMax, new(big.Int).Sub(minStake, one), totalSupply))
+
+	// shorter period is clamped to the min
+	stake, period = CalcStakeForUnstakeLockPeriod(lMin, lMax, 0, totalSupply)
+	assert.Equal(t, 0, minStake.Cmp(stake))
+	assert.Equal(t, lMin.Int64(), period)
+
+	// periods in between
+	for _, p := range []int64{6 * termPeriod, 10 * termPeriod, 19 * termPeriod} {
+		stake, period = CalcStakeForUnstakeLockPeriod(lMin, lMax, p, totalSupply)
+		assert.Equal(t, p, period)
+		assert.True(t, stake.Sign() > 0 && stake.Cmp(minStake) < 0)
+		assert.LessOrEqual(t, CalcUnstakeLockPeriod(lMin, lMax, stake, totalSupply), p)
+		assert.Less(t, p,
+			CalcUnstakeLockPeriod(lMin, lMax, new(big.Int).Sub(stake, one), totalSupply))
+	}
+}
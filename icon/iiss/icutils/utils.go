@@ -43,6 +43,8 @@ const (
 	PortMax       = 65536
 	EmailLocalMax = 64
 	EmailMax      = 254
+
+	ICXDecimals = 18
 )
 
 var (
@@ -230,6 +232,40 @@ func ValidateCountryAlpha3(alpha3 string) error {
 	return nil
 }
 
+// FormatDecimal formats x / 10^decimals as a decimal string with precision
+// digits after the decimal point. Remaining digits are truncated.
+func FormatDecimal(x *big.Int, decimals, precision int) string {
+	if decimals < 0 || precision < 0 {
+		return ""
+	}
+	abs := new(big.Int).Abs(x)
+	ip, fp := new(big.Int).QuoRem(abs, Pow10(decimals), new(big.Int))
+	sign := ""
+	if x.Sign() < 0 {
+		sign = "-"
+	}
+	if precision == 0 {
+		return sign + ip.String()
+	}
+	var fs string
+	if decimals > 0 {
+		fs = fp.String()
+		fs = strings.Repeat("0", decimals-len(fs)) + fs
+	}
+	if precision <= len(fs) {
+		fs = fs[:precision]
+	} else {
+		fs += strings.Repeat("0", precision-len(fs))
+	}
+	return sign + ip.String() + "." + fs
+}
+
+// FormatICX formats the amount in loop as ICX with precision digits after
+// the decimal point.
+func FormatICX(loop *big.Int, precision int) string {
+	return FormatDecimal(loop, ICXDecimals, precision)
+}
+
 func ICXToIScore(icx *big.Int) *big.Int {
 	return new(big.Int).Mul(icx, icmodule.BigIntIScoreICXRatio)
 }
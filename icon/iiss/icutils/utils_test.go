@@ -47,6 +47,29 @@ func TestToLoop(t *testing.T) {
 	}
 }
 
+func TestFormatDecimal(t *testing.T) {
+	loop, _ := new(big.Int).SetString("1234567890000000000000", 10)
+	args := []struct {
+		x         *big.Int
+		decimals  int
+		precision int
+		expected  string
+	}{
+		{loop, 18, 4, "1234.5678"},
+		{loop, 18, 0, "1234"},
+		{loop, 18, 20, "1234.56789000000000000000"},
+		{new(big.Int).Neg(loop), 18, 2, "-1234.56"},
+		{big.NewInt(5), 18, 18, "0.000000000000000005"},
+		{big.NewInt(0), 18, 2, "0.00"},
+		{big.NewInt(1234), 0, 2, "1234.00"},
+		{big.NewInt(1234), -1, 2, ""},
+	}
+	for _, arg := range args {
+		assert.Equal(t, arg.expected, FormatDecimal(arg.x, arg.decimals, arg.precision))
+	}
+	assert.Equal(t, "1234.567", FormatICX(loop, 3))
+}
+
 func TestValidateRange(t *testing.T) {
 
 	type args struct {
@@ -176,9 +176,21 @@ func (es *ExtensionStateImpl) slash(cc icmodule.CallContext, owner module.Addres
 			}
 
 			// stake
-			slashedStake.Add(slashedBond, slashedUnbond)
-			if err := account.SlashStake(slashedStake); err != nil {
-				return err
+			amount := new(big.Int).Add(slashedBond, slashedUnbond)
+			if cc.Revision().Value() < icmodule.RevisionSlashStakeClamp {
+				if err := account.SetStake(new(big.Int).Sub(account.Stake(), amount)); err != nil {
+					return err
+				}
+				slashedStake = amount
+			} else {
+				slashedStake = account.SlashStake(amount)
+				if slashedStake.Cmp(amount) != 0 {
+					// stake is less than bond and unbond, so the account is broken
+					es.logger.Warnf("Slashed stake is clamped bonder=%s stake=%v amount=%v slashed=%v",
+						bonder, account.Stake(), amount, slashedStake)
+					logger.TSystemf("IISS slash clamped bonder=%s amount=%v slashed=%v",
+						bonder, amount, slashedStake)
+				}
 			}
 			slashedStakeSum.Add(slashedStakeSum, slashedStake)
 
@@ -109,6 +109,10 @@ func (t *transition) NormalReceipts() module.ReceiptList {
 	return t.receipts
 }
 
+func (t *transition) Receipts() module.ReceiptList {
+	return t.receipts
+}
+
 func (t *transition) BTPSection() module.BTPSection {
 	return btp.ZeroBTPSection
 }
@@ -48,6 +48,7 @@ var revHandlerTable = []revHandlerItem{
 	{icmodule.RevisionFixIssueRegulator, onRevFixIssueRegulator},
 	{icmodule.RevisionRecoverUnderIssuance, onRevRecoverUnderIssuance},
 	{icmodule.RevisionSetBondRequirementRate, onRevSetBondRequirementRate},
+	{icmodule.RevisionDelegatorIndex, onRevDelegatorIndex},
 }
 
 // DO NOT update revHandlerMap manually
@@ -490,3 +491,8 @@ func onRevSetBondRequirementRate(s *chainScore, rev, _ int) error {
 	es := s.cc.GetExtensionState().(*iiss.ExtensionStateImpl)
 	return es.State.MigrateBondRequirement(rev)
 }
+
+func onRevDelegatorIndex(s *chainScore, _, _ int) error {
+	es := s.cc.GetExtensionState().(*iiss.ExtensionStateImpl)
+	return es.MigrateDelegatorIndex()
+}
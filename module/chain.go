@@ -38,6 +38,7 @@ type Chain interface {
 	ChildrenLimit() int
 	NephewsLimit() int
 	ValidateTxOnSend() bool
+	VoteTimestampSkew() time.Duration
 	Genesis() []byte
 	GenesisStorage() GenesisStorage
 	CommitVoteSetDecoder() CommitVoteSetDecoder
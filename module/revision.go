@@ -39,6 +39,7 @@ const (
 	ReportDoubleSign
 	FixJCLSteps
 	ReportConfigureEvents
+	CapStepRefund
 	LastRevisionBit
 
 	UseNIDInConsensusMessage = ReportDoubleSign
@@ -197,6 +197,13 @@ type Transition interface {
 
 	PatchReceipts() ReceiptList
 	NormalReceipts() ReceiptList
+
+	// Receipts returns receipts of all transactions in execution order,
+	// patch transactions first. Hash of the list is not a part of Result,
+	// so use PatchReceipts and NormalReceipts for proofs.
+	// It may return nil before cb.OnExecute is called back by Execute.
+	Receipts() ReceiptList
+
 	// Execute executes this transition.
 	// The result is asynchronously notified by cb. canceler can be used
 	// to cancel it after calling Execute. After canceler returns true,
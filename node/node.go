@@ -21,6 +21,7 @@ import (
 	"github.com/icon-project/goloop/server"
 	"github.com/icon-project/goloop/server/metric"
 	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/transaction"
 )
 
 var (
@@ -375,6 +376,14 @@ func (n *Node) JoinChain(
 		return nil, errors.Wrap(err, "fail to get NID for genesis")
 	}
 
+	if gt, err := genesisStorage.Type(); err != nil {
+		return nil, errors.Wrap(err, "fail to get type of genesis")
+	} else if gt == module.GenesisNormal {
+		if err := transaction.ValidateGenesis(genesisStorage.Genesis()); err != nil {
+			return nil, errors.Wrap(err, "invalid genesis")
+		}
+	}
+
 	channel := chain.GetChannel(p.Channel, nid)
 
 	if err := n._canAdd(cid, nid, channel, false); err != nil {
@@ -403,6 +412,8 @@ func (n *Node) JoinChain(
 		NormalTxPoolSize: p.NormalTxPoolSize,
 		PatchTxPoolSize:  p.PatchTxPoolSize,
 		MaxBlockTxBytes:  p.MaxBlockTxBytes,
+		MaxTxPerBlock:    p.MaxTxPerBlock,
+		MaxBlockSteps:    p.MaxBlockSteps,
 		NodeCache:        p.NodeCache,
 		DefWaitTimeout:   p.DefWaitTimeout,
 		MaxWaitTimeout:   p.MaxWaitTimeout,
@@ -413,6 +424,7 @@ func (n *Node) JoinChain(
 		ChildrenLimit:    p.ChildrenLimit,
 		NephewsLimit:     p.NephewsLimit,
 		ValidateTxOnSend: p.ValidateTxOnSend,
+		VoteTSSkew:       p.VoteTSSkew,
 	}
 
 	if err := cfg.Save(); err != nil {
@@ -697,6 +709,18 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 			} else {
 				c.cfg.MaxBlockTxBytes = intVal
 			}
+		case "maxTxPerBlock":
+			if intVal, err := strconv.Atoi(value); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.MaxTxPerBlock = intVal
+			}
+		case "maxBlockSteps":
+			if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.MaxBlockSteps = intVal
+			}
 		case "nodeCache":
 			if !chain.IsNodeCacheOption(value) {
 				return errors.Errorf("InvalidNodeCacheOption(%s)", value)
@@ -770,6 +794,12 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 			} else {
 				c.cfg.ValidateTxOnSend = bc
 			}
+		case "voteTimestampSkew":
+			if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.VoteTSSkew = intVal
+			}
 		default:
 			return errors.Errorf("not found key %s", key)
 		}
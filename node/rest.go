@@ -92,6 +92,8 @@ type ChainConfig struct {
 	NormalTxPoolSize int    `json:"normalTxPool,omitempty"`
 	PatchTxPoolSize  int    `json:"patchTxPool,omitempty"`
 	MaxBlockTxBytes  int    `json:"maxBlockTxBytes,omitempty"`
+	MaxTxPerBlock    int    `json:"maxTxPerBlock,omitempty"`
+	MaxBlockSteps    int64  `json:"maxBlockSteps,omitempty"`
 	NodeCache        string `json:"nodeCache,omitempty"`
 	Channel          string `json:"channel"`
 	SecureSuites     string `json:"secureSuites"`
@@ -103,6 +105,7 @@ type ChainConfig struct {
 	ChildrenLimit    *int   `json:"childrenLimit,omitempty"`
 	NephewsLimit     *int   `json:"nephewsLimit,omitempty"`
 	ValidateTxOnSend bool   `json:"validateTxOnSend,omitempty"`
+	VoteTSSkew       int64  `json:"voteTimestampSkew,omitempty"`
 }
 
 type ChainResetParam struct {
@@ -174,6 +177,8 @@ func NewChainConfig(cfg *chain.Config) *ChainConfig {
 		NormalTxPoolSize: cfg.NormalTxPoolSize,
 		PatchTxPoolSize:  cfg.PatchTxPoolSize,
 		MaxBlockTxBytes:  cfg.MaxBlockTxBytes,
+		MaxTxPerBlock:    cfg.MaxTxPerBlock,
+		MaxBlockSteps:    cfg.MaxBlockSteps,
 		NodeCache:        cfg.NodeCache,
 		Channel:          cfg.Channel,
 		SecureSuites:     cfg.SecureSuites,
@@ -185,6 +190,7 @@ func NewChainConfig(cfg *chain.Config) *ChainConfig {
 		ChildrenLimit:    cfg.ChildrenLimit,
 		NephewsLimit:     cfg.NephewsLimit,
 		ValidateTxOnSend: cfg.ValidateTxOnSend,
+		VoteTSSkew:       cfg.VoteTSSkew,
 	}
 	return v
 }
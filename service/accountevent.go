@@ -0,0 +1,142 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// AccountEvent is delivered to subscribers when the state or the stake of
+// the account is changed by the finalized transition.
+type AccountEvent struct {
+	Address module.Address
+	Height  int64
+	Balance *big.Int
+
+	// Stake is nil if the platform doesn't keep stake of accounts.
+	Stake *big.Int
+}
+
+type accountEventHub struct {
+	lock    sync.Mutex
+	subs    map[string][]chan<- AccountEvent
+	dropped int64
+}
+
+func (h *accountEventHub) subscribe(addr module.Address, ch chan<- AccountEvent) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.subs == nil {
+		h.subs = make(map[string][]chan<- AccountEvent)
+	}
+	key := string(addr.Bytes())
+	h.subs[key] = append(h.subs[key], ch)
+}
+
+func (h *accountEventHub) unsubscribe(addr module.Address, ch chan<- AccountEvent) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	key := string(addr.Bytes())
+	chs := h.subs[key]
+	for i, c := range chs {
+		if c == ch {
+			if len(chs) > 1 {
+				nchs := make([]chan<- AccountEvent, len(chs)-1)
+				copy(nchs, chs[:i])
+				copy(nchs[i:], chs[i+1:])
+				h.subs[key] = nchs
+			} else {
+				delete(h.subs, key)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// notify sends events for subscribed accounts changed from prev to cur.
+// It never blocks. Events for full channels are dropped and counted.
+func (h *accountEventHub) notify(height int64, prev, cur state.WorldSnapshot) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for key, chs := range h.subs {
+		addr, err := common.NewAddress([]byte(key))
+		if err != nil {
+			continue
+		}
+		as := cur.GetAccountSnapshot(addr.ID())
+		var pas state.AccountSnapshot
+		if prev != nil {
+			pas = prev.GetAccountSnapshot(addr.ID())
+		}
+		stake := stakeOf(cur, addr)
+		var pstake *big.Int
+		if prev != nil {
+			pstake = stakeOf(prev, addr)
+		}
+		if (as == nil && pas == nil || as != nil && pas != nil && as.Equal(pas)) &&
+			equalStake(stake, pstake) {
+			continue
+		}
+		balance := new(big.Int)
+		if as != nil {
+			balance = as.GetBalance()
+		}
+		ev := AccountEvent{
+			Address: addr,
+			Height:  height,
+			Balance: balance,
+			Stake:   stake,
+		}
+		for _, ch := range chs {
+			select {
+			case ch <- ev:
+			default:
+				h.dropped += 1
+			}
+		}
+	}
+}
+
+func stakeOf(wss state.WorldSnapshot, addr module.Address) *big.Int {
+	if ss, ok := wss.GetExtensionSnapshot().(state.StakeSnapshot); ok {
+		return ss.GetStake(addr)
+	}
+	return nil
+}
+
+func equalStake(s1, s2 *big.Int) bool {
+	if s1 == nil || s2 == nil {
+		return s1 == s2
+	}
+	return s1.Cmp(s2) == 0
+}
+
+func (h *accountEventHub) droppedEvents() int64 {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return h.dropped
+}
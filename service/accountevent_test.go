@@ -0,0 +1,120 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+type stakeSnapshot struct {
+	state.ExtensionSnapshot
+	stakes map[string]int64
+}
+
+func (s *stakeSnapshot) GetStake(addr module.Address) *big.Int {
+	return big.NewInt(s.stakes[string(addr.Bytes())])
+}
+
+func TestAccountEventHub(t *testing.T) {
+	dbase := db.NewMapDB()
+	addr1 := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	addr2 := common.MustNewAddressFromString("hx2222222222222222222222222222222222222222")
+
+	ws := state.NewWorldState(dbase, nil, nil, nil, nil)
+	ss0 := ws.GetSnapshot()
+	ws.GetAccountState(addr1.ID()).SetBalance(big.NewInt(100))
+	ss1 := ws.GetSnapshot()
+	ws.GetAccountState(addr2.ID()).SetBalance(big.NewInt(200))
+	ss2 := ws.GetSnapshot()
+
+	var hub accountEventHub
+	ch := make(chan AccountEvent, 1)
+	hub.subscribe(addr1, ch)
+
+	// account changed
+	hub.notify(1, ss0, ss1)
+	if assert.Len(t, ch, 1) {
+		ev := <-ch
+		assert.True(t, addr1.Equal(ev.Address))
+		assert.EqualValues(t, 1, ev.Height)
+		assert.Equal(t, 0, big.NewInt(100).Cmp(ev.Balance))
+		assert.Nil(t, ev.Stake)
+	}
+
+	// account not changed
+	hub.notify(2, ss1, ss2)
+	assert.Len(t, ch, 0)
+
+	// full channel doesn't block
+	hub.notify(1, ss0, ss1)
+	hub.notify(1, ss0, ss2)
+	assert.Len(t, ch, 1)
+	assert.EqualValues(t, 1, hub.droppedEvents())
+	<-ch
+
+	// removed subscription
+	assert.True(t, hub.unsubscribe(addr1, ch))
+	assert.False(t, hub.unsubscribe(addr1, ch))
+	hub.notify(1, ss0, ss1)
+	assert.Len(t, ch, 0)
+}
+
+func TestAccountEventHub_Stake(t *testing.T) {
+	dbase := db.NewMapDB()
+	addr1 := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	addr2 := common.MustNewAddressFromString("hx2222222222222222222222222222222222222222")
+
+	ws := state.NewWorldState(dbase, nil, nil, nil, nil)
+	ws.GetAccountState(addr1.ID()).SetBalance(big.NewInt(100))
+	ss := ws.GetSnapshot()
+	assert.NoError(t, ss.Flush())
+	snapshotWithStakes := func(stakes map[string]int64) state.WorldSnapshot {
+		return state.NewWorldSnapshot(dbase, ss.StateHash(), nil, &stakeSnapshot{stakes: stakes}, nil)
+	}
+	ss0 := snapshotWithStakes(nil)
+	ss1 := snapshotWithStakes(map[string]int64{string(addr1.Bytes()): 50})
+	ss2 := snapshotWithStakes(map[string]int64{
+		string(addr1.Bytes()): 50,
+		string(addr2.Bytes()): 70,
+	})
+
+	var hub accountEventHub
+	ch := make(chan AccountEvent, 1)
+	hub.subscribe(addr1, ch)
+
+	// stake changed without changing balance
+	hub.notify(1, ss0, ss1)
+	if assert.Len(t, ch, 1) {
+		ev := <-ch
+		assert.True(t, addr1.Equal(ev.Address))
+		assert.EqualValues(t, 1, ev.Height)
+		assert.Equal(t, 0, big.NewInt(50).Cmp(ev.Stake))
+		assert.Equal(t, 0, big.NewInt(100).Cmp(ev.Balance))
+	}
+
+	// stake of the other account changed
+	hub.notify(2, ss1, ss2)
+	assert.Len(t, ch, 0)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/eeproxy"
@@ -34,6 +35,13 @@ type Context interface {
 	SetProperty(name string, value interface{})
 	GetEnabledEETypes() state.EETypes
 	EEPriority() eeproxy.RequestPriority
+	GetWorldSnapshotByHeight(height int64) (state.WorldSnapshot, error)
+}
+
+// WorldSnapshotProvider is implemented by the service manager to build the
+// world snapshot from the result of the block.
+type WorldSnapshotProvider interface {
+	GetWorldSnapshot(result []byte) (state.WorldSnapshot, error)
 }
 
 type context struct {
@@ -145,6 +153,29 @@ func (c *context) ChainID() int {
 	return c.chain.CID()
 }
 
+// GetWorldSnapshotByHeight returns the world snapshot built from the result
+// of the finalized block at the height. It returns NotFoundError for the
+// height pruned from the database.
+func (c *context) GetWorldSnapshotByHeight(height int64) (state.WorldSnapshot, error) {
+	if height < 0 {
+		return nil, errors.IllegalArgumentError.Errorf("NegativeHeight(height=%d)", height)
+	}
+	if base := c.chain.GenesisStorage().Height(); height < base {
+		return nil, errors.NotFoundError.Errorf(
+			"PrunedBlock(height=%d,base=%d)", height, base)
+	}
+	bm := c.chain.BlockManager()
+	wsp, ok := c.chain.ServiceManager().(WorldSnapshotProvider)
+	if bm == nil || !ok {
+		return nil, errors.UnsupportedError.New("WorldSnapshotNotAvailable")
+	}
+	blk, err := bm.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	return wsp.GetWorldSnapshot(blk.Result())
+}
+
 func (c *context) TransactionTimeout() time.Duration {
 	return c.chain.TransactionTimeout()
 }
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+package contract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+type historyWorldSnapshot struct {
+	state.WorldSnapshot
+	height int64
+}
+
+type historyBlock struct {
+	module.Block
+	height int64
+}
+
+func (b *historyBlock) Result() []byte {
+	return intconv.Int64ToBytes(b.height)
+}
+
+type historyBlockManager struct {
+	module.BlockManager
+	blocks map[int64]*historyBlock
+}
+
+func (bm *historyBlockManager) GetBlockByHeight(height int64) (module.Block, error) {
+	if blk, ok := bm.blocks[height]; ok {
+		return blk, nil
+	}
+	return nil, errors.NotFoundError.Errorf("NoBlock(height=%d)", height)
+}
+
+type historyServiceManager struct {
+	module.ServiceManager
+}
+
+func (sm *historyServiceManager) GetWorldSnapshot(result []byte) (state.WorldSnapshot, error) {
+	return &historyWorldSnapshot{height: intconv.BytesToInt64(result)}, nil
+}
+
+type historyGenesisStorage struct {
+	module.GenesisStorage
+	height int64
+}
+
+func (gs *historyGenesisStorage) Height() int64 {
+	return gs.height
+}
+
+type historyChain struct {
+	module.Chain
+	gs module.GenesisStorage
+	bm module.BlockManager
+	sm module.ServiceManager
+}
+
+func (c *historyChain) GenesisStorage() module.GenesisStorage {
+	return c.gs
+}
+
+func (c *historyChain) BlockManager() module.BlockManager {
+	return c.bm
+}
+
+func (c *historyChain) ServiceManager() module.ServiceManager {
+	return c.sm
+}
+
+func TestContext_GetWorldSnapshotByHeight(t *testing.T) {
+	bm := &historyBlockManager{blocks: make(map[int64]*historyBlock)}
+	for _, height := range []int64{10, 20} {
+		bm.blocks[height] = &historyBlock{height: height}
+	}
+	chain := &historyChain{
+		gs: &historyGenesisStorage{height: 10},
+		bm: bm,
+		sm: &historyServiceManager{},
+	}
+	ctx := NewContext(nil, nil, nil, chain, nil, nil, 0)
+
+	for _, height := range []int64{10, 20} {
+		wss, err := ctx.GetWorldSnapshotByHeight(height)
+		assert.NoError(t, err)
+		assert.Equal(t, height, wss.(*historyWorldSnapshot).height)
+	}
+
+	// pruned
+	_, err := ctx.GetWorldSnapshotByHeight(5)
+	assert.True(t, errors.NotFoundError.Equals(err))
+
+	// not yet finalized
+	_, err = ctx.GetWorldSnapshotByHeight(30)
+	assert.True(t, errors.NotFoundError.Equals(err))
+
+	_, err = ctx.GetWorldSnapshotByHeight(-1)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	// service manager without world snapshots
+	chain.sm = nil
+	_, err = ctx.GetWorldSnapshotByHeight(10)
+	assert.True(t, errors.UnsupportedError.Equals(err))
+}
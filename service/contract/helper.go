@@ -35,6 +35,7 @@ const (
 
 	EventMaxStepLimitSet = "MaxStepLimitSet(str,int)"
 	EventTimestampThresholdSet = "TimestampThresholdSet(int)"
+	EventStepRefundCapSet      = "StepRefundCapSet(int)"
 )
 
 func GetRevision(cc CallContext) int {
@@ -259,3 +260,39 @@ func SetTimestampThreshold(cc CallContext, value int64) (bool, error) {
 	}
 	return true, nil
 }
+
+// GetStepRefundCap returns the maximum refund of steps in percentage of
+// the steps used by a transaction. Zero means that only the total is capped.
+func GetStepRefundCap(cc CallContext) int64 {
+	as := cc.GetAccountState(state.SystemID)
+	db := scoredb.NewVarDB(as, state.VarStepRefundCap)
+	return db.Int64()
+}
+
+func SetStepRefundCap(cc CallContext, value int64) (bool, error) {
+	if value < 0 || value > 100 {
+		return false, scoreresult.InvalidParameterError.Errorf("InvalidStepRefundCap(value=%d)", value)
+	}
+	as := cc.GetAccountState(state.SystemID)
+	db := scoredb.NewVarDB(as, state.VarStepRefundCap)
+	if old := db.Int64(); old == value {
+		return false, nil
+	}
+	if value == 0 {
+		if _, err := db.Delete(); err != nil {
+			return false, err
+		}
+	} else {
+		if err := db.Set(value); err != nil {
+			return false, err
+		}
+	}
+	if cc.Revision().Has(module.ReportConfigureEvents) {
+		cc.OnEvent(
+			state.SystemAddress,
+			[][]byte{[]byte(EventStepRefundCapSet)},
+			[][]byte{intconv.Int64ToBytes(value)},
+		)
+	}
+	return true, nil
+}
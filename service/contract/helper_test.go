@@ -446,4 +446,45 @@ func TestTimestampThreshold(t *testing.T) {
 		nil, []any{intconv.BigIntZero},
 	))
 	cc.events = nil
-}
\ No newline at end of file
+}
+
+func TestStepRefundCap(t *testing.T) {
+	cc := newFakeCallContext()
+	cc.revision |= module.ReportConfigureEvents
+
+	// initial is zero
+	assert.EqualValues(t, 0, GetStepRefundCap(cc))
+
+	ok, err := SetStepRefundCap(cc, 20)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 20, GetStepRefundCap(cc))
+
+	assert.Equal(t, 1, len(cc.events))
+	assert.NoError(t, cc.events[0].Assert(
+		state.SystemAddress,
+		EventStepRefundCapSet,
+		nil, []any{int64(20)},
+	))
+	cc.events = nil
+
+	// set as same
+	ok, err = SetStepRefundCap(cc, 20)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 0, len(cc.events))
+
+	// out of range
+	for _, v := range []int64{-1, 101} {
+		ok, err = SetStepRefundCap(cc, v)
+		assert.Error(t, err)
+		assert.False(t, ok)
+	}
+	assert.Equal(t, 0, len(cc.events))
+
+	// set as zero (delete)
+	ok, err = SetStepRefundCap(cc, 0)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 0, GetStepRefundCap(cc))
+}
@@ -135,12 +135,20 @@ func CheckMethod(obj SystemScore, methodInfo *scoreapi.Info) error {
 		}
 		// CHECK INPUT
 		numIn := m.Type.NumIn()
-		if len(methodInfo.Inputs) != numIn-1 {
+		if len(methodInfo.Inputs) > numIn-1 {
 			return scoreresult.IllegalFormatError.Errorf(
 				"Wrong method input. method[%s]", mName)
 		}
 		for j := 1; j < numIn; j++ {
 			t := m.Type.In(j)
+			if j > len(methodInfo.Inputs) {
+				// parameters added in later revisions are passed as nil
+				if !isNillableType(t) {
+					return scoreresult.IllegalFormatError.Errorf(
+						"Wrong method input. method[%s]", mName)
+				}
+				continue
+			}
 			mt := methodInfo.Inputs[j-1].Type
 			mf := methodInfo.Inputs[j-1].Fields
 			if err := CheckType(t, mt, mf); err != nil {
@@ -200,6 +208,16 @@ func CheckMethod(obj SystemScore, methodInfo *scoreapi.Info) error {
 	return nil
 }
 
+// isNillableType returns whether the type can be nil.
+func isNillableType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return true
+	default:
+		return false
+	}
+}
+
 var (
 	ptrOfHexIntType  = reflect.TypeOf((*common.HexInt)(nil))
 	ptrOfBigIntType  = reflect.TypeOf((*big.Int)(nil))
@@ -448,13 +466,23 @@ func Invoke(score SystemScore, method string, paramObj *codec.TypedObj) (status
 		params = ps.([]interface{})
 	}
 
-	if len(params) != mType.NumIn() {
+	if len(params) > mType.NumIn() {
 		return scoreresult.IllegalFormatError.Errorf(
 			"NotEnoughParameter(exp=%d,real=%d)",
 			mType.NumIn(), len(params)), nil, steps
 	}
 
-	objects := make([]reflect.Value, len(params))
+	// parameters missing in the API of the revision are passed as nil.
+	objects := make([]reflect.Value, mType.NumIn())
+	for i := len(params); i < len(objects); i++ {
+		oType := mType.In(i)
+		if !isNillableType(oType) {
+			return scoreresult.IllegalFormatError.Errorf(
+				"NotEnoughParameter(exp=%d,real=%d)",
+				mType.NumIn(), len(params)), nil, steps
+		}
+		objects[i] = reflect.Zero(oType)
+	}
 	for i, p := range params {
 		oType := mType.In(i)
 		oValue := reflect.New(oType).Elem()
@@ -21,7 +21,11 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/service/scoreapi"
 )
 
 func newHexInt(s string, base int) *common.HexInt {
@@ -65,3 +69,78 @@ func TestAssignHexInt(t *testing.T) {
 		})
 	}
 }
+
+type testSystemScore struct{}
+
+func (s *testSystemScore) Install(param []byte) error {
+	return nil
+}
+
+func (s *testSystemScore) Update(param []byte) error {
+	return nil
+}
+
+func (s *testSystemScore) GetAPI() *scoreapi.Info {
+	return nil
+}
+
+func (s *testSystemScore) Ex_getValue(value *common.HexInt, scale *common.HexInt) (int64, error) {
+	if scale == nil {
+		return value.Int64(), nil
+	}
+	return value.Int64() * scale.Int64(), nil
+}
+
+func (s *testSystemScore) Ex_getIntValue(value *common.HexInt, scale int64) (int64, error) {
+	return value.Int64() * scale, nil
+}
+
+func newTestMethod(name string, params ...string) *scoreapi.Method {
+	inputs := make([]scoreapi.Parameter, len(params))
+	for i, p := range params {
+		inputs[i] = scoreapi.Parameter{Name: p, Type: scoreapi.Integer}
+	}
+	return &scoreapi.Method{
+		Type:    scoreapi.Function,
+		Name:    name,
+		Flags:   scoreapi.FlagReadOnly | scoreapi.FlagExternal,
+		Indexed: 1,
+		Inputs:  inputs,
+		Outputs: []scoreapi.DataType{scoreapi.Integer},
+	}
+}
+
+func decodeForTest(t *testing.T, obj *codec.TypedObj) interface{} {
+	value, err := common.DecodeAnyForJSON(obj)
+	assert.NoError(t, err)
+	return value
+}
+
+func TestSystemScore_ParameterOfLaterRevision(t *testing.T) {
+	score := new(testSystemScore)
+
+	// parameters missing in the API are passed as nil
+	m := newTestMethod("getValue", "value")
+	assert.NoError(t, CheckMethod(score, scoreapi.NewInfo([]*scoreapi.Method{m})))
+	params, err := m.ConvertParamsToTypedObj([]byte(`{"value":"0x3"}`), false)
+	assert.NoError(t, err)
+	status, result, _ := Invoke(score, "getValue", params)
+	assert.NoError(t, status)
+	assert.Equal(t, common.NewHexInt(3), decodeForTest(t, result))
+
+	m = newTestMethod("getValue", "value", "scale")
+	assert.NoError(t, CheckMethod(score, scoreapi.NewInfo([]*scoreapi.Method{m})))
+	params, err = m.ConvertParamsToTypedObj([]byte(`{"value":"0x3","scale":"0x2"}`), false)
+	assert.NoError(t, err)
+	status, result, _ = Invoke(score, "getValue", params)
+	assert.NoError(t, status)
+	assert.Equal(t, common.NewHexInt(6), decodeForTest(t, result))
+
+	// parameters which can't be nil must be in the API
+	m = newTestMethod("getIntValue", "value")
+	assert.Error(t, CheckMethod(score, scoreapi.NewInfo([]*scoreapi.Method{m})))
+	params, err = m.ConvertParamsToTypedObj([]byte(`{"value":"0x3"}`), false)
+	assert.NoError(t, err)
+	status, _, _ = Invoke(score, "getIntValue", params)
+	assert.Error(t, status)
+}
@@ -162,6 +162,10 @@ func (wc *testWContext) BlockTimeStamp() int64 {
 	return wc.ts
 }
 
+func (wc *testWContext) TransactionTimestampThreshold() int64 {
+	return 0
+}
+
 func (wc *testWContext) GetAccountState(id []byte) state.AccountState {
 	if wc.accounts == nil {
 		wc.accounts = make(map[string]*testAccountState)
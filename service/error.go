@@ -15,6 +15,7 @@ const (
 	NotContractAddressError
 	InvalidPatchDataError
 	CommittedTransactionError
+	CanceledTransactionError
 )
 
 var (
@@ -24,4 +25,5 @@ var (
 	ErrTransitionInterrupted   = errors.NewBase(TransitionInterruptedError, "TransitionInterrupted")
 	ErrInvalidTransaction      = errors.NewBase(InvalidTransactionError, "InvalidTransaction")
 	ErrCommittedTransaction    = errors.NewBase(CommittedTransactionError, "CommittedTransaction")
+	ErrCanceledTransaction     = errors.NewBase(CanceledTransactionError, "CanceledTransaction")
 )
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -61,10 +62,74 @@ type manager struct {
 	log log.Logger
 
 	skipTxPatch atomic.Value
+
+	maxTxPerBlock   int
+	maxBlockTxBytes int
+	maxBlockSteps   int64
+	txTimeout       time.Duration
+
+	aeh accountEventHub
+}
+
+type ManagerOption func(m *manager)
+
+// WithMaxTxPerBlock limits the number of normal transactions included
+// in a proposed block. Zero means no additional limit.
+func WithMaxTxPerBlock(n int) ManagerOption {
+	return func(m *manager) {
+		m.maxTxPerBlock = n
+	}
+}
+
+// WithMaxBlockTxBytes limits the total size of normal transactions included
+// in a proposed block. Zero means no additional limit.
+func WithMaxBlockTxBytes(n int) ManagerOption {
+	return func(m *manager) {
+		m.maxBlockTxBytes = n
+	}
+}
+
+// WithMaxBlockSteps limits the sum of estimated steps, which is the step
+// limit of each transaction, of normal transactions included in a proposed
+// block. Zero means no limit.
+func WithMaxBlockSteps(n int64) ManagerOption {
+	return func(m *manager) {
+		m.maxBlockSteps = n
+	}
+}
+
+// WithTransactionTimeout limits the execution time of a transaction.
+// A transaction exceeding it fails with StatusTimeout while the others in
+// the block are executed as usual. Queries and traces keep the timeout of
+// the chain. Zero means the timeout of the chain.
+func WithTransactionTimeout(d time.Duration) ManagerOption {
+	return func(m *manager) {
+		m.txTimeout = d
+	}
+}
+
+// txTimeoutChain overrides the transaction timeout of the chain for
+// contract contexts executing transactions.
+type txTimeoutChain struct {
+	module.Chain
+	timeout time.Duration
+}
+
+func (c *txTimeoutChain) TransactionTimeout() time.Duration {
+	return c.timeout
+}
+
+// minLimit returns the smaller of two limits where zero or negative means no limit.
+func minLimit(v, limit int) int {
+	if limit > 0 && (v <= 0 || v > limit) {
+		return limit
+	}
+	return v
 }
 
 func NewManager(chain module.Chain, nm module.NetworkManager,
 	eem eeproxy.Manager, plt base.Platform, contractDir string,
+	opts ...ManagerOption,
 ) (module.ServiceManager, error) {
 	logger := chain.Logger().WithFields(log.Fields{
 		log.FieldKeyModule: "SV",
@@ -119,6 +184,9 @@ func NewManager(chain module.Chain, nm module.NetworkManager,
 		dsm: dsm,
 		lm:  lm,
 	}
+	for _, opt := range opts {
+		opt(mgr)
+	}
 	if nm != nil {
 		mgr.txReactor = NewTransactionReactor(nm, tm)
 	}
@@ -144,19 +212,26 @@ func (m *manager) Term() {
 	m.lm = nil
 }
 
+// normalTxCandidates returns normal transactions for a new block.
+// It stops including transactions once the transaction count, the total
+// bytes or the total estimated steps reaches its limit, and the rest remain
+// in the pool.
+func (m *manager) normalTxCandidates(wc state.WorldContext) []module.Transaction {
+	maxTxCount := minLimit(m.chain.Regulator().MaxTxCount(), m.maxTxPerBlock)
+	txSizeInBlock := minLimit(m.chain.MaxBlockTxBytes(), m.maxBlockTxBytes)
+	txs, _ := m.tm.Candidate(module.TransactionGroupNormal, wc, txSizeInBlock, maxTxCount, m.maxBlockSteps)
+	return txs
+}
+
 // ProposeTransition proposes a Transition following the parent Transition.
 // parent transition should have a valid result.
 // Returned Transition always passes validation.
 func (m *manager) ProposeTransition(parent module.Transition, bi module.BlockInfo, csi module.ConsensusInfo) (module.Transition, error) {
-	// check validity of transition
-	pt, err := m.checkTransitionResult(parent)
+	pt, wc, err := m.newProposalContext(parent, bi, csi)
 	if err != nil {
 		return nil, err
 	}
 
-	ws, _ := state.WorldStateFromSnapshot(pt.worldSnapshot)
-	wc := state.NewWorldContext(ws, bi, csi, m.plt)
-
 	baseTx, err := m.plt.NewBaseTransaction(wc)
 	if err != nil {
 		return nil, err
@@ -165,9 +240,7 @@ func (m *manager) ProposeTransition(parent module.Transition, bi module.BlockInf
 	if err != nil {
 		return nil, err
 	}
-	maxTxCount := m.chain.Regulator().MaxTxCount()
-	txSizeInBlock := m.chain.MaxBlockTxBytes()
-	normalTxs, _ := m.tm.Candidate(module.TransactionGroupNormal, wc, txSizeInBlock, maxTxCount)
+	normalTxs := m.normalTxCandidates(wc)
 
 	if baseTx != nil || len(dsrTxs) > 0 {
 		count := len(normalTxs)+len(dsrTxs)+1
@@ -193,12 +266,50 @@ func (m *manager) ProposeTransition(parent module.Transition, bi module.BlockInf
 		nil
 }
 
+// newProposalContext returns the parent transition and the world context
+// for the block of bi following it. parent transition should have a valid
+// result.
+func (m *manager) newProposalContext(parent module.Transition, bi module.BlockInfo, csi module.ConsensusInfo) (*transition, state.WorldContext, error) {
+	// check validity of transition
+	pt, err := m.checkTransitionResult(parent)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pt == nil {
+		return nil, nil, errors.ErrIllegalArgument
+	}
+
+	ws, err := state.WorldStateFromSnapshot(pt.worldSnapshot)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pt, state.NewWorldContext(ws, bi, csi, m.plt), nil
+}
+
+// ProposeBaseTransaction returns the base transaction which ProposeTransition
+// places before other transactions of the block of bi following the parent
+// Transition. It returns nil if the platform doesn't require it for the
+// block, for example, the block is not at a term boundary.
+// parent transition should have a valid result.
+func (m *manager) ProposeBaseTransaction(parent module.Transition, bi module.BlockInfo, csi module.ConsensusInfo) (module.Transaction, error) {
+	_, wc, err := m.newProposalContext(parent, bi, csi)
+	if err != nil {
+		return nil, err
+	}
+	return m.plt.NewBaseTransaction(wc)
+}
+
 // CreateInitialTransition creates an initial Transition with result and
 // vs validators.
 func (m *manager) CreateInitialTransition(result []byte,
 	valList module.ValidatorList,
 ) (module.Transition, error) {
-	return newInitTransition(m.db, result, valList, m.cm, m.eem, m.chain, m.log, m.plt, m.tsc, m.tim, m.dsm)
+	tr, err := newInitTransition(m.db, result, valList, m.cm, m.eem, m.chain, m.log, m.plt, m.tsc, m.tim, m.dsm)
+	if err != nil {
+		return nil, err
+	}
+	tr.txTimeout = m.txTimeout
+	return tr, nil
 }
 
 // CreateTransition creates a Transition following parent Transition with txs
@@ -219,6 +330,64 @@ func (m *manager) CreateTransition(
 	return newTransition(pt, nil, txs, bi, csi, validated), nil
 }
 
+type importCallback chan error
+
+func (cb importCallback) OnValidate(tr module.Transition, err error) {
+	cb <- err
+}
+
+func (cb importCallback) OnExecute(tr module.Transition, err error) {
+	cb <- err
+}
+
+// ValidateImportedBlock executes transactions of blk following parent
+// Transition, then checks that the result matches the header of nblk, the
+// next block of blk, which has the result and the next validators of blk.
+// csi is the consensus information for blk. It returns the executed
+// Transition, which can be used as the parent for validating nblk.
+func (m *manager) ValidateImportedBlock(
+	parent module.Transition,
+	blk module.BlockData,
+	csi module.ConsensusInfo,
+	nblk module.BlockData,
+) (module.Transition, error) {
+	if blk.Height()+1 != nblk.Height() {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidNextBlock(height=%d,next=%d)", blk.Height(), nblk.Height())
+	}
+	tr, err := m.CreateTransition(parent, blk.NormalTransactions(), blk, csi, true)
+	if err != nil {
+		return nil, err
+	}
+	if ptxs := nblk.PatchTransactions(); len(ptxs.Hash()) > 0 {
+		tr = PatchTransition(tr, ptxs, nblk, true)
+	}
+
+	cb := make(chan error, 2)
+	if _, err := tr.Execute(importCallback(cb)); err != nil {
+		return nil, err
+	}
+	// wait for OnValidate and OnExecute
+	for i := 0; i < 2; i++ {
+		if err := <-cb; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := compareTransitionResult(nblk.Result(), tr.Result()); err != nil {
+		return nil, err
+	}
+	var nvh []byte
+	if nvl := tr.NextValidators(); nvl != nil {
+		nvh = nvl.Hash()
+	}
+	if !bytes.Equal(nblk.NextValidatorsHash(), nvh) {
+		return nil, InvalidResultError.Errorf("NextValidatorsHashMismatch(exp=%#x,real=%#x)",
+			nblk.NextValidatorsHash(), nvh)
+	}
+	return tr, nil
+}
+
 func (m *manager) SendPatch(data module.Patch) error {
 	if data.Type() == module.PatchTypeSkipTransaction {
 		patch, ok := data.(module.SkipTransactionPatch)
@@ -255,7 +424,7 @@ func (m *manager) GetPatches(parent module.Transition, bi module.BlockInfo) modu
 
 	wc := state.NewWorldContext(ws, bi, nil, m.plt)
 
-	txs, size := m.tm.Candidate(module.TransactionGroupPatch, wc, m.chain.MaxBlockTxBytes(), 0)
+	txs, size := m.tm.Candidate(module.TransactionGroupPatch, wc, m.chain.MaxBlockTxBytes(), 0, 0)
 
 	p, _ := m.skipTxPatch.Load().(module.SkipTransactionPatch)
 	if p != nil {
@@ -317,9 +486,13 @@ func (m *manager) Finalize(t module.Transition, opt int) error {
 		}
 		if opt&module.FinalizeResult == module.FinalizeResult {
 			keepParent := (opt & module.KeepingParent) != 0
+			parent := tst.parent
 			if err := tst.finalizeResult(false, keepParent); err != nil {
 				return err
 			}
+			if parent != nil {
+				m.aeh.notify(tst.bi.Height(), parent.worldSnapshot, tst.worldSnapshot)
+			}
 			m.tm.NotifyFinalized(tst.patchTransactions, tst.patchReceipts, tst.normalTransactions, tst.normalReceipts)
 			now := time.Now()
 			m.patchMetric.OnFinalize(tst.patchTransactions.Hash(), now)
@@ -441,6 +614,32 @@ func (m *manager) SendTransactionAndWait(result []byte, height int64, txi interf
 	return nil, nil, err
 }
 
+// CancelTransaction removes a pending transaction from the pool so that it
+// would not be included in following proposals. It fails if the transaction
+// is already included in a block.
+func (m *manager) CancelTransaction(id []byte) error {
+	return m.tm.Cancel(id)
+}
+
+// Subscribe registers ch to receive AccountEvent whenever the state or
+// the stake of the account is changed by a finalized transition. Sending to
+// ch never blocks finalization. Events are dropped if ch is full.
+func (m *manager) Subscribe(addr module.Address, ch chan<- AccountEvent) {
+	m.aeh.subscribe(addr, ch)
+}
+
+// Unsubscribe removes the subscription registered by Subscribe.
+// It returns false if there is no such subscription.
+func (m *manager) Unsubscribe(addr module.Address, ch chan<- AccountEvent) bool {
+	return m.aeh.unsubscribe(addr, ch)
+}
+
+// DroppedAccountEvents returns the number of account events dropped
+// because of full channels.
+func (m *manager) DroppedAccountEvents() int64 {
+	return m.aeh.droppedEvents()
+}
+
 func (m *manager) WaitTransactionResult(id []byte) (<-chan interface{}, error) {
 	return m.tm.WaitResult(id)
 }
@@ -536,6 +735,11 @@ func (m *manager) getSystemByteStoreState(result []byte) (containerdb.BytesStore
 	return scoredb.NewStateStoreWith(ass), nil
 }
 
+// GetWorldSnapshot returns the world snapshot of the result.
+func (m *manager) GetWorldSnapshot(result []byte) (state.WorldSnapshot, error) {
+	return m.trc.GetWorldSnapshot(result, nil)
+}
+
 func (m *manager) GetBalance(result []byte, addr module.Address) (*big.Int, error) {
 	wss, err := m.trc.GetWorldSnapshot(result, nil)
 	if err != nil {
@@ -0,0 +1,370 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/chain/base"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/txlocator"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+type mockRegulator struct {
+	module.Regulator
+	maxTxCount int
+}
+
+func (r *mockRegulator) MaxTxCount() int {
+	return r.maxTxCount
+}
+
+type mockChain struct {
+	module.Chain
+	maxBlockTxBytes int
+	regulator       mockRegulator
+}
+
+func (c *mockChain) MaxBlockTxBytes() int {
+	return c.maxBlockTxBytes
+}
+
+func (c *mockChain) Regulator() module.Regulator {
+	return &c.regulator
+}
+
+func TestManager_normalTxCandidates(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	logger := log.New()
+	lm, err := txlocator.NewManager(dbase, logger)
+	assert.NoError(t, err)
+	tim, _ := NewTXIDManager(lm, tsc, nil)
+	ptp := NewTransactionPool(module.TransactionGroupPatch, 10, tim, &mockMonitor{}, logger)
+	ntp := NewTransactionPool(module.TransactionGroupNormal, 5000, tim, &mockMonitor{}, logger)
+	tm := NewTransactionManager(1, tsc, ptp, ntp, tim, logger)
+
+	ts := time.Now().UnixMicro()
+	addr := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	var txs []*mockTransaction
+	for i := 0; i < 5; i++ {
+		tx := newMockTransaction(crypto.SHA3Sum256([]byte{byte(i)}), addr, ts+int64(i))
+		assert.NoError(t, ntp.Add(tx, true))
+		txs = append(txs, tx)
+	}
+	txSize := len(txs[0].Bytes())
+	wc := &testWContext{ts: ts}
+
+	cases := []struct {
+		name  string
+		chain mockChain
+		opts  []ManagerOption
+		count int
+	}{
+		{"NoLimit", mockChain{}, nil, 5},
+		{"MaxTxPerBlock", mockChain{}, []ManagerOption{WithMaxTxPerBlock(2)}, 2},
+		{"MaxBlockTxBytes", mockChain{}, []ManagerOption{WithMaxBlockTxBytes(txSize * 3)}, 3},
+		{"Both", mockChain{}, []ManagerOption{WithMaxTxPerBlock(4), WithMaxBlockTxBytes(txSize * 3)}, 3},
+		{"RegulatorFirst", mockChain{regulator: mockRegulator{maxTxCount: 1}}, []ManagerOption{WithMaxTxPerBlock(4)}, 1},
+		{"ChainBytesFirst", mockChain{maxBlockTxBytes: txSize * 2}, []ManagerOption{WithMaxBlockTxBytes(txSize * 4)}, 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &manager{tm: tm, chain: &c.chain}
+			for _, opt := range c.opts {
+				opt(m)
+			}
+			cts := m.normalTxCandidates(wc)
+			assert.Len(t, cts, c.count)
+			for i, tx := range cts {
+				assert.Equal(t, txs[i].ID(), tx.ID())
+			}
+			// the rest remain pending
+			for _, tx := range txs {
+				assert.True(t, tm.HasTx(tx.ID()))
+			}
+		})
+	}
+}
+
+func TestManager_normalTxCandidatesWithMaxBlockSteps(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	logger := log.New()
+	lm, err := txlocator.NewManager(dbase, logger)
+	assert.NoError(t, err)
+	tim, _ := NewTXIDManager(lm, tsc, nil)
+	ptp := NewTransactionPool(module.TransactionGroupPatch, 10, tim, &mockMonitor{}, logger)
+	ntp := NewTransactionPool(module.TransactionGroupNormal, 5000, tim, &mockMonitor{}, logger)
+	tm := NewTransactionManager(1, tsc, ptp, ntp, tim, logger)
+
+	ts := time.Now().UnixMicro()
+	addr := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	var txs []*mockTransaction
+	for i, step := range []int64{100, 200, 300, 400} {
+		tx := newMockTransaction(crypto.SHA3Sum256([]byte{byte(i)}), addr, ts+int64(i))
+		tx.step = step
+		assert.NoError(t, ntp.Add(tx, true))
+		txs = append(txs, tx)
+	}
+	wc := &testWContext{ts: ts}
+
+	cases := []struct {
+		name  string
+		opts  []ManagerOption
+		count int
+	}{
+		{"NoLimit", nil, 4},
+		{"Boundary", []ManagerOption{WithMaxBlockSteps(600)}, 3},
+		{"BelowBoundary", []ManagerOption{WithMaxBlockSteps(599)}, 2},
+		{"TooSmall", []ManagerOption{WithMaxBlockSteps(99)}, 0},
+		{"WithMaxTxPerBlock", []ManagerOption{WithMaxBlockSteps(1000), WithMaxTxPerBlock(2)}, 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &manager{tm: tm, chain: &mockChain{}}
+			for _, opt := range c.opts {
+				opt(m)
+			}
+			cts := m.normalTxCandidates(wc)
+			assert.Len(t, cts, c.count)
+			for i, tx := range cts {
+				assert.Equal(t, txs[i].ID(), tx.ID())
+			}
+			// the rest remain pending
+			for _, tx := range txs {
+				assert.True(t, tm.HasTx(tx.ID()))
+			}
+		})
+	}
+}
+
+type timeoutChain struct {
+	module.Chain
+}
+
+func (c *timeoutChain) TransactionTimeout() time.Duration {
+	return 5 * time.Second
+}
+
+func TestManager_WithTransactionTimeout(t *testing.T) {
+	dbase := db.NewMapDB()
+	logger := log.New()
+	tsc := NewTimestampChecker()
+	lm, err := txlocator.NewManager(dbase, logger)
+	assert.NoError(t, err)
+	tim, err := NewTXIDManager(lm, tsc, nil)
+	assert.NoError(t, err)
+	chain := &timeoutChain{}
+	for _, c := range []struct {
+		name    string
+		timeout time.Duration
+		exp     time.Duration
+	}{
+		{"ChainTimeout", 0, 5 * time.Second},
+		{"Override", time.Second, time.Second},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			m := &manager{
+				db:    dbase,
+				chain: chain,
+				log:   logger,
+				plt:   &importPlatform{},
+				tsc:   tsc,
+				tim:   tim,
+				dsm:   newDSRManager(logger),
+			}
+			WithTransactionTimeout(c.timeout)(m)
+			assert.Equal(t, 5*time.Second, m.chain.TransactionTimeout())
+
+			itr, err := m.CreateInitialTransition(nil, nil)
+			assert.NoError(t, err)
+			tr := itr.(*transition)
+			assert.Equal(t, c.exp, tr.newContractContext(nil).TransactionTimeout())
+
+			tr.ti = &module.TraceInfo{}
+			assert.Equal(t, 5*time.Second, tr.newContractContext(nil).TransactionTimeout())
+		})
+	}
+}
+
+type termPlatform struct {
+	base.Platform
+	termPeriod int64
+}
+
+func (p *termPlatform) ToRevision(value int) module.Revision {
+	return module.Revision(value)
+}
+
+func (p *termPlatform) NewBaseTransaction(wc state.WorldContext) (module.Transaction, error) {
+	if wc.BlockHeight()%p.termPeriod != 0 {
+		return nil, nil
+	}
+	id := crypto.SHA3Sum256(intconv.Int64ToBytes(wc.BlockHeight()))
+	return newMockTransaction(id, state.SystemAddress, wc.BlockTimeStamp()), nil
+}
+
+func TestManager_ProposeBaseTransaction(t *testing.T) {
+	dbase := db.NewMapDB()
+	m := &manager{plt: &termPlatform{termPeriod: 10}}
+	parentAt := func(height int64) module.Transition {
+		return &transition{
+			bi:            common.NewBlockInfo(height, 1000),
+			step:          stepComplete,
+			worldSnapshot: state.NewWorldSnapshot(dbase, nil, nil, nil, nil),
+		}
+	}
+
+	// next block is at a term boundary
+	tx, err := m.ProposeBaseTransaction(parentAt(19), common.NewBlockInfo(20, 2000), nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, tx)
+	assert.Equal(t, crypto.SHA3Sum256(intconv.Int64ToBytes(20)), tx.ID())
+	assert.EqualValues(t, 2000, tx.(*mockTransaction).timeStamp)
+
+	// next block is not at a term boundary
+	tx, err = m.ProposeBaseTransaction(parentAt(20), common.NewBlockInfo(21, 2000), nil)
+	assert.NoError(t, err)
+	assert.Nil(t, tx)
+
+	// parent without result
+	_, err = m.ProposeBaseTransaction(&transition{bi: common.NewBlockInfo(19, 1000)}, common.NewBlockInfo(20, 2000), nil)
+	assert.Error(t, err)
+}
+
+// importPlatform records the height of the block in the state on execution,
+// so that the result depends on the block.
+type importPlatform struct {
+	base.Platform
+}
+
+func (p *importPlatform) NewExtensionSnapshot(dbase db.Database, raw []byte) state.ExtensionSnapshot {
+	return nil
+}
+
+func (p *importPlatform) OnExtensionSnapshotFinalization(ess state.ExtensionSnapshot, logger log.Logger) {
+}
+
+func (p *importPlatform) ToRevision(value int) module.Revision {
+	return module.LatestRevision
+}
+
+func (p *importPlatform) OnValidateTransactions(wc state.WorldContext, patches, txs module.TransactionList) error {
+	return nil
+}
+
+func (p *importPlatform) OnExecutionBegin(wc state.WorldContext, logger log.Logger) error {
+	as := wc.GetAccountState(state.SystemID)
+	return scoredb.NewVarDB(as, "height").Set(wc.BlockHeight())
+}
+
+func (p *importPlatform) OnExecutionEnd(wc state.WorldContext, er base.ExecutionResult, logger log.Logger) error {
+	return nil
+}
+
+type importChain struct {
+	timeoutChain
+}
+
+func (c *importChain) ConcurrencyLevel() int {
+	return 1
+}
+
+type importBlock struct {
+	module.BlockData
+	height int64
+	ts     int64
+	nvh    []byte
+	result []byte
+}
+
+func (b *importBlock) Height() int64 {
+	return b.height
+}
+
+func (b *importBlock) Timestamp() int64 {
+	return b.ts
+}
+
+func (b *importBlock) NormalTransactions() module.TransactionList {
+	return nil
+}
+
+func (b *importBlock) PatchTransactions() module.TransactionList {
+	return transaction.NewTransactionListFromSlice(db.NewMapDB(), nil)
+}
+
+func (b *importBlock) NextValidatorsHash() []byte {
+	return b.nvh
+}
+
+func (b *importBlock) Result() []byte {
+	return b.result
+}
+
+func TestManager_ValidateImportedBlock(t *testing.T) {
+	dbase := db.NewMapDB()
+	logger := log.New()
+	tsc := NewTimestampChecker()
+	lm, err := txlocator.NewManager(dbase, logger)
+	assert.NoError(t, err)
+	tim, err := NewTXIDManager(lm, tsc, nil)
+	assert.NoError(t, err)
+	parent, err := newInitTransition(dbase, nil, nil, nil, nil, &importChain{},
+		logger, &importPlatform{}, tsc, tim, newDSRManager(logger))
+	assert.NoError(t, err)
+	m := &manager{}
+
+	execute := func(blk module.BlockData) module.Transition {
+		tr, err := m.CreateTransition(parent, blk.NormalTransactions(), blk, nil, true)
+		assert.NoError(t, err)
+		cb := make(chan error, 2)
+		_, err = tr.Execute(importCallback(cb))
+		assert.NoError(t, err)
+		assert.NoError(t, <-cb)
+		assert.NoError(t, <-cb)
+		return tr
+	}
+	blk := &importBlock{height: 1, ts: 1000}
+	exp := execute(blk)
+	other := execute(&importBlock{height: 2, ts: 1000})
+	assert.NotEqual(t, exp.Result(), other.Result())
+
+	nblk := &importBlock{
+		height: 2,
+		ts:     2000,
+		nvh:    exp.NextValidators().Hash(),
+		result: exp.Result(),
+	}
+	tr, err := m.ValidateImportedBlock(parent, blk, nil, nblk)
+	assert.NoError(t, err)
+	assert.Equal(t, exp.Result(), tr.Result())
+
+	// result of another block
+	nblk.result = other.Result()
+	_, err = m.ValidateImportedBlock(parent, blk, nil, nblk)
+	assert.True(t, InvalidResultError.Equals(err), "err=%+v", err)
+
+	// next validators mismatch
+	nblk.result = exp.Result()
+	nblk.nvh = crypto.SHA3Sum256([]byte("validators"))
+	_, err = m.ValidateImportedBlock(parent, blk, nil, nblk)
+	assert.True(t, InvalidResultError.Equals(err), "err=%+v", err)
+
+	// not the next block
+	nblk.nvh = exp.NextValidators().Hash()
+	nblk.height = 3
+	_, err = m.ValidateImportedBlock(parent, blk, nil, nblk)
+	assert.True(t, errors.IllegalArgumentError.Equals(err), "err=%+v", err)
+}
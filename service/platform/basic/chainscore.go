@@ -474,6 +474,30 @@ var chainMethods = []*chainMethod{
 			scoreapi.Integer,
 		},
 	}, Revision5, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "setStepRefundCap",
+		scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"cap", scoreapi.Integer, nil, nil},
+		},
+		nil,
+	}, Revision10, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getStepRefundCap",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
+		nil,
+		[]scoreapi.DataType{
+			scoreapi.Integer,
+		},
+	}, Revision10, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getNetworkInfo",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
+		nil,
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, Revision9, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "setMinimizeBlockGen",
 		scoreapi.FlagExternal, 1,
@@ -784,9 +808,9 @@ func (s *ChainScore) Install(param []byte) error {
 			}
 		}
 	}
-	validators := make([]module.Validator, len(chain.ValidatorList))
-	for i, validator := range chain.ValidatorList {
-		validators[i], _ = state.ValidatorFromAddress(validator)
+	validators, err := state.ValidatorsFromAddresses(chain.ValidatorList)
+	if err != nil {
+		return err
 	}
 	if err := s.cc.GetValidatorState().Set(validators); err != nil {
 		return errors.CriticalUnknownError.Wrap(err, "FailToSetValidators")
@@ -1415,6 +1439,21 @@ func (s *ChainScore) Ex_getRoundLimitFactor() (int64, error) {
 	return scoredb.NewVarDB(as, state.VarRoundLimitFactor).Int64(), nil
 }
 
+// Ex_getNetworkInfo returns network parameters related to block generation
+// and fees, so that clients can estimate polling intervals and timeouts.
+func (s *ChainScore) Ex_getNetworkInfo() (map[string]interface{}, error) {
+	if err := s.tryChargeCall(); err != nil {
+		return nil, err
+	}
+	as := s.cc.GetAccountState(state.SystemID)
+	return map[string]interface{}{
+		"blockInterval":    scoredb.NewVarDB(as, state.VarBlockInterval).Int64(),
+		"roundLimitFactor": scoredb.NewVarDB(as, state.VarRoundLimitFactor).Int64(),
+		"stepPrice":        contract.GetStepPrice(s.cc),
+		"revision":         int64(contract.GetRevision(s.cc)),
+	}, nil
+}
+
 func (s *ChainScore) Ex_setRoundLimitFactor(f *common.HexInt) error {
 	if err := s.checkGovernance(true); err != nil {
 		return err
@@ -1427,6 +1466,21 @@ func (s *ChainScore) Ex_setRoundLimitFactor(f *common.HexInt) error {
 	return factor.Set(f)
 }
 
+func (s *ChainScore) Ex_setStepRefundCap(c int64) error {
+	if err := s.checkGovernance(true); err != nil {
+		return err
+	}
+	_, err := contract.SetStepRefundCap(s.cc, c)
+	return err
+}
+
+func (s *ChainScore) Ex_getStepRefundCap() (int64, error) {
+	if err := s.tryChargeCall(); err != nil {
+		return 0, err
+	}
+	return contract.GetStepRefundCap(s.cc), nil
+}
+
 func (s *ChainScore) Ex_getMinimizeBlockGen() (bool, error) {
 	if err := s.tryChargeCall(); err != nil {
 		return false, err
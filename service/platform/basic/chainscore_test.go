@@ -19,12 +19,14 @@ package basic
 
 import (
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/scoredb"
 	"github.com/icon-project/goloop/service/state"
 )
 
@@ -105,4 +107,25 @@ func TestChainScore_GetAPI(t *testing.T) {
 			assert.NoError(t, err)
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestChainScore_GetNetworkInfo(t *testing.T) {
+	cc := newFakeCallContext()
+	score := &ChainScore{
+		cc:  cc,
+		gov: true,
+	}
+	as := cc.GetAccountState(state.SystemID)
+	assert.NoError(t, scoredb.NewVarDB(as, state.VarBlockInterval).Set(2000))
+	assert.NoError(t, scoredb.NewVarDB(as, state.VarRoundLimitFactor).Set(3))
+	assert.NoError(t, scoredb.NewVarDB(as, state.VarStepPrice).Set(big.NewInt(12_500_000_000)))
+	_, err := contract.SetRevision(cc, Revision9, false)
+	assert.NoError(t, err)
+
+	info, err := score.Ex_getNetworkInfo()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2000, info["blockInterval"])
+	assert.EqualValues(t, 3, info["roundLimitFactor"])
+	assert.Zero(t, big.NewInt(12_500_000_000).Cmp(info["stepPrice"].(*big.Int)))
+	assert.EqualValues(t, Revision9, info["revision"])
+}
@@ -16,12 +16,24 @@
 
 package state
 
+import (
+	"math/big"
+
+	"github.com/icon-project/goloop/module"
+)
+
 type ExtensionSnapshot interface {
 	Bytes() []byte
 	Flush() error
 	NewState(readonly bool) ExtensionState
 }
 
+// StakeSnapshot is implemented by ExtensionSnapshot of the platform keeping
+// stake of accounts.
+type StakeSnapshot interface {
+	GetStake(addr module.Address) *big.Int
+}
+
 type ExtensionState interface {
 	GetSnapshot() ExtensionSnapshot
 	Reset(snapshot ExtensionSnapshot)
@@ -96,6 +96,33 @@ func ValidatorFromAddress(a module.Address) (module.Validator, error) {
 	return v, nil
 }
 
+// ValidatorsFromAddresses returns validators for the addresses. It returns
+// an error on an invalid or duplicated address instead of leaving a nil
+// validator in the result.
+func ValidatorsFromAddresses(addrs []*common.Address) ([]module.Validator, error) {
+	validators := make([]module.Validator, len(addrs))
+	m := make(map[string]int)
+	for i, a := range addrs {
+		if a == nil {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"InvalidValidator(idx=%d,addr=nil)", i)
+		}
+		v, err := ValidatorFromAddress(a)
+		if err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err,
+				"InvalidValidator(idx=%d,addr=%v)", i, a)
+		}
+		key := string(a.Bytes())
+		if j, ok := m[key]; ok {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"DuplicateValidator(idx=%d,dup=%d,addr=%s)", i, j, a)
+		}
+		m[key] = i
+		validators[i] = v
+	}
+	return validators, nil
+}
+
 func ValidatorFromPublicKey(pk []byte) (module.Validator, error) {
 	v := new(validator)
 	if err := v.setPublicKey(pk); err != nil {
@@ -148,3 +148,31 @@ func TestValidatorSerializeWithAddr(t *testing.T) {
 		return
 	}
 }
+
+func TestValidatorsFromAddresses(t *testing.T) {
+	addr1 := common.MustNewAddressFromString("hx4567db98764567db98764567db98764567db9876")
+	addr2 := common.MustNewAddressFromString("hx1234db98764567db98764567db98764567db9876")
+	score := common.MustNewAddressFromString("cx4567db98764567db98764567db98764567db9876")
+
+	vs, err := ValidatorsFromAddresses([]*common.Address{addr1, addr2})
+	if err != nil {
+		t.Fatalf("Fail to make validators err=%+v", err)
+	}
+	if len(vs) != 2 || !vs[0].Address().Equal(addr1) || !vs[1].Address().Equal(addr2) {
+		t.Errorf("Invalid validators ret=%v", vs)
+	}
+
+	invalids := map[string][]*common.Address{
+		"Duplicate": {addr1, addr2, addr1},
+		"Contract":  {addr1, score},
+		"Nil":       {addr1, nil},
+	}
+	for name, addrs := range invalids {
+		t.Run(name, func(t *testing.T) {
+			vs, err := ValidatorsFromAddresses(addrs)
+			if err == nil {
+				t.Errorf("Invalid addresses are accepted ret=%v", vs)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package state
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/icon-project/goloop/common"
@@ -572,3 +573,49 @@ func ToValidatorList(list module.ValidatorList) (module.ValidatorList, error) {
 	}
 	return &validators{ list: vs, }, nil
 }
+
+func addressesOf(list module.ValidatorList) map[string]module.Address {
+	addrs := make(map[string]module.Address)
+	if list == nil {
+		return addrs
+	}
+	for i := 0; i < list.Len(); i++ {
+		if v, ok := list.Get(i); ok {
+			addr := v.Address()
+			addrs[string(addr.Bytes())] = addr
+		}
+	}
+	return addrs
+}
+
+func sortedAddresses(m map[string]module.Address) []module.Address {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	addrs := make([]module.Address, len(keys))
+	for i, k := range keys {
+		addrs[i] = m[k]
+	}
+	return addrs
+}
+
+// DiffValidators returns addresses of validators in new but not in old (added)
+// and ones in old but not in new (removed). Validators are compared by
+// address, so reordering only doesn't make any difference. Both results are
+// sorted by the bytes of the addresses.
+func DiffValidators(old, new module.ValidatorList) (added, removed []module.Address) {
+	oldAddrs := addressesOf(old)
+	newAddrs := addressesOf(new)
+	for k := range newAddrs {
+		if _, ok := oldAddrs[k]; ok {
+			delete(oldAddrs, k)
+			delete(newAddrs, k)
+		}
+	}
+	return sortedAddresses(newAddrs), sortedAddresses(oldAddrs)
+}
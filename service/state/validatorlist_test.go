@@ -608,4 +608,52 @@ func TestValidatorSnapshotWithBuilder(t *testing.T) {
 		assert.True(t, ok)
 		assert.EqualValues(t, v1.Bytes(), v2.Bytes())
 	}
-}
\ No newline at end of file
+}
+
+func TestDiffValidators(t *testing.T) {
+	dbase := db.NewMapDB()
+	listOf := func(vs []module.Validator) module.ValidatorList {
+		vss, err := ValidatorSnapshotFromSlice(dbase, vs)
+		assert.NoError(t, err)
+		return vss
+	}
+	addrsOf := func(ids ...int) []module.Address {
+		var addrs []module.Address
+		for _, id := range ids {
+			addrs = append(addrs, newDummyAddress(id))
+		}
+		return addrs
+	}
+	reversed := func(vs []module.Validator) []module.Validator {
+		rvs := make([]module.Validator, len(vs))
+		for i, v := range vs {
+			rvs[len(vs)-1-i] = v
+		}
+		return rvs
+	}
+
+	vl := listOf(newDummyValidators(4))
+	cases := []struct {
+		name    string
+		old     module.ValidatorList
+		new     module.ValidatorList
+		added   []module.Address
+		removed []module.Address
+	}{
+		{"Same", vl, listOf(newDummyValidators(4)), nil, nil},
+		{"Reordered", vl, listOf(reversed(newDummyValidators(4))), nil, nil},
+		{"Added", vl, listOf(newDummyValidators(6)), addrsOf(4, 5), nil},
+		{"AddedReversed", vl, listOf(reversed(newDummyValidators(6))), addrsOf(4, 5), nil},
+		{"Removed", vl, listOf(newDummyValidators(2)), nil, addrsOf(2, 3)},
+		{"Replaced", vl, listOf(reversed(newDummyValidatorsFrom(2, 4))), addrsOf(4, 5), addrsOf(0, 1)},
+		{"FromNil", nil, vl, addrsOf(0, 1, 2, 3), nil},
+		{"ToNil", vl, nil, nil, addrsOf(0, 1, 2, 3)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			added, removed := DiffValidators(c.old, c.new)
+			assert.Equal(t, c.added, added)
+			assert.Equal(t, c.removed, removed)
+		})
+	}
+}
@@ -40,6 +40,7 @@ const (
 	VarNextBlockVersion   = "next_block_version"
 	VarEnabledEETypes     = "enabled_ee_types"
 	VarSystemDepositUsage = "system_deposit_usage"
+	VarStepRefundCap      = "step_refund_cap"
 
 	VarDSRContextHistory = "dsr_context_history"
 )
@@ -108,6 +109,7 @@ type WorldContext interface {
 	DepositIssueRate() *big.Int
 	FeeLimit() *big.Int
 	DepositTerm() int64
+	StepRefundCap() int64
 	UpdateSystemInfo()
 
 	IsDeployer(addr string) bool
@@ -265,6 +267,14 @@ func (c *worldContext) DepositTerm() int64 {
 	return scoredb.NewVarDB(ss, VarDepositTerm).Int64()
 }
 
+// StepRefundCap returns the maximum refund of steps in percentage of the
+// steps used by the transaction. Zero means that the refund is limited only
+// by the steps used.
+func (c *worldContext) StepRefundCap() int64 {
+	ss := scoredb.NewStateStoreWith(c.systemInfo.ass)
+	return scoredb.NewVarDB(ss, VarStepRefundCap).Int64()
+}
+
 func (c *worldContext) ToRevision(value int) module.Revision {
 	return c.platform.ToRevision(value)
 }
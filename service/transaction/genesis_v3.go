@@ -18,6 +18,7 @@ import (
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/module"
 )
 
@@ -117,6 +118,65 @@ func calcHashOfGenesisMap(jso map[string]interface{}) ([]byte, error) {
 	return crypto.SHA3Sum256(bs), nil
 }
 
+func canonicalizeGenesisValue(o interface{}) (interface{}, error) {
+	switch obj := o.(type) {
+	case json.Number:
+		var r big.Rat
+		if _, ok := r.SetString(obj.String()); !ok {
+			return nil, errors.IllegalArgumentError.Errorf("InvalidNumber(%s)", obj)
+		}
+		if r.IsInt() {
+			return json.Number(r.Num().String()), nil
+		}
+		f := new(big.Float).SetPrec(256).SetRat(&r)
+		return json.Number(f.Text('e', -1)), nil
+	case []interface{}:
+		for i, v := range obj {
+			nv, err := canonicalizeGenesisValue(v)
+			if err != nil {
+				return nil, err
+			}
+			obj[i] = nv
+		}
+		return obj, nil
+	case map[string]interface{}:
+		for k, v := range obj {
+			nv, err := canonicalizeGenesisValue(v)
+			if err != nil {
+				return nil, err
+			}
+			obj[k] = nv
+		}
+		return obj, nil
+	default:
+		return obj, nil
+	}
+}
+
+// CanonicalGenesisHash returns SHA3-256 hash of the canonical form of
+// the genesis. Keys are sorted and numbers are normalized, so semantically
+// equal genesis documents have the same hash regardless of formatting.
+func CanonicalGenesisHash(js []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	var jso interface{}
+	if err := dec.Decode(&jso); err != nil {
+		return nil, InvalidGenesisError.Wrap(err, "InvalidJSON")
+	}
+	if dec.More() {
+		return nil, InvalidGenesisError.New("TrailingData")
+	}
+	jso, err := canonicalizeGenesisValue(jso)
+	if err != nil {
+		return nil, InvalidGenesisError.Wrap(err, "FailToCanonicalize")
+	}
+	bs, err := json.Marshal(jso)
+	if err != nil {
+		return nil, InvalidGenesisError.Wrap(err, "FailToCanonicalize")
+	}
+	return crypto.SHA3Sum256(bs), nil
+}
+
 func (g *genesisV3JSON) calcHash() ([]byte, error) {
 	var jso map[string]interface{}
 	if err := json.Unmarshal(g.raw, &jso); err != nil {
@@ -171,6 +231,12 @@ func (g *genesisV3) ID() []byte {
 	return g.txHash
 }
 
+// IdentityHash returns the canonical hash of the genesis, which identifies
+// the chain independently of the formatting of the genesis.
+func (g *genesisV3) IdentityHash() ([]byte, error) {
+	return CanonicalGenesisHash(g.raw)
+}
+
 func (g *genesisV3) ToJSON(version module.JSONVersion) (interface{}, error) {
 	var jso map[string]interface{}
 	if err := json.Unmarshal(g.raw, &jso); err != nil {
@@ -387,6 +453,113 @@ func parseV3Genesis(js []byte, raw bool) (Transaction, error) {
 	return parseV3GenesisJSON(js, jso, raw)
 }
 
+// ValidateGenesis checks the format of the genesis strictly, so that
+// malformed one is rejected with the reason before the chain is created.
+// The genesis of existing chains is parsed without it.
+func ValidateGenesis(b []byte) error {
+	if js, err := jsonCompact(b); err == nil {
+		b = js
+	}
+	var jsm map[string]interface{}
+	if err := json.Unmarshal(b, &jsm); err != nil {
+		return InvalidGenesisError.Wrap(err, "InvalidJSON")
+	}
+	return validateGenesisV3JSON(jsm)
+}
+
+func validateGenesisV3JSON(jsm map[string]interface{}) error {
+	accounts, ok := jsm["accounts"].([]interface{})
+	if !ok || len(accounts) == 0 {
+		return InvalidGenesisError.New("NoAccounts")
+	}
+	for idx, item := range accounts {
+		account, ok := item.(map[string]interface{})
+		if !ok {
+			return InvalidGenesisError.Errorf("InvalidAccount(idx=%d)", idx)
+		}
+		name, ok := account["name"].(string)
+		if !ok || len(name) == 0 {
+			return InvalidGenesisError.Errorf("InvalidAccountName(idx=%d,name=%v)", idx, account["name"])
+		}
+		if addr, ok := account["address"].(string); !ok ||
+			new(common.Address).SetStringStrict(addr) != nil {
+			return InvalidGenesisError.Errorf("InvalidAccountAddress(name=%s,address=%v)", name, account["address"])
+		}
+		if v, has := account["balance"]; has && v != nil {
+			balance, ok := v.(string)
+			var value big.Int
+			if !ok || !strings.HasPrefix(balance, "0x") ||
+				intconv.ParseBigInt(&value, balance) != nil || value.Sign() < 0 {
+				return InvalidGenesisError.Errorf("InvalidAccountBalance(name=%s,balance=%v)", name, v)
+			}
+		}
+	}
+	if chain, ok := jsm["chain"].(map[string]interface{}); ok {
+		if v, has := chain["validatorList"]; has && v != nil {
+			validators, ok := v.([]interface{})
+			if !ok {
+				return InvalidGenesisError.Errorf("InvalidValidatorList(%v)", v)
+			}
+			for idx, item := range validators {
+				if addr, ok := item.(string); !ok ||
+					new(common.Address).SetStringStrict(addr) != nil {
+					return InvalidGenesisError.Errorf("InvalidValidator(idx=%d,address=%v)", idx, item)
+				}
+			}
+		}
+		if v, has := chain["preps"]; has && v != nil {
+			if err := validateGenesisPReps(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateGenesisPReps(v interface{}) error {
+	preps, ok := v.([]interface{})
+	if !ok {
+		return InvalidGenesisError.Errorf("InvalidPReps(%v)", v)
+	}
+	owners := make(map[string]struct{}, len(preps))
+	for idx, item := range preps {
+		prep, ok := item.(map[string]interface{})
+		if !ok {
+			return InvalidGenesisError.Errorf("InvalidPRep(idx=%d)", idx)
+		}
+		owner := new(common.Address)
+		if addr, ok := prep["address"].(string); !ok ||
+			owner.SetStringStrict(addr) != nil || owner.IsContract() {
+			return InvalidGenesisError.Errorf("InvalidPRepAddress(idx=%d,address=%v)", idx, prep["address"])
+		}
+		if _, dup := owners[string(owner.Bytes())]; dup {
+			return InvalidGenesisError.Errorf("DuplicatePRep(idx=%d,address=%s)", idx, owner)
+		}
+		owners[string(owner.Bytes())] = struct{}{}
+		for _, key := range []string{"name", "email", "website", "country", "city", "details", "p2pEndpoint"} {
+			if s, ok := prep[key].(string); !ok || len(s) == 0 {
+				return InvalidGenesisError.Errorf("InvalidPRepField(address=%s,%s=%v)", owner, key, prep[key])
+			}
+		}
+		if v, has := prep["nodeAddress"]; has && v != nil {
+			node := new(common.Address)
+			if addr, ok := v.(string); !ok ||
+				node.SetStringStrict(addr) != nil || node.IsContract() {
+				return InvalidGenesisError.Errorf("InvalidPRepNodeAddress(address=%s,nodeAddress=%v)", owner, v)
+			}
+		}
+		if v, has := prep["delegation"]; has && v != nil {
+			delegation, ok := v.(string)
+			var value big.Int
+			if !ok || !strings.HasPrefix(delegation, "0x") ||
+				intconv.ParseBigInt(&value, delegation) != nil || value.Sign() < 0 {
+				return InvalidGenesisError.Errorf("InvalidPRepDelegation(address=%s,delegation=%v)", owner, v)
+			}
+		}
+	}
+	return nil
+}
+
 func parseV3GenesisJSON(js []byte, jsm map[string]interface{}, raw bool) (Transaction, error) {
 	genjs := new(genesisV3JSON)
 	if err := json.Unmarshal(js, genjs); err != nil {
@@ -84,3 +84,119 @@ func TestGenesisV3_ICONMainNet(t *testing.T) {
 	assert.Equal(t, ICONMainNetCID, gtx.CID())
 	assert.Equal(t, ICONMainNetCID, gtx.NID())
 }
+
+func TestGenesisV3_Validate(t *testing.T) {
+	const god = `{"name":"god","address":"hx736846756bcdea54366decfdbdae354789815103","balance":"0x1234"}`
+	const treasury = `{"name":"treasury","address":"hx1000000000000000000000000000000000000000"}`
+	tests := []struct {
+		name    string
+		genesis string
+		reason  string
+	}{
+		{"NoAccounts", `{"accounts":[]}`, "NoAccounts"},
+		{"BadAccount", `{"accounts":["god"]}`, "InvalidAccount"},
+		{"NoName", `{"accounts":[{"address":"hx1000000000000000000000000000000000000000"}]}`, "InvalidAccountName"},
+		{"BadAddress", `{"accounts":[{"name":"god","address":"hx1234"}]}`, "InvalidAccountAddress"},
+		{"BadBalanceHex", `{"accounts":[{"name":"god","address":"hx736846756bcdea54366decfdbdae354789815103","balance":"0xZZ"}]}`, "InvalidAccountBalance"},
+		{"DecimalBalance", `{"accounts":[{"name":"god","address":"hx736846756bcdea54366decfdbdae354789815103","balance":"1234"}]}`, "InvalidAccountBalance"},
+		{"NegativeBalance", `{"accounts":[{"name":"god","address":"hx736846756bcdea54366decfdbdae354789815103","balance":"-0x1"}]}`, "InvalidAccountBalance"},
+		{"BadValidatorList", `{"accounts":[` + god + `,` + treasury + `],"chain":{"validatorList":"hx1000000000000000000000000000000000000000"}}`, "InvalidValidatorList"},
+		{"BadValidator", `{"accounts":[` + god + `,` + treasury + `],"chain":{"validatorList":["hx1234"]}}`, "InvalidValidator"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGenesis([]byte(tt.genesis))
+			assert.Error(t, err)
+			assert.True(t, InvalidGenesisError.Equals(err))
+			assert.Contains(t, err.Error(), tt.reason)
+		})
+	}
+
+	genesis := `{"accounts":[` + god + `,` + treasury + `],"chain":{"validatorList":["hx1000000000000000000000000000000000000000"]}}`
+	assert.NoError(t, ValidateGenesis([]byte(genesis)))
+	tx, err := NewGenesisTransaction([]byte(genesis))
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Verify())
+
+	// the genesis of existing chains is parsed without validation
+	genesis = `{"accounts":[{"address":"hx736846756bcdea54366decfdbdae354789815103","balance":"0x1234"},` + treasury + `]}`
+	assert.Error(t, ValidateGenesis([]byte(genesis)))
+	_, err = NewGenesisTransaction([]byte(genesis))
+	assert.NoError(t, err)
+}
+
+func TestGenesisV3_ValidatePReps(t *testing.T) {
+	const accounts = `"accounts":[{"name":"god","address":"hx736846756bcdea54366decfdbdae354789815103","balance":"0x1234"},{"name":"treasury","address":"hx1000000000000000000000000000000000000000"}]`
+	const prep = `"address":"hx0000000000000000000000000000000000000001","name":"node1","email":"node1@example.com","website":"https://node1.example.com/","country":"KOR","city":"Seoul","details":"https://node1.example.com/details","p2pEndpoint":"node1.example.com:7100"`
+	tests := []struct {
+		name   string
+		preps  string
+		reason string
+	}{
+		{"NotList", `{` + prep + `}`, "InvalidPReps"},
+		{"BadPRep", `["node1"]`, "InvalidPRep"},
+		{"BadAddress", `[{"address":"cx0000000000000000000000000000000000000001"}]`, "InvalidPRepAddress"},
+		{"Duplicate", `[{` + prep + `},{` + prep + `}]`, "DuplicatePRep"},
+		{"NoName", `[{"address":"hx0000000000000000000000000000000000000001"}]`, "InvalidPRepField"},
+		{"BadNode", `[{` + prep + `,"nodeAddress":"hx1234"}]`, "InvalidPRepNodeAddress"},
+		{"BadDelegation", `[{` + prep + `,"delegation":"100"}]`, "InvalidPRepDelegation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			genesis := `{` + accounts + `,"chain":{"preps":` + tt.preps + `}}`
+			err := ValidateGenesis([]byte(genesis))
+			assert.Error(t, err)
+			assert.True(t, InvalidGenesisError.Equals(err))
+			assert.Contains(t, err.Error(), tt.reason)
+		})
+	}
+
+	genesis := `{` + accounts + `,"chain":{"preps":[{` + prep + `,"nodeAddress":"hx0000000000000000000000000000000000000011","delegation":"0x10"}]}}`
+	assert.NoError(t, ValidateGenesis([]byte(genesis)))
+	tx, err := NewGenesisTransaction([]byte(genesis))
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Verify())
+}
+
+func TestGenesisV3_CanonicalHash(t *testing.T) {
+	const genesis = `{
+	"accounts": [
+		{"name": "god", "address": "hx736846756bcdea54366decfdbdae354789815103", "balance": "0x1234"},
+		{"name": "treasury", "address": "hx1000000000000000000000000000000000000000"}
+	],
+	"message": "genesis for test",
+	"chain": {"revision": 10, "blockInterval": 1.5e3, "ratio": 0.25}
+}`
+	equivalents := []string{
+		`{"message":"genesis for test","chain":{"ratio":2.5e-1,"blockInterval":1500,"revision":10.0},"accounts":[{"address":"hx736846756bcdea54366decfdbdae354789815103","name":"god","balance":"0x1234"},{"name":"treasury","address":"hx1000000000000000000000000000000000000000"}]}`,
+		`{ "accounts" : [ { "balance" : "0x1234" , "name" : "god" , "address" : "hx736846756bcdea54366decfdbdae354789815103" } , { "name" : "treasury" , "address" : "hx1000000000000000000000000000000000000000" } ] , "chain" : { "revision" : 1e1 , "blockInterval" : 1500.00 , "ratio" : 0.250 } , "message" : "genesis for test" }`,
+	}
+	differents := []string{
+		`{"message":"genesis for test","chain":{"ratio":0.25,"blockInterval":1501,"revision":10},"accounts":[{"address":"hx736846756bcdea54366decfdbdae354789815103","name":"god","balance":"0x1234"},{"name":"treasury","address":"hx1000000000000000000000000000000000000000"}]}`,
+		`{"message":"genesis for test","chain":{"ratio":0.25,"blockInterval":1500,"revision":10},"accounts":[{"name":"treasury","address":"hx1000000000000000000000000000000000000000"},{"address":"hx736846756bcdea54366decfdbdae354789815103","name":"god","balance":"0x1234"}]}`,
+	}
+
+	expected, err := CanonicalGenesisHash([]byte(genesis))
+	assert.NoError(t, err)
+	assert.Len(t, expected, 32)
+
+	for _, js := range equivalents {
+		h, err := CanonicalGenesisHash([]byte(js))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, h, js)
+	}
+	for _, js := range differents {
+		h, err := CanonicalGenesisHash([]byte(js))
+		assert.NoError(t, err)
+		assert.NotEqual(t, expected, h, js)
+	}
+
+	tx, err := NewGenesisTransaction([]byte(genesis))
+	assert.NoError(t, err)
+	h, err := tx.IdentityHash()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, h)
+
+	_, err = CanonicalGenesisHash([]byte(`{"accounts":[]} {}`))
+	assert.True(t, InvalidGenesisError.Equals(err))
+}
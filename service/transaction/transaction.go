@@ -28,10 +28,18 @@ type Transaction interface {
 	IsSkippable() bool
 }
 
+// StepEstimator is implemented by transactions which can tell the maximum
+// steps they may use on execution. It's used to bound the total steps of
+// transactions in a block.
+type StepEstimator interface {
+	EstimatedStep() *big.Int
+}
+
 type GenesisTransaction interface {
 	Transaction
 	CID() int
 	NID() int
+	IdentityHash() ([]byte, error)
 }
 
 type transaction struct {
@@ -100,6 +108,10 @@ func (t *transaction) CID() int {
 	return t.Transaction.(GenesisTransaction).CID()
 }
 
+func (t *transaction) IdentityHash() ([]byte, error) {
+	return t.Transaction.(GenesisTransaction).IdentityHash()
+}
+
 func (t *transaction) ClearCache() {
 	// nothing to do
 }
@@ -223,6 +223,10 @@ func (tx *transactionV2) IsSkippable() bool {
 	return true
 }
 
+func (tx *transactionV2) EstimatedStep() *big.Int {
+	return version2StepUsed
+}
+
 func checkV2(jso map[string]interface{}) bool {
 	if _, ok := jso["version"]; ok {
 		return false
@@ -392,6 +392,10 @@ func (tx *transactionV3) IsSkippable() bool {
 	return tx.Group() == module.TransactionGroupNormal
 }
 
+func (tx *transactionV3) EstimatedStep() *big.Int {
+	return &tx.StepLimit.Int
+}
+
 func checkV3JSON(jso map[string]interface{}) bool {
 	if version, ok := jso["version"]; !ok || version != "0x3" {
 		return false
@@ -155,6 +155,12 @@ func (th *transactionHandler) DoExecute(cc contract.CallContext, estimate, isPat
 
 	// Execute
 	status, used, _, addr := cc.Call(th.chandler, cc.StepAvailable())
+	if cc.Revision().Has(module.CapStepRefund) {
+		if capped := capStepRefund(used, cc.StepUsed(), cc.StepRefundCap()); capped != used {
+			cc.FrameLogger().TSystemf("STEP cap refund value=%d old=%d", capped, used)
+			used = capped
+		}
+	}
 	cc.DeductSteps(used)
 
 	// If it fails for system failure, then it needs to re-run this.
@@ -168,6 +174,25 @@ func (th *transactionHandler) DoExecute(cc contract.CallContext, estimate, isPat
 	return status, addr, nil
 }
 
+// capStepRefund limits the refund (negative steps used by the call) up to
+// the steps already used by the transaction, so that negative step costs
+// can't drive the total steps used below zero. If rate is positive, then
+// the refund is limited up to rate percent of them.
+func capStepRefund(used, stepUsed *big.Int, rate int64) *big.Int {
+	if used.Sign() >= 0 {
+		return used
+	}
+	limit := new(big.Int).Set(stepUsed)
+	if rate > 0 {
+		limit.Mul(limit, big.NewInt(rate))
+		limit.Div(limit, big.NewInt(100))
+	}
+	if new(big.Int).Neg(used).Cmp(limit) <= 0 {
+		return used
+	}
+	return limit.Neg(limit)
+}
+
 func (th *transactionHandler) Execute(ctx contract.Context, wcs state.WorldSnapshot, estimate bool) (txresult.Receipt, error) {
 	isPatch := th.group == module.TransactionGroupPatch
 	limit := th.stepLimit
@@ -0,0 +1,175 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transaction
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/trace"
+)
+
+func TestCapStepRefund(t *testing.T) {
+	cases := []struct {
+		name     string
+		used     int64
+		stepUsed int64
+		rate     int64
+		expected int64
+	}{
+		{"NoCap", -70000, 100000, 0, -70000},
+		{"NoCapNegative", -170000, 100000, 0, -100000},
+		{"Positive", 5000, 100000, 20, 5000},
+		{"UnderCap", -15000, 100000, 20, -15000},
+		{"ExactCap", -20000, 100000, 20, -20000},
+		{"DestructHeavy", -70000, 100000, 20, -20000},
+		{"FullRefund", -170000, 100000, 100, -100000},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stepUsed := big.NewInt(c.stepUsed)
+			used := capStepRefund(big.NewInt(c.used), stepUsed, c.rate)
+			assert.Equal(t, c.expected, used.Int64())
+
+			// total steps used can't be negative
+			assert.True(t, new(big.Int).Add(stepUsed, used).Sign() >= 0)
+		})
+	}
+}
+
+type testPlatform struct {
+	revision module.Revision
+}
+
+func (p testPlatform) ToRevision(value int) module.Revision {
+	return p.revision
+}
+
+type testChain struct {
+	module.Chain
+}
+
+func (c *testChain) TransactionTimeout() time.Duration {
+	return 5 * time.Second
+}
+
+// refundHandler refunds steps like a contract destructing itself.
+type refundHandler struct {
+	refund int64
+}
+
+func (h *refundHandler) Prepare(ctx contract.Context) (state.WorldContext, error) {
+	return ctx, nil
+}
+
+func (h *refundHandler) SetTraceLogger(logger *trace.Logger) {}
+
+func (h *refundHandler) TraceLogger() *trace.Logger {
+	return nil
+}
+
+func (h *refundHandler) ExecuteSync(cc contract.CallContext) (error, *codec.TypedObj, module.Address) {
+	cc.DeductSteps(big.NewInt(-h.refund))
+	return nil, nil, nil
+}
+
+func newRefundTestContext(t *testing.T, rev module.Revision, from module.Address, refundCap int64) (contract.Context, state.WorldSnapshot) {
+	dbase := db.NewMapDB()
+	ws := state.NewWorldState(dbase, nil, nil, nil, nil)
+	ws.GetAccountState(from.ID()).SetBalance(big.NewInt(1_000_000_000))
+	as := ws.GetAccountState(state.SystemID)
+	assert.NoError(t, scoredb.NewVarDB(as, state.VarStepPrice).Set(1))
+	costs := map[string]int64{
+		state.StepTypeDefault: 100_000,
+		state.StepTypeInput:   200,
+	}
+	for name, cost := range costs {
+		assert.NoError(t, scoredb.NewArrayDB(as, state.VarStepTypes).Put(name))
+		assert.NoError(t, scoredb.NewDictDB(as, state.VarStepCosts, 1).Set(name, cost))
+	}
+	assert.NoError(t, scoredb.NewArrayDB(as, state.VarStepLimitTypes).Put(state.StepLimitTypeInvoke))
+	assert.NoError(t, scoredb.NewDictDB(as, state.VarStepLimit, 1).Set(state.StepLimitTypeInvoke, 1_000_000_000))
+	if refundCap > 0 {
+		assert.NoError(t, scoredb.NewVarDB(as, state.VarStepRefundCap).Set(refundCap))
+	}
+	wc := state.NewWorldContext(ws, common.NewBlockInfo(1, 0), nil, testPlatform{rev})
+	wc.UpdateSystemInfo()
+	ctx := contract.NewContext(wc, nil, nil, &testChain{}, log.New(), nil, eeproxy.ForTransaction)
+	return ctx, ws.GetSnapshot()
+}
+
+func TestTransactionHandler_ExecuteWithRefund(t *testing.T) {
+	data := []byte(fmt.Sprintf(`{"method":"destruct","params":{"pad":"%s"}}`, strings.Repeat("0", 1000)))
+	cnt, err := MeasureBytesOfData(module.LatestRevision, data)
+	assert.NoError(t, err)
+	used := 100_000 + 200*int64(cnt)
+	dataType := contract.DataTypeCall
+	from := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+
+	oldRev := module.LatestRevision &^ module.CapStepRefund
+
+	cases := []struct {
+		name     string
+		rev      module.Revision
+		cap      int64
+		refund   int64
+		expected int64
+	}{
+		// total steps are limited to zero, then reset to the minimum
+		{"NoCap", module.LatestRevision, 0, used + 100_000, 100_000},
+		{"UnderCap", module.LatestRevision, 50, used / 4, used - used/4},
+		{"DestructHeavy", module.LatestRevision, 20, used / 2, used - used*20/100},
+		{"FullRefund", module.LatestRevision, 100, used - 50_000, 100_000},
+		// refund isn't capped before the revision
+		{"DestructHeavyOldRevision", oldRev, 20, used / 2, used - used/2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, wcs := newRefundTestContext(t, c.rev, from, c.cap)
+			th := &transactionHandler{
+				group:     module.TransactionGroupNormal,
+				from:      from,
+				to:        common.MustNewAddressFromString("cx0000000000000000000000000000000000000002"),
+				value:     new(big.Int),
+				stepLimit: big.NewInt(10_000_000),
+				dataType:  &dataType,
+				data:      data,
+				chandler:  &refundHandler{refund: c.refund},
+			}
+			defer th.Dispose()
+
+			rct, err := th.Execute(ctx, wcs, false)
+			assert.NoError(t, err)
+			assert.Equal(t, module.StatusSuccess, rct.Status())
+			assert.Equal(t, c.expected, rct.StepUsed().Int64())
+		})
+	}
+}
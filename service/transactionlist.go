@@ -189,6 +189,11 @@ func (l *transactionList) RemoveTx(tx module.Transaction) (bool, int64) {
 	return false, 0
 }
 
+func (l *transactionList) Get(id []byte) *txElement {
+	tidBk, tidSlot := indexAndBucketKeyFromKey(string(id))
+	return l.idMap[tidBk][tidSlot]
+}
+
 func (l *transactionList) Remove(t *txElement) bool {
 	if t.list == nil || t.list != l {
 		return false
@@ -16,6 +16,7 @@ type mockTransaction struct {
 	id        []byte
 	from      module.Address
 	timeStamp int64
+	step      int64
 }
 
 func (*mockTransaction) Group() module.TransactionGroup {
@@ -51,7 +52,7 @@ func (*mockTransaction) ToJSON(version module.JSONVersion) (interface{}, error)
 }
 
 func (*mockTransaction) PreValidate(wc state.WorldContext, update bool) error {
-	panic("implement me")
+	return nil
 }
 
 func (*mockTransaction) GetHandler(cm contract.ContractManager) (transaction.Handler, error) {
@@ -78,6 +79,10 @@ func (t *mockTransaction) IsSkippable() bool {
 	return true
 }
 
+func (t *mockTransaction) EstimatedStep() *big.Int {
+	return big.NewInt(t.step)
+}
+
 func newMockTransaction(id []byte, from module.Address, ts int64) *mockTransaction {
 	return &mockTransaction{
 		id:        id,
@@ -52,6 +52,20 @@ func (m *TransactionManager) HasTx(id []byte) bool {
 	return m.normalTxPool.HasTx(id) || m.patchTxPool.HasTx(id)
 }
 
+// Cancel removes a pending transaction from the pool. It returns
+// ErrCommittedTransaction if the transaction is already included in a block.
+func (m *TransactionManager) Cancel(id []byte) error {
+	if m.normalTxPool.Cancel(id) || m.patchTxPool.Cancel(id) {
+		return nil
+	}
+	if has, err := m.tim.HasLocator(id); err != nil {
+		return err
+	} else if has {
+		return ErrCommittedTransaction
+	}
+	return errors.ErrNotFound
+}
+
 func (m *TransactionManager) RemoveTxs(
 	g module.TransactionGroup, l module.TransactionList,
 ) {
@@ -59,9 +73,9 @@ func (m *TransactionManager) RemoveTxs(
 }
 
 func (m *TransactionManager) Candidate(
-	g module.TransactionGroup, wc state.WorldContext, maxBytes, maxCount int,
+	g module.TransactionGroup, wc state.WorldContext, maxBytes, maxCount int, maxSteps int64,
 ) ([]module.Transaction, int) {
-	return m.getTxPool(g).Candidate(wc, maxBytes, maxCount)
+	return m.getTxPool(g).Candidate(wc, maxBytes, maxCount, maxSteps)
 }
 
 func (m *TransactionManager) NotifyFinalized(
@@ -17,6 +17,7 @@
 package service
 
 import (
+	"math/big"
 	"sync"
 	"time"
 
@@ -123,7 +124,9 @@ func (tp *TransactionPool) DropOldTXs(bts int64) {
 }
 
 // It returns all candidates for a negative integer n.
-func (tp *TransactionPool) Candidate(wc state.WorldContext, maxBytes int, maxCount int) (
+// If maxSteps is positive, it stops before the transaction which makes the
+// sum of estimated steps of the candidates exceed maxSteps.
+func (tp *TransactionPool) Candidate(wc state.WorldContext, maxBytes int, maxCount int, maxSteps int64) (
 	[]module.Transaction, int,
 ) {
 	lock := common.Lock(&tp.mutex)
@@ -147,6 +150,7 @@ func (tp *TransactionPool) Candidate(wc state.WorldContext, maxBytes int, maxCou
 	dropped := make([]*txElement, 0, configDefaultTxSliceCapacity)
 	poolSize := tp.list.Len()
 	txSize := int(0)
+	txSteps := new(big.Int)
 	for e := tp.list.Front(); e != nil && txSize < maxBytes && len(txs) < maxCount; e = e.Next() {
 		tx := e.Value()
 		if err := tsr.CheckTx(tx); err != nil {
@@ -181,6 +185,15 @@ func (tp *TransactionPool) Candidate(wc state.WorldContext, maxBytes int, maxCou
 		if txSize+len(bs) > maxBytes {
 			break
 		}
+		if maxSteps > 0 {
+			if se, ok := tx.(transaction.StepEstimator); ok {
+				steps := new(big.Int).Add(txSteps, se.EstimatedStep())
+				if steps.Cmp(big.NewInt(maxSteps)) > 0 {
+					break
+				}
+				txSteps = steps
+			}
+		}
 		txSize += len(bs)
 		txs = append(txs, tx)
 	}
@@ -274,6 +287,26 @@ func (tp *TransactionPool) RemoveList(txs module.TransactionList) {
 	}
 }
 
+// Cancel removes a pending transaction with the id from the pool.
+// It returns false if there is no such transaction in the pool.
+func (tp *TransactionPool) Cancel(tid []byte) bool {
+	lock := common.LockForAutoCall(&tp.mutex)
+	defer lock.Unlock()
+
+	e := tp.list.Get(tid)
+	if e == nil || !tp.list.Remove(e) {
+		return false
+	}
+	tx := e.Value()
+	tp.log.Debugf("CANCEL TX: id=0x%x", tid)
+	tp.monitor.OnDropTx(len(tx.Bytes()), e.ts != 0)
+	tp.pcm.OnPoolCapacityUpdated(tp.group, tp.size, tp.list.Len())
+	lock.CallAfterUnlock(func() {
+		tp.txm.OnTxDrops([]TxDrop{{tid, ErrCanceledTransaction}})
+	})
+	return true
+}
+
 func (tp *TransactionPool) HasTx(tid []byte) bool {
 	tp.mutex.Lock()
 	defer tp.mutex.Unlock()
@@ -7,7 +7,9 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/common/txlocator"
 	"github.com/icon-project/goloop/module"
@@ -49,3 +51,39 @@ func TestTransactionPool_Add(t *testing.T) {
 		t.Error("Fail to add transaction with valid network ID")
 	}
 }
+
+func TestTransactionManager_Cancel(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	logger := log.New()
+	lm, err := txlocator.NewManager(dbase, logger)
+	assert.NoError(t, err)
+	tim, _ := NewTXIDManager(lm, tsc, nil)
+	ptp := NewTransactionPool(module.TransactionGroupPatch, 10, tim, &mockMonitor{}, logger)
+	ntp := NewTransactionPool(module.TransactionGroupNormal, 5000, tim, &mockMonitor{}, logger)
+	tm := NewTransactionManager(1, tsc, ptp, ntp, tim, logger)
+
+	ts := time.Now().UnixMicro()
+	addr := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	tx1 := newMockTransaction(crypto.SHA3Sum256([]byte("tx1")), addr, ts)
+	tx2 := newMockTransaction(crypto.SHA3Sum256([]byte("tx2")), addr, ts+1)
+	assert.NoError(t, ntp.Add(tx1, true))
+	assert.NoError(t, ntp.Add(tx2, true))
+
+	rc, err := tm.WaitResult(tx1.ID())
+	assert.NoError(t, err)
+
+	assert.NoError(t, tm.Cancel(tx1.ID()))
+	assert.False(t, tm.HasTx(tx1.ID()))
+	assert.True(t, tm.HasTx(tx2.ID()))
+	assert.Equal(t, ErrCanceledTransaction, <-rc)
+
+	wc := &testWContext{ts: ts}
+	txs, _ := tm.Candidate(module.TransactionGroupNormal, wc, 0, 0, 0)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, tx2.ID(), txs[0].ID())
+
+	// already canceled one is unknown to the manager
+	err = tm.Cancel(tx1.ID())
+	assert.True(t, errors.NotFoundError.Equals(err))
+}
@@ -88,6 +88,8 @@ type transitionContext struct {
 	sass  state.AccountSnapshot
 	tim   TXIDManager
 	dsm   DSRManager
+
+	txTimeout time.Duration
 }
 
 func (tc *transitionContext) onWorldFinalize(wss state.WorldSnapshot) {
@@ -299,6 +301,34 @@ func (t *transition) NormalReceipts() module.ReceiptList {
 	return t.normalReceipts
 }
 
+// Receipts returns receipts of all transactions in execution order, which
+// are receipts of patch transactions followed by ones of normal transactions.
+// It returns nil before the execution is completed, so the returned
+// receipts are always consistent with Result.
+func (t *transition) Receipts() module.ReceiptList {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.step != stepComplete {
+		return nil
+	}
+	var rcts []txresult.Receipt
+	for _, rl := range []module.ReceiptList{t.patchReceipts, t.normalReceipts} {
+		if rl == nil {
+			continue
+		}
+		for itr := rl.Iterator(); itr.Has(); itr.Next() {
+			rct, err := itr.Get()
+			if err != nil {
+				t.log.Warnf("FAIL to get receipt err=%+v", err)
+				return nil
+			}
+			rcts = append(rcts, rct.(txresult.Receipt))
+		}
+	}
+	return txresult.NewReceiptListFromSlice(t.db, rcts)
+}
+
 // startExecution executes this transition.
 // The result is asynchronously notified by cb. canceler can be used
 // to cancel it after calling Execute. After canceler returns true,
@@ -434,10 +464,13 @@ func (t *transition) newWorldContext(execution bool) (state.WorldContext, error)
 
 func (t *transition) newContractContext(wc state.WorldContext) contract.Context {
 	priority := eeproxy.ForTransaction
+	chain := t.chain
 	if t.ti != nil {
 		priority = eeproxy.ForQuery
+	} else if t.txTimeout > 0 {
+		chain = &txTimeoutChain{Chain: chain, timeout: t.txTimeout}
 	}
-	return contract.NewContext(wc, t.cm, t.eem, t.chain, t.log, t.ti, priority)
+	return contract.NewContext(wc, t.cm, t.eem, chain, t.log, t.ti, priority)
 }
 
 func (t *transition) reportValidation(e error) bool {
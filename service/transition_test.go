@@ -20,6 +20,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
 )
 
 func TestTransitionID(t *testing.T) {
@@ -27,3 +33,41 @@ func TestTransitionID(t *testing.T) {
 	id2 := new(transitionID)
 	assert.False(t, id1 == id2)
 }
+
+func TestTransition_Receipts(t *testing.T) {
+	database := db.NewMapDB()
+	rev := module.LatestRevision
+	newReceipts := func(addrs ...string) module.ReceiptList {
+		var rcts []txresult.Receipt
+		for _, addr := range addrs {
+			rcts = append(rcts, txresult.NewReceipt(database, rev, common.MustNewAddressFromString(addr)))
+		}
+		return txresult.NewReceiptListFromSlice(database, rcts)
+	}
+	tr := &transition{
+		transitionContext: &transitionContext{db: database, log: log.New()},
+		step:              stepExecuting,
+		patchReceipts:     newReceipts("cx0000000000000000000000000000000000000001"),
+		normalReceipts:    newReceipts("hx0000000000000000000000000000000000000002", "hx0000000000000000000000000000000000000003"),
+	}
+	assert.Nil(t, tr.Receipts())
+
+	tr.step = stepComplete
+	rl := tr.Receipts()
+	assert.NotNil(t, rl)
+	var tos []string
+	for itr := rl.Iterator(); itr.Has(); assert.NoError(t, itr.Next()) {
+		rct, err := itr.Get()
+		assert.NoError(t, err)
+		tos = append(tos, rct.To().String())
+	}
+	assert.Equal(t, []string{"cx0000000000000000000000000000000000000001", "hx0000000000000000000000000000000000000002", "hx0000000000000000000000000000000000000003"}, tos)
+
+	// patch transactions only
+	tr.normalReceipts = newReceipts()
+	rct, err := tr.Receipts().Get(0)
+	assert.NoError(t, err)
+	assert.Equal(t, "cx0000000000000000000000000000000000000001", rct.To().String())
+	_, err = tr.Receipts().Get(1)
+	assert.Error(t, err)
+}
@@ -17,6 +17,7 @@
 package service
 
 import (
+	"bytes"
 	"io"
 
 	"github.com/icon-project/goloop/chain/base"
@@ -124,6 +125,39 @@ func (tr *transitionResult) Bytes() []byte {
 	}
 }
 
+// compareTransitionResult compares each part of the result with the expected
+// one, and returns an error describing the first mismatched part.
+func compareTransitionResult(expected, result []byte) error {
+	if bytes.Equal(expected, result) {
+		return nil
+	}
+	etr, err := newTransitionResultFromBytes(expected)
+	if err != nil {
+		return err
+	}
+	rtr, err := newTransitionResultFromBytes(result)
+	if err != nil {
+		return err
+	}
+	for _, part := range []struct {
+		name     string
+		expected []byte
+		result   []byte
+	}{
+		{"StateHash", etr.StateHash, rtr.StateHash},
+		{"PatchReceiptHash", etr.PatchReceiptHash, rtr.PatchReceiptHash},
+		{"NormalReceiptHash", etr.NormalReceiptHash, rtr.NormalReceiptHash},
+		{"ExtensionData", etr.ExtensionData, rtr.ExtensionData},
+		{"BTPData", etr.BTPData, rtr.BTPData},
+	} {
+		if !bytes.Equal(part.expected, part.result) {
+			return InvalidResultError.Errorf("%sMismatch(exp=%#x,real=%#x)",
+				part.name, part.expected, part.result)
+		}
+	}
+	return InvalidResultError.Errorf("ResultMismatch(exp=%#x,real=%#x)", expected, result)
+}
+
 func NewWorldSnapshot(database db.Database, plt base.Platform, result []byte, vl module.ValidatorList) (state.WorldSnapshot, error) {
 	return newWorldSnapshot(database, plt, result, vl)
 }
@@ -73,4 +73,35 @@ func Test_NewBTPContext(t *testing.T) {
 	ctx, err := NewBTPContext(dbase, nil)
 	assert.NoError(t, err)
 	assert.NotNil(t, ctx)
-}
\ No newline at end of file
+}
+
+func Test_compareTransitionResult(t *testing.T) {
+	s1, _ := hex.DecodeString("6a41c16fb4827945748042f252c39805fb916e3e47f157b3620cfc8ce0c3093d")
+	r1, _ := hex.DecodeString("6fa24a70df169c2cb1e10d1ae748096ed0730fc1b1bc869f2ce21abe64f85820")
+	r2, _ := hex.DecodeString("ed9e644e59b2ff65446f5f3d7d77c27858facf8aeb3b969470d7499c79f9757c")
+	b1, _ := hex.DecodeString("a09ec44e59b2ff65426f5f3d7d79c27858f1cf8aeb3b969470d749dc7df97a7e")
+	expected := (&transitionResult{s1, r1, r2, nil, b1}).Bytes()
+
+	tests := []struct {
+		name   string
+		result *transitionResult
+		errMsg string
+	}{
+		{"Valid", &transitionResult{s1, r1, r2, nil, b1}, ""},
+		{"TamperedState", &transitionResult{r1, r1, r2, nil, b1}, "StateHashMismatch"},
+		{"TamperedPatchReceipts", &transitionResult{s1, r2, r2, nil, b1}, "PatchReceiptHashMismatch"},
+		{"TamperedNormalReceipts", &transitionResult{s1, r1, r1, nil, b1}, "NormalReceiptHashMismatch"},
+		{"TamperedBTPData", &transitionResult{s1, r1, r2, nil, s1}, "BTPDataMismatch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := compareTransitionResult(expected, tt.result.Bytes())
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, InvalidResultError.Equals(err))
+				assert.Contains(t, err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/icon-project/goloop/block"
 	"github.com/icon-project/goloop/chain/base"
+	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
@@ -43,6 +44,7 @@ type FixtureConfig struct {
 	NewBM             func(ctx *NodeContext) module.BlockManager
 	NewCS             func(ctx *NodeContext) module.Consensus
 	TimeoutPropose    time.Duration
+	Clock             common.Clock
 	AddValidatorNodes int
 	Genesis           string
 	GenesisStorage    module.GenesisStorage
@@ -78,6 +80,9 @@ func NewFixtureConfig(t T, o ...FixtureOption) *FixtureConfig {
 				ctx.C, wal, wm, nil, nil, nil, ctx.Config.TimeoutPropose,
 			)
 			assert.NotNil(ctx.Config.T, cs)
+			if ctx.Config.Clock != nil {
+				cs.SetClock(ctx.Config.Clock)
+			}
 			return cs
 		},
 		AddValidatorNodes: 0,
@@ -133,6 +138,9 @@ func (cf *FixtureConfig) Override(cf2 *FixtureConfig) *FixtureConfig {
 	if cf2.TimeoutPropose != 0 {
 		res.TimeoutPropose = cf2.TimeoutPropose
 	}
+	if cf2.Clock != nil {
+		res.Clock = cf2.Clock
+	}
 	if cf2.AddValidatorNodes != 0 {
 		res.AddValidatorNodes = cf2.AddValidatorNodes
 	}
@@ -19,6 +19,7 @@ package test
 import (
 	"time"
 
+	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
@@ -81,6 +82,10 @@ func SetTimeoutPropose(to time.Duration) FixtureOption {
 	return UseConfig(&FixtureConfig{TimeoutPropose: to})
 }
 
+func UseClock(cl common.Clock) FixtureOption {
+	return UseConfig(&FixtureConfig{Clock: cl})
+}
+
 func UseSMFactory(f func(ctx *NodeContext) module.ServiceManager) FixtureOption {
 	return UseConfig(&FixtureConfig{NewSM: f})
 }
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+// HTTPError is returned if the server responds with a status other than
+// 200 OK and without JSON-RPC error object.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP %s body=%q", e.Status, e.Body)
+}
+
+type response struct {
+	Version string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result"`
+	Error   *jsonrpc.Error  `json:"error,omitempty"`
+	ID      interface{}     `json:"id"`
+}
+
+// Client calls JSON-RPC methods of a node for integration tests.
+// It reads whole response body, so large responses like getBlock are
+// not truncated.
+type Client struct {
+	hc       *http.Client
+	endpoint string
+	id       int64
+}
+
+func NewClient(endpoint string) *Client {
+	return &Client{
+		hc:       http.DefaultClient,
+		endpoint: endpoint,
+	}
+}
+
+// Call calls the method with params, and decodes the result into result
+// if it's not nil. If the server returns JSON-RPC error object, then it
+// returns *jsonrpc.Error. If the server returns other status than 200 OK
+// without the object, then it returns *HTTPError with the body.
+func (c *Client) Call(method string, params, result interface{}) error {
+	req := &jsonrpc.Request{
+		Version: jsonrpc.Version,
+		Method:  &method,
+		ID:      atomic.AddInt64(&c.id, 1),
+	}
+	if params != nil {
+		bs, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		req.Params = bs
+	}
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	resp, err := c.hc.Post(c.endpoint, "application/json", bytes.NewReader(reqBytes))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var jrResp response
+	if err := json.Unmarshal(body, &jrResp); err != nil || jrResp.Error == nil {
+		if resp.StatusCode != http.StatusOK {
+			return &HTTPError{
+				StatusCode: resp.StatusCode,
+				Status:     resp.Status,
+				Body:       string(body),
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("fail to decode response err=%v body=%q", err, body)
+		}
+	}
+	if jrResp.Error != nil {
+		return jrResp.Error
+	}
+	if result != nil {
+		return json.Unmarshal(jrResp.Result, result)
+	}
+	return nil
+}
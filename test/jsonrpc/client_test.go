@@ -0,0 +1,95 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+func newTestServer(status int, contentType, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+}
+
+func TestClient_Call(t *testing.T) {
+	var req jsonrpc.Request
+	large := strings.Repeat("a", 3*1024*1024)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bs, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(bs, &req)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"data":"`+large+`"}}`)
+	}))
+	defer s.Close()
+
+	var result struct {
+		Data string `json:"data"`
+	}
+	c := NewClient(s.URL)
+	assert.NoError(t, c.Call("icx_getBlockByHeight", map[string]string{"height": "0x1"}, &result))
+	assert.Equal(t, large, result.Data)
+	assert.Equal(t, "icx_getBlockByHeight", *req.Method)
+	assert.JSONEq(t, `{"height":"0x1"}`, string(req.Params))
+
+	// result can be ignored
+	assert.NoError(t, c.Call("icx_getBlockByHeight", nil, nil))
+}
+
+func TestClient_CallWithError(t *testing.T) {
+	errBody := `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"MethodNotFound"}}`
+	for _, status := range []int{http.StatusOK, http.StatusBadRequest} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			s := newTestServer(status, "application/json", errBody)
+			defer s.Close()
+
+			err := NewClient(s.URL).Call("test", nil, nil)
+			jerr, ok := err.(*jsonrpc.Error)
+			if assert.True(t, ok) {
+				assert.Equal(t, jsonrpc.ErrorCodeMethodNotFound, jerr.Code)
+				assert.Equal(t, "MethodNotFound", jerr.Message)
+			}
+		})
+	}
+
+	// body of error status is kept in the error
+	s := newTestServer(http.StatusBadGateway, "text/plain", "upstream failure")
+	defer s.Close()
+	err := NewClient(s.URL).Call("test", nil, nil)
+	herr, ok := err.(*HTTPError)
+	if assert.True(t, ok) {
+		assert.Equal(t, http.StatusBadGateway, herr.StatusCode)
+		assert.Equal(t, "upstream failure", herr.Body)
+	}
+
+	// malformed body with 200 OK
+	s2 := newTestServer(http.StatusOK, "application/json", "{")
+	defer s2.Close()
+	assert.Error(t, NewClient(s2.URL).Call("test", nil, nil))
+}
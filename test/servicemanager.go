@@ -52,6 +52,7 @@ type ServiceManager struct {
 	emptyTXs         module.TransactionList
 	nextBlockVersion int
 	pool             []module.Transaction
+	patches          []module.Transaction
 	txWaiters        []func()
 }
 
@@ -79,22 +80,31 @@ func (sm *ServiceManager) TransactionFromBytes(b []byte, blockVersion int) (modu
 	return transaction.NewTransaction(b)
 }
 
-func (sm *ServiceManager) ProposeTransition(parent module.Transition, bi module.BlockInfo, csi module.ConsensusInfo) (module.Transition, error) {
-	sm.mu.Lock()
-	defer sm.mu.Unlock()
-
+// filterCommitted returns transactions in txs which are not committed yet.
+func (sm *ServiceManager) filterCommitted(txs []module.Transaction) ([]module.Transaction, error) {
 	bk, err := sm.dbase.GetBucket(db.TransactionLocatorByHash)
 	if err != nil {
 		return nil, err
 	}
 	var filtered []module.Transaction
-	for _, t := range sm.pool {
+	for _, t := range txs {
 		bs, err := bk.Get(t.ID())
 		if bs != nil && err == nil {
 			continue
 		}
 		filtered = append(filtered, t)
 	}
+	return filtered, nil
+}
+
+func (sm *ServiceManager) ProposeTransition(parent module.Transition, bi module.BlockInfo, csi module.ConsensusInfo) (module.Transition, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	filtered, err := sm.filterCommitted(sm.pool)
+	if err != nil {
+		return nil, err
+	}
 	txs := transaction.NewTransactionListFromSlice(sm.dbase, filtered)
 	sm.pool = filtered
 	return service.NewTransition(
@@ -136,11 +146,22 @@ func (sm *ServiceManager) CreateTransition(parent module.Transition, txs module.
 }
 
 func (sm *ServiceManager) GetPatches(parent module.Transition, bi module.BlockInfo) module.TransactionList {
-	return sm.emptyTXs
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	filtered, err := sm.filterCommitted(sm.patches)
+	if err != nil || len(filtered) == 0 {
+		return sm.emptyTXs
+	}
+	sm.patches = filtered
+	return transaction.NewTransactionListFromSlice(sm.dbase, filtered)
 }
 
 func (sm *ServiceManager) PatchTransition(transition module.Transition, patches module.TransactionList, bi module.BlockInfo) module.Transition {
-	return transition
+	if patches == nil || len(patches.Hash()) == 0 {
+		return transition
+	}
+	return service.PatchTransition(transition, patches, bi, true)
 }
 
 func (sm *ServiceManager) CreateSyncTransition(transition module.Transition, result []byte, vlHash []byte, noBuffer bool) module.Transition {
@@ -152,19 +173,15 @@ func (sm *ServiceManager) Finalize(transition module.Transition, opt int) error
 	defer sm.mu.Unlock()
 
 	res := service.FinalizeTransition(transition, opt, false)
-	bk, err := sm.dbase.GetBucket(db.TransactionLocatorByHash)
+	filtered, err := sm.filterCommitted(sm.pool)
 	if err != nil {
 		return err
 	}
-	var filtered []module.Transaction
-	for _, t := range sm.pool {
-		bs, err := bk.Get(t.ID())
-		if bs != nil && err == nil {
-			continue
-		}
-		filtered = append(filtered, t)
-	}
 	sm.pool = filtered
+	if filtered, err = sm.filterCommitted(sm.patches); err != nil {
+		return err
+	}
+	sm.patches = filtered
 	return res
 }
 
@@ -172,7 +189,7 @@ func (sm *ServiceManager) WaitForTransaction(parent module.Transition, bi module
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	if len(sm.pool) > 0 {
+	if len(sm.pool) > 0 || len(sm.patches) > 0 {
 		return false
 	}
 	sm.txWaiters = append(sm.txWaiters, cb)
@@ -267,6 +284,16 @@ func (sm *ServiceManager) getSystemByteStoreState(result []byte) (containerdb.By
 	return scoredb.NewStateStoreWith(ass), nil
 }
 
+// GetVarTest returns the value stored by Transaction.SetVarTest in the
+// system account of the result.
+func (sm *ServiceManager) GetVarTest(result []byte) (string, error) {
+	as, err := sm.getSystemByteStoreState(result)
+	if err != nil {
+		return "", err
+	}
+	return scoredb.NewVarDB(as, VarTest).String(), nil
+}
+
 func (sm *ServiceManager) ImportResult(result []byte, vh []byte, src db.Database) error {
 	panic("implement me")
 }
@@ -318,7 +345,16 @@ func (sm *ServiceManager) SendTransaction(result []byte, height int64, tx interf
 			return nil, errors.Errorf("Already existing TX %x", t.ID())
 		}
 	}
-	sm.pool = append(sm.pool, t)
+	for _, pt := range sm.patches {
+		if bytes.Equal(pt.ID(), t.ID()) {
+			return nil, errors.Errorf("Already existing TX %x", t.ID())
+		}
+	}
+	if t.Group() == module.TransactionGroupPatch {
+		sm.patches = append(sm.patches, t)
+	} else {
+		sm.pool = append(sm.pool, t)
+	}
 	txWaiters := sm.txWaiters
 	sm.txWaiters = nil
 
@@ -56,6 +56,8 @@ type transactionJSON struct {
 	Validators       []*common.Address `json:"validators,omitempty"`
 	NextBlockVersion *common.HexInt32  `json:"nextBlockVersion,omitempty"`
 	VarTest          *string           `json:"varTest,omitempty"`
+	StepUsed         *common.HexInt64  `json:"stepUsed,omitempty"`
+	Patch            bool              `json:"patch,omitempty"`
 	Call             []callJSON        `json:"call"`
 }
 
@@ -125,6 +127,18 @@ func (t *Transaction) SetVarTest(v *string) *Transaction {
 	return t
 }
 
+func (t *Transaction) SetStepUsed(v int64) *Transaction {
+	t.json.StepUsed = &common.HexInt64{Value: v}
+	return t
+}
+
+// SetPatch makes the transaction belong to the patch group, so it is
+// executed before normal transactions of the block.
+func (t *Transaction) SetPatch(v bool) *Transaction {
+	t.json.Patch = v
+	return t
+}
+
 func (t *Transaction) CallFrom(from *common.Address, method string, params map[string]string) *Transaction {
 	paramsStr, err := json.Marshal(params)
 	if err != nil {
@@ -213,7 +227,11 @@ func (t *Transaction) Execute(ctx contract.Context, wcs state.WorldSnapshot, est
 		cc.GetBTPMessages(r)
 	}
 	log.Infof("Execute transaction height=%d tx=%s chainScoreError=%+v", ctx.BlockHeight(), t, chainScoreError)
-	r.SetResult(module.StatusSuccess, big.NewInt(0), big.NewInt(0), nil)
+	stepUsed := big.NewInt(0)
+	if t.json.StepUsed != nil {
+		stepUsed.SetInt64(t.json.StepUsed.Value)
+	}
+	r.SetResult(module.StatusSuccess, stepUsed, big.NewInt(0), nil)
 	return r, nil
 }
 
@@ -221,6 +239,9 @@ func (t *Transaction) Dispose() {
 }
 
 func (t *Transaction) Group() module.TransactionGroup {
+	if t.json.Patch {
+		return module.TransactionGroupPatch
+	}
 	return module.TransactionGroupNormal
 }
 
@@ -272,6 +293,12 @@ func (t *Transaction) ToJSON(version module.JSONVersion) (interface{}, error) {
 	if t.json.VarTest != nil {
 		res["varTest"] = t.json.VarTest
 	}
+	if t.json.StepUsed != nil {
+		res["stepUsed"] = t.json.StepUsed
+	}
+	if t.json.Patch {
+		res["patch"] = t.json.Patch
+	}
 	var calls []interface{}
 	for _, c := range t.json.Call {
 		call := map[string]interface{}{